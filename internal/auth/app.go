@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// appTokenEarlyExpiry は有効期限のどれだけ前にトークンを更新するか
+const appTokenEarlyExpiry = 5 * time.Minute
+
+// appTokenSource はGitHub Appのインストールトークンを発行する
+type appTokenSource struct {
+	ctx            context.Context
+	baseURL        string // REST APIのベースURL
+	appID          int64
+	key            *rsa.PrivateKey
+	installationID int64
+	owner          string // installationID 未指定時の検索に使う
+	http           *http.Client
+}
+
+// NewAppTokenSource はGitHub Appのインストールトークンを自動更新するTokenSourceを作成する
+// host が空の場合は github.com を使用する
+func NewAppTokenSource(ctx context.Context, host string, appID int64, keyPath string, installationID int64, owner string) (oauth2.TokenSource, error) {
+	key, err := loadPrivateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &appTokenSource{
+		ctx:            ctx,
+		baseURL:        config.APIBaseURL(host),
+		appID:          appID,
+		key:            key,
+		installationID: installationID,
+		owner:          owner,
+		http:           &http.Client{Timeout: 30 * time.Second},
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, src, appTokenEarlyExpiry), nil
+}
+
+// Token はインストールトークンを発行する
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.installationID == 0 {
+		id, err := s.findInstallation(jwt)
+		if err != nil {
+			return nil, err
+		}
+		s.installationID = id
+	}
+
+	var resp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.baseURL, s.installationID)
+	if err := s.request(http.MethodPost, url, jwt, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create installation token: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: resp.Token,
+		TokenType:   "token",
+		Expiry:      resp.ExpiresAt,
+	}, nil
+}
+
+// findInstallation はownerに対するAppのインストールIDを検索する
+func (s *appTokenSource) findInstallation(jwt string) (int64, error) {
+	if s.owner == "" {
+		return 0, fmt.Errorf("app_installation_id is required when no project owner is configured")
+	}
+
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	// ユーザーと組織でエンドポイントが異なるため順に試す
+	var lastErr error
+	for _, kind := range []string{"users", "orgs"} {
+		url := fmt.Sprintf("%s/%s/%s/installation", s.baseURL, kind, s.owner)
+		if err := s.request(http.MethodGet, url, jwt, &resp); err != nil {
+			lastErr = err
+			continue
+		}
+		return resp.ID, nil
+	}
+	return 0, fmt.Errorf("app is not installed for %s: %w", s.owner, lastErr)
+}
+
+// jwt はApp認証用のJWT (RS256) を作成する
+func (s *appTokenSource) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		// 時刻のずれを考慮して発行時刻を過去にずらす
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(header + "." + payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (s *appTokenSource) request(method, url, jwt string, out interface{}) error {
+	req, err := http.NewRequestWithContext(s.ctx, method, url, bytes.NewReader(nil))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// loadPrivateKey はPEM形式 (PKCS#1 / PKCS#8) のRSA秘密鍵を読み込む
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key: no PEM block found in %s", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+// DefaultScopes はデバイスフローで要求するスコープ
+var DefaultScopes = []string{"project", "repo", "read:org"}
+
+// DeviceLogin はOAuthデバイスフローでトークンを取得する
+// prompt にはユーザーコードと認証URLが渡されるので、ユーザーに表示する
+// host が空の場合は github.com を使用する
+func DeviceLogin(ctx context.Context, host, clientID string, scopes []string, prompt func(userCode, verificationURI string)) (*oauth2.Token, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("OAuth client ID is required")
+	}
+
+	conf := &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: endpoint(host),
+		Scopes:   scopes,
+	}
+
+	resp, err := conf.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device flow: %w", err)
+	}
+
+	prompt(resp.UserCode, resp.VerificationURI)
+
+	token, err := conf.DeviceAccessToken(ctx, resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	return token, nil
+}
+
+// endpoint はホストに対応するOAuthエンドポイントを返す
+func endpoint(host string) oauth2.Endpoint {
+	if host == "" || host == config.DefaultHost {
+		return endpoints.GitHub
+	}
+	base := "https://" + host
+	return oauth2.Endpoint{
+		AuthURL:       base + "/login/oauth/authorize",
+		TokenURL:      base + "/login/oauth/access_token",
+		DeviceAuthURL: base + "/login/device/code",
+	}
+}
@@ -0,0 +1,8 @@
+package auth
+
+// キーリングのエントリ識別子
+const (
+	keyringService = "vibe"
+	keyringAccount = "github" // プロファイル使用時は github:<profile>
+	keyringLabel   = "vibe GitHub token"
+)
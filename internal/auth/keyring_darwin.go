@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keyring はmacOSのキーチェーンにトークンを保存する
+type keyring struct {
+	account string
+}
+
+// KeyringAvailable はキーチェーンが利用可能かどうかを返す
+func KeyringAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func (k keyring) Get() (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keyringService, "-a", k.account, "-w").Output()
+	if err != nil {
+		// 見つからない場合は終了コード44
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read keychain: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (k keyring) Set(token string) error {
+	// -w の値として引数に渡すと ps などでトークンが見えるため、対話モード（-i）で標準入力からコマンドを渡す
+	// -U で既存のエントリを更新する
+	if strings.ContainsAny(token, "\r\n") {
+		return fmt.Errorf("failed to write keychain: token must not contain line breaks")
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+		quoteCommandArg(keyringService), quoteCommandArg(k.account), quoteCommandArg(keyringLabel), quoteCommandArg(token))
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to write keychain: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	// 対話モードはコマンドが失敗しても終了コードが 0 になることがあるため、読み戻して確認する
+	if saved, err := k.Get(); err != nil || saved != token {
+		return fmt.Errorf("failed to write keychain: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// quoteCommandArg は security の対話モードのコマンドの引数をダブルクォートで囲む
+func quoteCommandArg(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (k keyring) Delete() error {
+	out, err := exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", k.account).CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+			return nil
+		}
+		return fmt.Errorf("failed to delete from keychain: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// keyring はSecret Service (GNOME Keyring / KWallet) にトークンを保存する
+// libsecret の secret-tool を使用する
+type keyring struct {
+	account string
+}
+
+func (k keyring) attrs() []string {
+	return []string{"service", keyringService, "account", k.account}
+}
+
+// KeyringAvailable はSecret Serviceが利用可能かどうかを返す
+func KeyringAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (k keyring) Get() (string, error) {
+	out, err := exec.Command("secret-tool", append([]string{"lookup"}, k.attrs()...)...).Output()
+	if err != nil {
+		// 見つからない場合は終了コード1で何も出力しない
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read keyring: %w", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
+
+func (k keyring) Set(token string) error {
+	args := append([]string{"store", "--label=" + keyringLabel}, k.attrs()...)
+	cmd := exec.Command("secret-tool", args...)
+	// トークンはコマンドライン引数に出さず標準入力で渡す
+	cmd.Stdin = strings.NewReader(token)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write keyring: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (k keyring) Delete() error {
+	if out, err := exec.Command("secret-tool", append([]string{"clear"}, k.attrs()...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete from keyring: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+//go:build !linux && !darwin
+
+package auth
+
+import "fmt"
+
+// keyring はこのOSでは未対応（暗号化ファイルを使用する）
+type keyring struct {
+	account string
+}
+
+// KeyringAvailable はこのOSでは常に false を返す
+func KeyringAvailable() bool {
+	return false
+}
+
+func (keyring) Get() (string, error) {
+	return "", fmt.Errorf("OS keyring is not supported on this platform")
+}
+
+func (keyring) Set(token string) error {
+	return fmt.Errorf("OS keyring is not supported on this platform")
+}
+
+func (keyring) Delete() error {
+	return nil
+}
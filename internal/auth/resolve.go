@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+// トークンの取得元
+const (
+	SourceKeyring = "keyring"
+	SourceFile    = "file"
+	SourceConfig  = "config" // ~/.vibe/config.json の github_token（平文）
+	SourceGH      = "gh"     // gh auth token
+)
+
+// TokenEnvVars はトークンを読み込む環境変数（優先順）
+var TokenEnvVars = []string{"VIBE_GITHUB_TOKEN", "GH_TOKEN", "GITHUB_TOKEN"}
+
+// Resolve はトークンを取得し cfg.Auth.Token と cfg.Auth.TokenSource に設定する
+//
+// 優先順位:
+//  1. 環境変数（プロファイルの token_env > VIBE_GITHUB_TOKEN / GH_TOKEN / GITHUB_TOKEN）
+//  2. vibe auth login の保存先（キーリング / 暗号化ファイル）
+//  3. config.json の github_token（旧形式）
+//  4. gh auth token（GitHub App 認証時は使用しない）
+//
+// トークンが見つからなくてもエラーにはしない（保存先の読み込みに失敗した場合のみ返す）
+func Resolve(cfg *config.Config) error {
+	envVars := TokenEnvVars
+	if cfg.Auth.TokenEnv != "" {
+		envVars = append([]string{cfg.Auth.TokenEnv}, envVars...)
+	}
+	for _, name := range envVars {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			setToken(cfg, v, "env:"+name)
+			return nil
+		}
+	}
+
+	var storeErr error
+	if cfg.Auth.TokenStore != "" {
+		token, err := readStore(cfg.Auth.TokenStore, cfg.Profile())
+		switch {
+		case err == nil:
+			setToken(cfg, token, cfg.Auth.TokenStore)
+			return nil
+		case !errors.Is(err, ErrNotFound):
+			storeErr = fmt.Errorf("failed to read token from %s: %w", cfg.Auth.TokenStore, err)
+		}
+	}
+
+	if cfg.GitHubToken != "" {
+		setToken(cfg, cfg.GitHubToken, SourceConfig)
+		return storeErr
+	}
+
+	if cfg.AuthMethod() != config.AuthMethodApp {
+		if token, err := ghToken(cfg.GitHubHost()); err == nil && token != "" {
+			setToken(cfg, token, SourceGH)
+		}
+	}
+	return storeErr
+}
+
+// FromEnv はトークンが環境変数から取得されたかどうかを返す
+func FromEnv(cfg *config.Config) bool {
+	return strings.HasPrefix(cfg.Auth.TokenSource, "env:")
+}
+
+// Save はトークンを保存先に書き込み、設定ファイルから平文のトークンを削除する
+// storeName が空の場合は利用可能な保存先を自動で選ぶ
+func Save(cfg *config.Config, token, storeName string) error {
+	if storeName == "" {
+		storeName = DefaultStore()
+	}
+	store, err := NewStore(storeName, cfg.Profile())
+	if err != nil {
+		return err
+	}
+	if err := store.Set(token); err != nil {
+		return err
+	}
+
+	cfg.GitHubToken = ""
+	cfg.Auth.TokenStore = storeName
+	setToken(cfg, token, storeName)
+	return nil
+}
+
+// Delete は保存先からトークンを削除する
+func Delete(cfg *config.Config) error {
+	if cfg.Auth.TokenStore == "" {
+		return nil
+	}
+	store, err := NewStore(cfg.Auth.TokenStore, cfg.Profile())
+	if err != nil {
+		return err
+	}
+	return store.Delete()
+}
+
+func readStore(name, profile string) (string, error) {
+	store, err := NewStore(name, profile)
+	if err != nil {
+		return "", err
+	}
+	return store.Get()
+}
+
+func setToken(cfg *config.Config, token, source string) {
+	cfg.Auth.Token = token
+	cfg.Auth.TokenSource = source
+}
+
+// ghToken は GitHub CLI のトークンを取得する
+func ghToken(host string) (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", err
+	}
+	out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// TokenSource は設定された認証方式に応じたTokenSourceを返す
+// owner はGitHub Appのインストール検索に使用する
+// 環境変数でトークンが指定されている場合は認証方式に関係なくそれを使う
+func TokenSource(ctx context.Context, cfg *config.Config, owner string) (oauth2.TokenSource, error) {
+	method := cfg.AuthMethod()
+	if FromEnv(cfg) {
+		method = config.AuthMethodToken
+	}
+
+	switch method {
+	case config.AuthMethodApp:
+		return NewAppTokenSource(ctx, cfg.Host, cfg.Auth.AppID, cfg.Auth.AppPrivateKeyPath, cfg.Auth.AppInstallationID, owner)
+	case config.AuthMethodToken, config.AuthMethodOAuth:
+		token := cfg.Token()
+		if token == "" {
+			return nil, fmt.Errorf("not logged in. Run: vibe auth login")
+		}
+		if method == config.AuthMethodOAuth && !cfg.Auth.TokenExpiry.IsZero() && time.Now().After(cfg.Auth.TokenExpiry) {
+			return nil, fmt.Errorf("token expired at %s. Run: vibe auth login --device", cfg.Auth.TokenExpiry.Format(time.RFC3339))
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	default:
+		return nil, fmt.Errorf("unknown auth method: %s", cfg.Auth.Method)
+	}
+}
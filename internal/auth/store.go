@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+// ErrNotFound はトークンが保存されていないことを示す
+var ErrNotFound = errors.New("token not found")
+
+// Store はトークンの保存先
+type Store interface {
+	Get() (string, error)
+	Set(token string) error
+	Delete() error
+}
+
+// NewStore は保存先の名前 (keyring / file) からStoreを作成する
+// profile ごとに別のエントリに保存する（空ならデフォルト）
+func NewStore(name, profile string) (Store, error) {
+	switch name {
+	case config.TokenStoreKeyring:
+		if !KeyringAvailable() {
+			return nil, fmt.Errorf("OS keyring is not available")
+		}
+		account := keyringAccount
+		if profile != "" {
+			account += ":" + profile
+		}
+		return keyring{account: account}, nil
+	case config.TokenStoreFile:
+		dir, err := config.Dir()
+		if err != nil {
+			return nil, err
+		}
+		prefix := "token"
+		if profile != "" {
+			prefix += "-" + profile
+		}
+		return &fileStore{dir: dir, prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("unknown token store: %s", name)
+	}
+}
+
+// DefaultStore は利用可能な保存先を返す（キーリングを優先し、なければ暗号化ファイル）
+func DefaultStore() string {
+	if KeyringAvailable() {
+		return config.TokenStoreKeyring
+	}
+	return config.TokenStoreFile
+}
+
+// PassphraseEnv は暗号化ファイルの鍵を導出するパスフレーズを読み込む環境変数
+const PassphraseEnv = "VIBE_TOKEN_PASSPHRASE"
+
+// 暗号化ファイルの鍵導出のパラメータ（PBKDF2-HMAC-SHA256）
+const (
+	saltSize   = 16
+	kdfIter    = 600000
+	kdfKeySize = 32
+)
+
+// fileStore はトークンを暗号化してファイルに保存する（キーリングがない環境向け）
+// 鍵は PassphraseEnv のパスフレーズからPBKDF2で導出し、ファイルには保存しない。
+// ファイルの先頭にソルトとnonceを置き、AES-GCMで暗号化する
+type fileStore struct {
+	dir    string
+	prefix string // token / token-<profile>
+}
+
+func (s *fileStore) tokenPath() string { return filepath.Join(s.dir, s.prefix+".enc") }
+
+// legacyKeyPath は以前のバージョンがトークンの隣に保存していた鍵のパス
+func (s *fileStore) legacyKeyPath() string { return filepath.Join(s.dir, s.prefix+".key") }
+
+// Get は暗号化ファイルからトークンを読み込む
+func (s *fileStore) Get() (string, error) {
+	data, err := os.ReadFile(s.tokenPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if _, err := os.Stat(s.legacyKeyPath()); err == nil {
+		return "", fmt.Errorf("%s was written by an older version with its key next to it; run vibe auth login again", s.tokenPath())
+	}
+
+	passphrase, err := filePassphrase()
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) < saltSize {
+		return "", fmt.Errorf("failed to decode token file: %s is corrupted", s.tokenPath())
+	}
+	salt, sealed := raw[:saltSize], raw[saltSize:]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("failed to decode token file: %s is corrupted", s.tokenPath())
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token: wrong %s or corrupted %s", PassphraseEnv, s.tokenPath())
+	}
+	return string(plain), nil
+}
+
+// Set はトークンを暗号化して保存する（ソルトとnonceは毎回作り直す）
+func (s *fileStore) Set(token string) error {
+	passphrase, err := filePassphrase()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(append(salt, nonce...), nonce, []byte(token), nil)
+
+	if err := os.WriteFile(s.tokenPath(), []byte(base64.StdEncoding.EncodeToString(sealed)), 0600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	// 以前のバージョンの鍵は不要になる
+	if err := os.Remove(s.legacyKeyPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", s.legacyKeyPath(), err)
+	}
+	return nil
+}
+
+// Delete は暗号化ファイル（と以前のバージョンの鍵）を削除する
+func (s *fileStore) Delete() error {
+	for _, p := range []string{s.tokenPath(), s.legacyKeyPath()} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// filePassphrase は PassphraseEnv のパスフレーズを返す（未設定ならエラー）
+func filePassphrase() (string, error) {
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return "", fmt.Errorf("%s is not set: set it to encrypt the token file, or use the OS keyring or VIBE_GITHUB_TOKEN", PassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+// newGCM はパスフレーズとソルトから鍵を導出してAES-GCMを作成する
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIter, kdfKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive token key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,40 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// checklistInstruction は受け入れ基準の達成状況を報告させるためにシステムプロンプトに追加する指示
+func checklistInstruction(items []domain.ChecklistItem) string {
+	var b strings.Builder
+	b.WriteString("The issue lists these acceptance criteria:\n")
+	for _, item := range items {
+		fmt.Fprintf(&b, "%d. %s", item.Number, item.Text)
+		if item.Checked {
+			b.WriteString(" (already checked)")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nWhen you have finished, end your final reply with a line \"" + domain.ChecklistMarker + "\" " +
+		"followed by one line per criterion in the form \"<number>. done\" or \"<number>. not done - <reason>\". " +
+		"Report a criterion as done only if your changes satisfy it. " +
+		"Leave the report out if you stop to ask a question.")
+	return b.String()
+}
+
+// reportChecklist は opt.Checklist があり、成功した実行の出力に達成状況の報告があれば
+// Execution.Checklist に記録し、出力から報告を取り除く
+func reportChecklist(opt *ExecuteOption, execution *domain.Execution) {
+	if len(opt.Checklist) == 0 || !execution.Success {
+		return
+	}
+	items, output := domain.ApplyChecklistReport(opt.Checklist, execution.Output)
+	if output == execution.Output {
+		return
+	}
+	execution.Checklist = items
+	execution.Output = output
+}
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/hook"
 )
 
 // Executor はClaude Codeを実行する
@@ -29,8 +32,32 @@ type ExecuteOption struct {
 	DryRun    bool          // 実行せず確認のみ
 	Timeout   time.Duration // タイムアウト
 	SessionID string        // 継続するセッションID
+	Prompt    string        // task.Prompt の代わりに送るプロンプト（リトライ時の継続指示など）
+	Hooks     *hook.Runner  // 実行前後のフック（ExecuteWithRetry で実行する）
+	Output    io.Writer     // 実行中の出力を書き込む先（指定すると stream-json で逐次出力する）
+	AddDirs   []string      // 作業ディレクトリ以外にClaude Codeが読めるディレクトリ（添付ファイルなど）
+	NoTools   bool          // ツールを許可せず、書き込み・コマンド実行・Webアクセスを拒否する（要約など）
+
+	DetectQuestions bool                   // 最終結果が質問なら Execution.Question に記録し、検証を行わずに終える
+	Checklist       []domain.ChecklistItem // 受け入れ基準（達成状況の報告を指示し、Execution.Checklist に記録する）
+
+	Verify        *hook.Runner                        // 実行後の検証コマンド（ExecuteWithRetry で実行する）
+	MaxIterations int                                 // 検証ループでの初回を含むClaude Codeの最大実行回数
+	CheckBudget   func(exec *domain.Execution) string // 予算を超えていれば理由を返す（超えたら検証ループを打ち切る）
+
+	Log io.Writer // リトライ・検証・フックの進捗を1行ずつ書き込む先（nil なら書き込まない）
+}
+
+// logf は進捗メッセージを1行 opt.Log に書き込む
+func (o *ExecuteOption) logf(format string, args ...any) {
+	if o.Log != nil {
+		fmt.Fprintf(o.Log, format+"\n", args...)
+	}
 }
 
+// writeTools は NoTools の実行で拒否するツール（ユーザーの設定で許可されていても使わせない）
+var writeTools = []string{"Bash", "Edit", "MultiEdit", "Write", "NotebookEdit", "WebFetch", "WebSearch", "Task"}
+
 // DefaultTimeout はデフォルトのタイムアウト時間
 const DefaultTimeout = 30 * time.Minute
 
@@ -39,9 +66,7 @@ func (e *Executor) Execute(ctx context.Context, task *domain.Task, opt *ExecuteO
 	if opt == nil {
 		opt = &ExecuteOption{}
 	}
-	if opt.Timeout == 0 {
-		opt.Timeout = DefaultTimeout
-	}
+	opt.Timeout = timeoutOrDefault(opt.Timeout)
 
 	execution := &domain.Execution{
 		TaskID:    task.ID,
@@ -71,8 +96,17 @@ func (e *Executor) Execute(ctx context.Context, task *domain.Task, opt *ExecuteO
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	var stream *streamWriter
+	if opt.Output != nil {
+		stream = newStreamWriter(&stdout, opt.Output)
+		cmd.Stdout = stream
+		cmd.Stderr = io.MultiWriter(&stderr, opt.Output)
+	}
 
 	err := cmd.Run()
+	if stream != nil {
+		stream.Flush()
+	}
 
 	execution.EndedAt = time.Now()
 	execution.Duration = execution.EndedAt.Sub(execution.StartedAt)
@@ -80,33 +114,135 @@ func (e *Executor) Execute(ctx context.Context, task *domain.Task, opt *ExecuteO
 	if err != nil {
 		execution.Success = false
 		execution.Error = formatError(err, stderr.String())
+		execution.TimedOut = ctx.Err() == context.DeadlineExceeded
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			execution.ExitCode = exitErr.ExitCode()
+		}
 		execution.Output = stdout.String()
+		// 失敗時もコストは発生しているため記録する
+		if result, ok := parseResult(stdout.Bytes()); ok {
+			result.apply(execution)
+		}
 		return execution, nil // エラーは返さない（Failedとして処理）
 	}
 
 	execution.Success = true
 	execution.Output = stdout.String()
 
-	// セッションIDを抽出（あれば）
-	execution.SessionID = e.extractSessionID(stdout.String())
+	// JSON出力から結果・コストを取得（パースできなければ生の出力を使う）
+	if result, ok := parseResult(stdout.Bytes()); ok {
+		result.apply(execution)
+		if result.IsError {
+			execution.Success = false
+			execution.Error = fmt.Sprintf("claude reported %s: %s", result.Subtype, result.Result)
+		}
+	} else {
+		execution.SessionID = e.extractSessionID(stdout.String())
+	}
 
 	return execution, nil
 }
 
+// result は --output-format json の出力
+type result struct {
+	Type         string  `json:"type"`
+	Subtype      string  `json:"subtype"`
+	IsError      bool    `json:"is_error"`
+	Result       string  `json:"result"`
+	SessionID    string  `json:"session_id"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	NumTurns     int     `json:"num_turns"`
+	Usage        struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+}
+
+// parseResult はClaude CodeのJSON出力をパースする
+// stream-json の場合は最後の result イベントを使う
+func parseResult(data []byte) (*result, bool) {
+	var r result
+	if err := json.Unmarshal(bytes.TrimSpace(data), &r); err == nil {
+		if r.Type != "result" {
+			return nil, false
+		}
+		return &r, true
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var r result
+		if err := json.Unmarshal(lines[i], &r); err == nil && r.Type == "result" {
+			return &r, true
+		}
+	}
+	return nil, false
+}
+
+// apply はJSON出力の内容を実行結果に反映する
+func (r *result) apply(execution *domain.Execution) {
+	execution.Output = r.Result
+	execution.SessionID = r.SessionID
+	execution.CostUSD = r.TotalCostUSD
+	execution.NumTurns = r.NumTurns
+	execution.InputTokens = r.Usage.InputTokens + r.Usage.CacheCreationInputTokens + r.Usage.CacheReadInputTokens
+	execution.OutputTokens = r.Usage.OutputTokens
+}
+
 func (e *Executor) buildArgs(task *domain.Task, opt *ExecuteOption) []string {
 	args := []string{
-		"--print", // 非対話モード
+		"--print",                 // 非対話モード
+		"--output-format", "json", // コスト・セッションIDを取得するためJSONで出力
+	}
+	if opt.Output != nil {
+		// 実行中の出力を逐次受け取る（最後の result イベントは json と同じ形式）
+		args = []string{"--print", "--output-format", "stream-json", "--verbose"}
+	}
+
+	// --add-dir・--allowedTools は複数の値を取るため、プロンプトを値として読まないよう先頭に置く
+	var leading []string
+	for _, dir := range opt.AddDirs {
+		leading = append(leading, "--add-dir", dir)
+	}
+	switch {
+	case opt.NoTools:
+		leading = append(leading, "--allowedTools", "", "--disallowedTools", strings.Join(writeTools, ","))
+	case len(task.AllowedTools) > 0:
+		leading = append(leading, "--allowedTools", strings.Join(task.AllowedTools, ","))
+	}
+	args = append(leading, args...)
+
+	// Issueフォームで指定したモデル
+	if task.Model != "" {
+		args = append(args, "--model", task.Model)
 	}
 
-	// セッション継続
+	// セッション継続（明示的に指定した場合のみ。task.SessionID は前回の実行の記録）
 	if opt.SessionID != "" {
 		args = append(args, "--resume", opt.SessionID)
-	} else if task.SessionID != "" {
-		args = append(args, "--resume", task.SessionID)
+	}
+
+	// 入力が必要な場合に質問で終えるよう、受け入れ基準があれば達成状況を報告するよう指示する
+	var instructions []string
+	if opt.DetectQuestions {
+		instructions = append(instructions, questionInstruction)
+	}
+	if len(opt.Checklist) > 0 {
+		instructions = append(instructions, checklistInstruction(opt.Checklist))
+	}
+	if len(instructions) > 0 {
+		args = append(args, "--append-system-prompt", strings.Join(instructions, "\n\n"))
 	}
 
 	// プロンプトを追加
-	args = append(args, task.Prompt)
+	prompt := task.Prompt
+	if opt.Prompt != "" {
+		prompt = opt.Prompt
+	}
+	args = append(args, prompt)
 
 	return args
 }
@@ -152,3 +288,12 @@ func (e *Executor) CheckInstalled() error {
 	}
 	return nil
 }
+
+// Version はclaude コマンドのバージョンを返す
+func (e *Executor) Version() (string, error) {
+	out, err := exec.Command(e.claudePath, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("claude command not found at %s: %w", e.claudePath, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,66 @@
+package claude
+
+import (
+	"regexp"
+	"strings"
+)
+
+// questionMarker はClaude Codeが追加の入力を求めるときに回答の最後に書く行の接頭辞
+const questionMarker = "NEEDS INPUT:"
+
+// questionInstruction は質問を検出するためにシステムプロンプトに追加する指示
+const questionInstruction = `You are running non-interactively and nobody can answer you during this run. ` +
+	`If you cannot complete the task without more information or a decision from the user, ` +
+	`stop and end your final reply with a line starting with "` + questionMarker + `" followed by your questions. ` +
+	`Your questions will be posted to the GitHub issue and the session will be resumed with the answer.`
+
+// offerPattern は作業を終えた後の申し出（質問ではない締めくくり）に一致する
+var offerPattern = regexp.MustCompile(`(?i)\b(anything else|would you like me to|do you want me to|shall i|want me to|let me know if)\b`)
+
+// DetectQuestion はClaude Codeの最終結果が質問（追加の入力の依頼）であればその質問を返す
+// questionMarker で始まる行があればそれ以降を、なければ最後の段落が「?」で終わる場合に
+// その段落を質問とする。作業後の「他に何かありますか？」のような申し出は質問としない
+func DetectQuestion(output string) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return ""
+	}
+
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimLeft(lines[i], " *_#>")
+		if len(line) >= len(questionMarker) && strings.EqualFold(line[:len(questionMarker)], questionMarker) {
+			rest := strings.TrimLeft(line[len(questionMarker):], " *_")
+			question := strings.TrimSpace(strings.Join(append([]string{rest}, lines[i+1:]...), "\n"))
+			if question == "" {
+				// マーカーだけの行: 直前の段落を質問とする
+				return lastParagraph(strings.Join(lines[:i], "\n"))
+			}
+			return question
+		}
+	}
+
+	last := lastParagraph(output)
+	if !strings.HasSuffix(last, "?") && !strings.HasSuffix(last, "？") {
+		return ""
+	}
+	if offerPattern.MatchString(last) {
+		return ""
+	}
+
+	// 「次の点を確認させてください:」のような前置きの段落も含める
+	rest := strings.TrimSpace(strings.TrimSuffix(output, last))
+	if intro := lastParagraph(rest); strings.HasSuffix(intro, ":") || strings.HasSuffix(intro, "：") {
+		return intro + "\n\n" + last
+	}
+	return last
+}
+
+// lastParagraph は空行で区切られた最後の段落を返す
+func lastParagraph(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "\n\n"); i >= 0 {
+		return strings.TrimSpace(s[i:])
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package claude
+
+import "testing"
+
+func TestDetectQuestion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "empty output",
+			output: "  \n",
+		},
+		{
+			name:   "completed work",
+			output: "I added the endpoint and the tests pass.",
+		},
+		{
+			name:   "marker line",
+			output: "I looked at the code.\n\nNEEDS INPUT: Should the endpoint require authentication?",
+			want:   "Should the endpoint require authentication?",
+		},
+		{
+			name:   "marker with following lines",
+			output: "Two options exist.\n\n**NEEDS INPUT:** Which one do you prefer?\n1. Redis\n2. Memcached",
+			want:   "Which one do you prefer?\n1. Redis\n2. Memcached",
+		},
+		{
+			name:   "lowercase marker",
+			output: "needs input: Which database?",
+			want:   "Which database?",
+		},
+		{
+			name:   "marker alone uses the previous paragraph",
+			output: "Done with the setup.\n\nWhich region should I deploy to?\n\nNEEDS INPUT:",
+			want:   "Which region should I deploy to?",
+		},
+		{
+			name:   "last paragraph ends with a question mark",
+			output: "I found two config files.\n\nWhich one should I update?",
+			want:   "Which one should I update?",
+		},
+		{
+			name:   "full-width question mark",
+			output: "設定ファイルが2つあります。\n\nどちらを更新しますか？",
+			want:   "どちらを更新しますか？",
+		},
+		{
+			name:   "introductory paragraph is included",
+			output: "I stopped before migrating.\n\nPlease confirm the following:\n\n- Can I drop the old table?",
+			want:   "Please confirm the following:\n\n- Can I drop the old table?",
+		},
+		{
+			name:   "closing offer is not a question",
+			output: "I fixed the bug and added a test.\n\nWould you like me to open a pull request?",
+		},
+		{
+			name:   "let me know offer is not a question",
+			output: "All done.\n\nLet me know if you need anything else?",
+		},
+		{
+			name:   "question mark in an earlier paragraph",
+			output: "Why did this fail? The cache was stale.\n\nI cleared it and the build passes.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectQuestion(tt.output); got != tt.want {
+				t.Errorf("DetectQuestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/hook"
+)
+
+// RetryPolicy は失敗した実行をリトライする条件
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	rules       []retryRule
+}
+
+type retryRule struct {
+	name      string
+	exitCodes []int
+	pattern   *regexp.Regexp
+	timeout   bool
+}
+
+// resumePrompt はリトライでセッションを再開するときのプロンプト
+const resumePrompt = "The previous run was interrupted by a transient error (%s). Continue the task from where you left off."
+
+// NewRetryPolicy は設定からRetryPolicyを作成する
+func NewRetryPolicy(cfg config.RetryConfig) (*RetryPolicy, error) {
+	p := &RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.Backoff.Duration,
+		MaxBackoff:  cfg.MaxBackoff.Duration,
+	}
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.Backoff == 0 {
+		p.Backoff = config.DefaultRetryBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = config.DefaultRetryMaxBackoff
+	}
+
+	rules := cfg.Retryable
+	if len(rules) == 0 {
+		rules = config.DefaultRetryRules
+	}
+	for _, r := range rules {
+		rule := retryRule{
+			name:      r.Name,
+			exitCodes: r.ExitCodes,
+			timeout:   r.Timeout,
+		}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid retry pattern for %s: %w", r.Name, err)
+			}
+			rule.pattern = re
+		}
+		p.rules = append(p.rules, rule)
+	}
+
+	return p, nil
+}
+
+// Classify は失敗した実行がリトライ対象であればエラー分類名を返す
+func (p *RetryPolicy) Classify(exec *domain.Execution) (string, bool) {
+	if exec.Success {
+		return "", false
+	}
+	for _, r := range p.rules {
+		if r.timeout && exec.TimedOut {
+			return r.name, true
+		}
+		for _, code := range r.exitCodes {
+			if exec.ExitCode == code {
+				return r.name, true
+			}
+		}
+		if r.pattern != nil && r.pattern.MatchString(exec.Error) {
+			return r.name, true
+		}
+	}
+	return "", false
+}
+
+// delay は attempt 回目の失敗後の待機時間を返す
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// ExecuteWithRetry はリトライポリシーに従ってタスクを実行する
+// リトライ時は前回のセッションを --resume で再開する。
+// 返り値の Execution のコスト・トークン・実行時間は全試行の合計（待機時間を除く）
+//
+// opt.Verify があれば、Claude Codeの実行後に検証コマンドを実行し、失敗した場合は
+// 出力を添えて同じセッションを再開する（executeVerified）
+//
+// opt.Hooks があれば、最初の試行の前に pre_run、検証の後に post_run と
+// on_success / on_failure を実行する。pre_run が失敗した場合はClaude Codeを実行せず、
+// post_run が失敗した場合はタスクを失敗にする。質問で終えた場合は on_success / on_failure を実行しない
+func (e *Executor) ExecuteWithRetry(ctx context.Context, task *domain.Task, opt *ExecuteOption, policy *RetryPolicy) (*domain.Execution, error) {
+	if opt == nil {
+		opt = &ExecuteOption{}
+	}
+	if opt.DryRun {
+		return e.executeAttempts(ctx, task, opt, policy)
+	}
+	if opt.Hooks == nil {
+		return e.executeVerified(ctx, task, opt, policy)
+	}
+
+	var execution *domain.Execution
+	pre, ok := opt.Hooks.Run(ctx, hook.PreRun, task, nil, opt.Log)
+	if ok {
+		var err error
+		execution, err = e.executeVerified(ctx, task, opt, policy)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		failed := pre[len(pre)-1]
+		now := time.Now()
+		execution = &domain.Execution{
+			TaskID:    task.ID,
+			Error:     fmt.Sprintf("pre_run hook failed: %s (%s)", failed.Command, hook.Describe(failed)),
+			StartedAt: now,
+			EndedAt:   now,
+		}
+	}
+	execution.Hooks = append(execution.Hooks, pre...)
+
+	// post_run は後片付けにも使えるよう、pre_run やClaude Codeが失敗しても実行する
+	post, ok := opt.Hooks.Run(ctx, hook.PostRun, task, execution, opt.Log)
+	execution.Hooks = append(execution.Hooks, post...)
+	if !ok && execution.Success {
+		failed := post[len(post)-1]
+		execution.Success = false
+		execution.Error = fmt.Sprintf("post_run hook failed: %s (%s)", failed.Command, hook.Describe(failed))
+	}
+
+	// 質問で終えた実行は返信を待っているため、完了・失敗のどちらのフックも実行しない
+	if execution.NeedsInput() {
+		return execution, nil
+	}
+	stage := hook.OnSuccess
+	if !execution.Success {
+		stage = hook.OnFailure
+	}
+	// on_success / on_failure の失敗はタスクの結果に影響しない
+	results, _ := opt.Hooks.Run(ctx, stage, task, execution, opt.Log)
+	execution.Hooks = append(execution.Hooks, results...)
+	return execution, nil
+}
+
+// executeAttempts はリトライポリシーに従ってClaude Codeを実行する
+// リトライの前に opt.CheckBudget で予算を確認し、超えていればリトライしない
+// opt.Timeout は全試行（待機時間を含む）の合計の上限で、各試行には残りの時間を使う
+func (e *Executor) executeAttempts(ctx context.Context, task *domain.Task, opt *ExecuteOption, policy *RetryPolicy) (*domain.Execution, error) {
+	if policy == nil || opt.DryRun {
+		execution, err := e.Execute(ctx, task, opt)
+		if execution != nil {
+			execution.Attempts = 1
+		}
+		return execution, err
+	}
+
+	attemptOpt := *opt
+	deadline := time.Now().Add(timeoutOrDefault(opt.Timeout))
+	var total *domain.Execution
+	for attempt := 1; ; attempt++ {
+		execution, err := e.Execute(ctx, task, &attemptOpt)
+		if err != nil {
+			return nil, err
+		}
+
+		if total == nil {
+			total = execution
+		} else {
+			mergeAttempt(total, execution)
+		}
+		total.Attempts = attempt
+
+		class, retryable := policy.Classify(execution)
+		total.ErrorClass = class
+		if !retryable || attempt >= policy.MaxAttempts {
+			return total, nil
+		}
+		if opt.CheckBudget != nil {
+			if reason := opt.CheckBudget(total); reason != "" {
+				opt.logf("⚠️  Retryable error (%s), but the per-task budget is exhausted (%s)", class, reason)
+				return total, nil
+			}
+		}
+
+		wait := policy.delay(attempt)
+		remaining := time.Until(deadline) - wait
+		if remaining <= 0 {
+			opt.logf("⚠️  Retryable error (%s), but the task timeout leaves no time to retry", class)
+			return total, nil
+		}
+		attemptOpt.Timeout = remaining
+		opt.logf("🔁 Retryable error (%s), retrying in %s (attempt %d/%d)", class, wait, attempt+1, policy.MaxAttempts)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return total, nil
+		}
+
+		// セッションが作られていれば再開し、なければ最初からやり直す
+		if execution.SessionID != "" {
+			attemptOpt.SessionID = execution.SessionID
+			attemptOpt.Prompt = fmt.Sprintf(resumePrompt, class)
+		}
+	}
+}
+
+// timeoutOrDefault は未指定（0）なら DefaultTimeout を返す
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return DefaultTimeout
+	}
+	return timeout
+}
+
+// mergeAttempt は最新の試行結果を合計に反映する
+func mergeAttempt(total, latest *domain.Execution) {
+	total.Success = latest.Success
+	total.Output = latest.Output
+	total.Error = latest.Error
+	total.ExitCode = latest.ExitCode
+	total.TimedOut = latest.TimedOut
+	total.Question = latest.Question
+	if latest.Checklist != nil {
+		total.Checklist = latest.Checklist
+	}
+	if latest.SessionID != "" {
+		total.SessionID = latest.SessionID
+	}
+	total.EndedAt = latest.EndedAt
+	// 待機時間は含めず実行時間のみを合計する
+	total.Duration += latest.Duration
+	total.CostUSD += latest.CostUSD
+	total.InputTokens += latest.InputTokens
+	total.OutputTokens += latest.OutputTokens
+	total.NumTurns += latest.NumTurns
+}
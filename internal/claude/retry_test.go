@@ -0,0 +1,229 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+func TestClassify(t *testing.T) {
+	policy, err := NewRetryPolicy(config.RetryConfig{
+		MaxAttempts: 3,
+		Retryable: []config.RetryRule{
+			{Name: "crash", ExitCodes: []int{137, 139}},
+			{Name: "rate_limit", Pattern: `(?i)rate.?limit|\b429\b`},
+			{Name: "timeout", Timeout: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		exec      domain.Execution
+		wantClass string
+		wantRetry bool
+	}{
+		{name: "success is never retried", exec: domain.Execution{Success: true, ExitCode: 137, Error: "rate limit"}},
+		{name: "exit code", exec: domain.Execution{ExitCode: 139, Error: "signal: segmentation fault"}, wantClass: "crash", wantRetry: true},
+		{name: "stderr pattern", exec: domain.Execution{ExitCode: 1, Error: "exit status 1: API Error: Rate limit reached"}, wantClass: "rate_limit", wantRetry: true},
+		{name: "status code in stderr", exec: domain.Execution{ExitCode: 1, Error: "request failed with 429"}, wantClass: "rate_limit", wantRetry: true},
+		{name: "timeout", exec: domain.Execution{TimedOut: true, Error: "signal: killed"}, wantClass: "timeout", wantRetry: true},
+		{name: "first matching rule wins", exec: domain.Execution{ExitCode: 137, Error: "rate limit", TimedOut: true}, wantClass: "crash", wantRetry: true},
+		{name: "unmatched error", exec: domain.Execution{ExitCode: 1, Error: "exit status 1: invalid prompt"}},
+		{name: "pattern is not matched against the output", exec: domain.Execution{ExitCode: 1, Output: "rate limit", Error: "exit status 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, retry := policy.Classify(&tt.exec)
+			if class != tt.wantClass || retry != tt.wantRetry {
+				t.Errorf("Classify() = %q, %v, want %q, %v", class, retry, tt.wantClass, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryRules(t *testing.T) {
+	policy, err := NewRetryPolicy(config.RetryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.MaxAttempts != 1 || policy.Backoff != config.DefaultRetryBackoff || policy.MaxBackoff != config.DefaultRetryMaxBackoff {
+		t.Errorf("defaults = %d, %s, %s", policy.MaxAttempts, policy.Backoff, policy.MaxBackoff)
+	}
+
+	tests := map[string]string{
+		"exit status 1: 529 overloaded":                "rate_limit",
+		"exit status 1: Error: connect ECONNREFUSED":   "network",
+		"exit status 1: TypeError: fetch failed":       "network",
+		"exit status 1: Too Many Requests, slow down!": "rate_limit",
+	}
+	for message, want := range tests {
+		if class, _ := policy.Classify(&domain.Execution{Error: message}); class != want {
+			t.Errorf("Classify(%q) = %q, want %q", message, class, want)
+		}
+	}
+
+	if _, err := NewRetryPolicy(config.RetryConfig{Retryable: []config.RetryRule{{Name: "bad", Pattern: "("}}}); err == nil {
+		t.Error("NewRetryPolicy() with an invalid pattern = nil, want an error")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := &RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 5 * time.Second},
+		{attempt: 30, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.delay(tt.attempt); got != tt.want {
+			t.Errorf("delay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+// fakeClaude は呼び出しごとの引数を記録し、n 回目の呼び出しで results[n-1] を実行する claude を作成する
+// 呼び出し回数を超えた場合は最後のスクリプトを使う
+func fakeClaude(t *testing.T, results ...string) (*Executor, func() []string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake claude is a shell script")
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\necho \"$*\" >> " + calls + "\nn=$(wc -l < " + calls + ")\n")
+	for i, r := range results {
+		if i == len(results)-1 {
+			script.WriteString(r + "\n")
+			break
+		}
+		script.WriteString("if [ \"$n\" -eq " + strconv.Itoa(i+1) + " ]; then\n" + r + "\nfi\n")
+	}
+	path := filepath.Join(dir, "claude")
+	if err := os.WriteFile(path, []byte(script.String()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return NewExecutor(path), func() []string {
+		data, _ := os.ReadFile(calls)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+const (
+	// rateLimited はセッションを作った後にレート制限で失敗する claude の出力
+	rateLimited = `echo '{"type":"result","subtype":"error_during_execution","is_error":true,"session_id":"s1","total_cost_usd":0.5}'; echo 'API Error: rate limit' >&2; exit 1`
+	// completed は成功した claude の出力
+	completed = `echo '{"type":"result","subtype":"success","result":"done","session_id":"s1","total_cost_usd":0.25}'; exit 0`
+)
+
+func TestExecuteAttempts(t *testing.T) {
+	task := &domain.Task{ID: "t1", Prompt: "do it", WorkDir: os.TempDir()}
+
+	tests := []struct {
+		name         string
+		scripts      []string
+		maxAttempts  int
+		backoff      time.Duration
+		timeout      time.Duration
+		checkBudget  func(*domain.Execution) string
+		wantAttempts int
+		wantSuccess  bool
+		wantLog      string
+	}{
+		{
+			name:         "retries until max attempts",
+			scripts:      []string{rateLimited},
+			maxAttempts:  3,
+			backoff:      time.Millisecond,
+			wantAttempts: 3,
+			wantLog:      "retrying in 2ms (attempt 3/3)",
+		},
+		{
+			name:         "succeeds on a retry",
+			scripts:      []string{rateLimited, completed},
+			maxAttempts:  3,
+			backoff:      time.Millisecond,
+			wantAttempts: 2,
+			wantSuccess:  true,
+		},
+		{
+			name:         "deadline caps the attempts",
+			scripts:      []string{rateLimited},
+			maxAttempts:  5,
+			backoff:      600 * time.Millisecond,
+			timeout:      time.Second,
+			wantAttempts: 2,
+			wantLog:      "the task timeout leaves no time to retry",
+		},
+		{
+			name:        "per-task budget stops retries",
+			scripts:     []string{rateLimited},
+			maxAttempts: 3,
+			backoff:     time.Millisecond,
+			checkBudget: func(e *domain.Execution) string {
+				if e.CostUSD >= 0.5 {
+					return "$0.50 of $0.50"
+				}
+				return ""
+			},
+			wantAttempts: 1,
+			wantLog:      "the per-task budget is exhausted ($0.50 of $0.50)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor, calls := fakeClaude(t, tt.scripts...)
+			policy := &RetryPolicy{
+				MaxAttempts: tt.maxAttempts,
+				Backoff:     tt.backoff,
+				MaxBackoff:  time.Minute,
+				rules:       []retryRule{{name: "rate_limit", pattern: regexp.MustCompile(`(?i)rate.?limit`)}},
+			}
+			var log bytes.Buffer
+			opt := &ExecuteOption{Timeout: tt.timeout, CheckBudget: tt.checkBudget, Log: &log}
+
+			execution, err := executor.executeAttempts(context.Background(), task, opt, policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if execution.Attempts != tt.wantAttempts || len(calls()) != tt.wantAttempts {
+				t.Errorf("attempts = %d (%d calls), want %d", execution.Attempts, len(calls()), tt.wantAttempts)
+			}
+			if execution.Success != tt.wantSuccess {
+				t.Errorf("success = %v, want %v (%s)", execution.Success, tt.wantSuccess, execution.Error)
+			}
+			if !tt.wantSuccess && execution.ErrorClass != "rate_limit" {
+				t.Errorf("error class = %q, want rate_limit", execution.ErrorClass)
+			}
+			if !strings.Contains(log.String(), tt.wantLog) {
+				t.Errorf("log = %q, want it to contain %q", log.String(), tt.wantLog)
+			}
+			// リトライは前回のセッションを再開する
+			for i, args := range calls()[1:] {
+				if !strings.Contains(args, "--resume s1") {
+					t.Errorf("call %d does not resume the session: %s", i+2, args)
+				}
+			}
+		})
+	}
+}
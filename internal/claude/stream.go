@@ -0,0 +1,125 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// streamWriter は --output-format stream-json の出力を1行ずつ読みやすい形式にして out に書き込む
+// 元の出力は raw に残す（最後の result イベントから結果・コストを取得するため）
+type streamWriter struct {
+	raw  *bytes.Buffer
+	out  io.Writer
+	line []byte
+}
+
+func newStreamWriter(raw *bytes.Buffer, out io.Writer) *streamWriter {
+	return &streamWriter{raw: raw, out: out}
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.raw.Write(p)
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			break
+		}
+		w.render(w.line[:i])
+		w.line = w.line[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush は改行で終わっていない最後の行を書き込む
+func (w *streamWriter) Flush() {
+	if len(w.line) > 0 {
+		w.render(w.line)
+		w.line = nil
+	}
+}
+
+// streamEvent は stream-json の1行分のイベント
+type streamEvent struct {
+	Type         string  `json:"type"`
+	Subtype      string  `json:"subtype"`
+	SessionID    string  `json:"session_id"`
+	Model        string  `json:"model"`
+	Result       string  `json:"result"`
+	NumTurns     int     `json:"num_turns"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Message      struct {
+		Content []struct {
+			Type    string          `json:"type"`
+			Text    string          `json:"text"`
+			Name    string          `json:"name"`
+			Input   json.RawMessage `json:"input"`
+			IsError bool            `json:"is_error"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// render はイベントを人が読む形式で書き込む（JSONでない行はそのまま書き込む）
+func (w *streamWriter) render(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	var ev streamEvent
+	if err := json.Unmarshal(line, &ev); err != nil {
+		fmt.Fprintf(w.out, "%s\n", line)
+		return
+	}
+
+	switch ev.Type {
+	case "system":
+		if ev.Subtype == "init" {
+			fmt.Fprintf(w.out, "· session %s (%s)\n", ev.SessionID, ev.Model)
+		}
+	case "assistant":
+		for _, c := range ev.Message.Content {
+			switch c.Type {
+			case "text":
+				if text := strings.TrimSpace(c.Text); text != "" {
+					fmt.Fprintf(w.out, "%s\n", text)
+				}
+			case "tool_use":
+				fmt.Fprintf(w.out, "→ %s %s\n", c.Name, toolSummary(c.Input))
+			}
+		}
+	case "user":
+		for _, c := range ev.Message.Content {
+			if c.Type == "tool_result" && c.IsError {
+				fmt.Fprintln(w.out, "  ✗ tool returned an error")
+			}
+		}
+	case "result":
+		fmt.Fprintf(w.out, "· %s (%d turns, $%.2f)\n", ev.Subtype, ev.NumTurns, ev.TotalCostUSD)
+	}
+}
+
+// toolSummary はツール呼び出しの入力から代表的な値を1行で返す
+func toolSummary(input json.RawMessage) string {
+	var fields map[string]any
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return ""
+	}
+	for _, key := range []string{"command", "file_path", "path", "pattern", "url", "description"} {
+		if s, ok := fields[key].(string); ok && s != "" {
+			return oneLine(s, 120)
+		}
+	}
+	return oneLine(string(input), 120)
+}
+
+// oneLine は改行を空白にして n 文字までに切り詰める
+func oneLine(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
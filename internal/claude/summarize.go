@@ -0,0 +1,42 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// summarizeTimeout は要約の実行のタイムアウト
+const summarizeTimeout = 5 * time.Minute
+
+// summarizePrompt は長いIssueのスレッドの途中のコメントを要約するプロンプト
+const summarizePrompt = `Summarize the following GitHub issue comments for a coding agent that will work on the issue.
+Keep every decision, requirement, constraint, file path, command, and error message, and who asked for what.
+Drop greetings, thanks, and discussion that was later superseded. Reply with the summary only, in Markdown.
+
+%s`
+
+// Summarize はClaude Codeでテキストを要約する（タスクのセッションとは別に実行する）
+// コメントの指示でファイルやコマンドに触れないよう、ツールを許可せず空の一時ディレクトリで実行する
+// 失敗した場合も、コストを記録できるよう実行結果を返す
+func (e *Executor) Summarize(ctx context.Context, text string) (string, *domain.Execution, error) {
+	dir, err := os.MkdirTemp("", "vibe-summarize-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create summarization dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	task := &domain.Task{Prompt: fmt.Sprintf(summarizePrompt, text), WorkDir: dir}
+	execution, err := e.Execute(ctx, task, &ExecuteOption{Timeout: summarizeTimeout, NoTools: true})
+	if err != nil {
+		return "", nil, err
+	}
+	if !execution.Success {
+		return "", execution, fmt.Errorf("summarization failed: %s", execution.Error)
+	}
+	return strings.TrimSpace(execution.Output), execution, nil
+}
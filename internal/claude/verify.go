@@ -0,0 +1,161 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/hook"
+)
+
+// verifyPrompt は検証に失敗したセッションを再開するときのプロンプト
+const verifyPrompt = `The verification command failed after your changes.
+
+$ %s
+%s
+
+Output:
+%s
+
+Fix the problems so that the verification passes. Do not skip, disable or weaken the checks.`
+
+// maxVerifyPromptOutput はプロンプトに含める検証出力の長さ（超えた分は先頭を省略する）
+const maxVerifyPromptOutput = 8000
+
+// executeVerified はClaude Codeを実行し、opt.Verify の検証が成功するまでセッションを再開する
+// 各回はリトライポリシーに従って実行し（executeAttempts）、結果を Execution.Iterations に記録する。
+// 検証が成功した場合のみタスクを成功にする。Claude Codeが失敗した場合・質問で終えた場合、
+// opt.MaxIterations に達した場合、opt.CheckBudget が予算超過を返した場合、opt.Timeout を使い切った場合は打ち切る
+// opt.Timeout は全ての回（検証を含む）の合計の上限で、各回には残りの時間を使う
+func (e *Executor) executeVerified(ctx context.Context, task *domain.Task, opt *ExecuteOption, policy *RetryPolicy) (*domain.Execution, error) {
+	if opt.Verify == nil {
+		execution, err := e.executeAttempts(ctx, task, opt, policy)
+		if err == nil {
+			reportChecklist(opt, execution)
+			detectQuestion(opt, execution)
+		}
+		return execution, err
+	}
+	maxIterations := opt.MaxIterations
+	if maxIterations < 1 {
+		maxIterations = config.DefaultVerifyMaxIterations
+	}
+
+	iterOpt := *opt
+	deadline := time.Now().Add(timeoutOrDefault(opt.Timeout))
+	var total *domain.Execution
+	for n := 1; ; n++ {
+		// リトライ前の予算の確認には、前の回までの合計も含める
+		if opt.CheckBudget != nil && total != nil {
+			spent := total
+			iterOpt.CheckBudget = func(exec *domain.Execution) string {
+				return opt.CheckBudget(spentWith(spent, exec))
+			}
+		}
+		execution, err := e.executeAttempts(ctx, task, &iterOpt, policy)
+		if err != nil {
+			return nil, err
+		}
+		reportChecklist(opt, execution)
+
+		it := domain.Iteration{
+			Number:    n,
+			SessionID: execution.SessionID,
+			Success:   execution.Success,
+			Error:     execution.Error,
+			Attempts:  execution.Attempts,
+			CostUSD:   execution.CostUSD,
+			Tokens:    execution.Tokens(),
+			Duration:  execution.Duration,
+		}
+		if detectQuestion(opt, execution) {
+			// 質問で終えた場合は検証せず、返信を待つ
+			opt.logf("❓ Claude Code asked a question, skipping verification")
+		} else if execution.Success {
+			results, ok := opt.Verify.Run(ctx, hook.Verify, task, execution, opt.Log)
+			it.Verification = results
+			it.Verified = ok
+			for _, r := range results {
+				it.Duration += r.Duration
+			}
+			if !ok {
+				failed := results[len(results)-1]
+				execution.Success = false
+				execution.Error = fmt.Sprintf("verification failed: %s (%s)", failed.Command, hook.Describe(failed))
+			}
+		}
+
+		if total == nil {
+			total = execution
+		} else {
+			attempts := total.Attempts
+			mergeAttempt(total, execution)
+			total.Attempts = attempts + execution.Attempts
+			total.ErrorClass = execution.ErrorClass
+		}
+		total.Iterations = append(total.Iterations, it)
+
+		if it.Verified || !it.Success || total.NeedsInput() || n >= maxIterations || ctx.Err() != nil {
+			return total, nil
+		}
+		if execution.SessionID == "" {
+			opt.logf("⚠️  Verification failed, but the session cannot be resumed (no session ID)")
+			return total, nil
+		}
+		if opt.CheckBudget != nil {
+			if reason := opt.CheckBudget(total); reason != "" {
+				opt.logf("⚠️  Verification failed, but the per-task budget is exhausted (%s)", reason)
+				return total, nil
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			opt.logf("⚠️  Verification failed, but the task timeout has been used up")
+			return total, nil
+		}
+
+		opt.logf("🔁 Verification failed, resuming the session (iteration %d/%d)", n+1, maxIterations)
+		iterOpt.Timeout = remaining
+		iterOpt.SessionID = execution.SessionID
+		iterOpt.Prompt = verificationPrompt(it.Verification[len(it.Verification)-1])
+	}
+}
+
+// verificationPrompt は失敗した検証コマンドの出力からセッション再開用のプロンプトを作成する
+func verificationPrompt(failed domain.HookResult) string {
+	output := strings.TrimRight(failed.Output, "\n")
+	if output == "" {
+		output = "(no output)"
+	}
+	if runes := []rune(output); len(runes) > maxVerifyPromptOutput {
+		output = "..." + string(runes[len(runes)-maxVerifyPromptOutput:])
+	}
+	fence := strings.Repeat("`", 3)
+	for strings.Contains(output, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf(verifyPrompt, failed.Command, hook.Describe(failed), fence+"\n"+output+"\n"+fence)
+}
+
+// detectQuestion は opt.DetectQuestions が有効で、成功した実行の最終結果が質問であれば記録する
+func detectQuestion(opt *ExecuteOption, execution *domain.Execution) bool {
+	if !opt.DetectQuestions || !execution.Success {
+		return false
+	}
+	execution.Question = DetectQuestion(execution.Output)
+	return execution.Question != ""
+}
+
+// spentWith は前の回までの合計 total に今回の実行 exec の分を加えた、予算の確認用の実行結果を返す
+func spentWith(total, exec *domain.Execution) *domain.Execution {
+	sum := *total
+	sum.CostUSD += exec.CostUSD
+	sum.InputTokens += exec.InputTokens
+	sum.OutputTokens += exec.OutputTokens
+	sum.Duration += exec.Duration
+	return &sum
+}
@@ -2,11 +2,24 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/auth"
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+var (
+	loginStore          string
+	loginDevice         bool
+	loginClientID       string
+	loginAppID          int64
+	loginPrivateKey     string
+	loginInstallationID int64
 )
 
 var authCmd = &cobra.Command{
@@ -17,7 +30,15 @@ var authCmd = &cobra.Command{
 var authLoginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to GitHub",
-	Long: `Login to GitHub using a personal access token.
+	Long: `Login to GitHub.
+
+By default a personal access token is read from stdin (without echo) and
+stored in the OS keyring (Secret Service on Linux, Keychain on macOS), or in
+an encrypted file under ~/.vibe when no keyring is available. The file's key
+is derived from the passphrase in VIBE_TOKEN_PASSPHRASE, which must be set
+whenever vibe runs; without a keyring or the passphrase, login fails.
+Use --device for the OAuth device flow, or --app-id and --private-key to
+authenticate as a GitHub App installation (comments are posted by the bot).
 
 For Classic tokens (https://github.com/settings/tokens):
   Required scopes:
@@ -29,52 +50,220 @@ For Fine-grained tokens (https://github.com/settings/tokens?type=beta):
   Account permissions:
     - Projects: Read and write
 
-Create a token at: https://github.com/settings/tokens`,
+Create a token at: https://github.com/settings/tokens
+
+OAuth device flow:
+  Requires the client ID of an OAuth App (or GitHub App) with device flow
+  enabled, via --client-id or VIBE_OAUTH_CLIENT_ID.
+
+GitHub App:
+  The app needs Projects (read and write), Issues (read and write), and
+  Contents (read) permissions. Installation tokens are refreshed automatically.
+  The installation is looked up from the project owner unless
+  --installation-id is given.
+
+Environment variables VIBE_GITHUB_TOKEN, GH_TOKEN, and GITHUB_TOKEN take
+precedence over the stored token, and the GitHub CLI token (gh auth token)
+is used when nothing else is configured.
+
+Examples:
+  vibe auth login
+  echo "$TOKEN" | vibe auth login --store file
+  vibe auth login --device --client-id Iv1.0123456789abcdef
+  vibe auth login --app-id 123456 --private-key ~/keys/vibe-bot.pem`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case loginAppID != 0 || loginPrivateKey != "":
+			return loginApp()
+		case loginDevice:
+			return loginDeviceFlow()
+		}
+
 		fmt.Println("GitHub Personal Access Token を入力してください")
 		fmt.Println("(必要なスコープ: project, read:org, repo)")
 		fmt.Println()
 		fmt.Print("Token: ")
 
-		reader := bufio.NewReader(os.Stdin)
-		token, err := reader.ReadString('\n')
+		token, err := readSecret(bufio.NewReader(os.Stdin))
 		if err != nil {
 			return fmt.Errorf("failed to read token: %w", err)
 		}
-		token = strings.TrimSpace(token)
 
 		if token == "" {
 			return fmt.Errorf("token cannot be empty")
 		}
 
-		cfg.GitHubToken = token
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+		if err := saveToken(token, config.AuthConfig{Method: config.AuthMethodToken}); err != nil {
+			return err
 		}
 
-		fmt.Println("✓ Token saved successfully")
+		fmt.Printf("✓ Token saved to %s\n", cfg.Auth.TokenStore)
 		fmt.Println()
 		fmt.Println("Next step: vibe project select")
 		return nil
 	},
 }
 
+// loginDeviceFlow はOAuthデバイスフローでログインする
+func loginDeviceFlow() error {
+	clientID := loginClientID
+	if clientID == "" {
+		clientID = os.Getenv("VIBE_OAUTH_CLIENT_ID")
+	}
+	if clientID == "" {
+		clientID = cfg.Auth.OAuthClientID
+	}
+	if clientID == "" {
+		return fmt.Errorf("OAuth client ID is required. Use --client-id or set VIBE_OAUTH_CLIENT_ID")
+	}
+
+	token, err := auth.DeviceLogin(context.Background(), cfg.Host, clientID, auth.DefaultScopes, func(userCode, verificationURI string) {
+		fmt.Printf("Open %s and enter the code: %s\n", verificationURI, userCode)
+		fmt.Println()
+		fmt.Println("Waiting for authorization...")
+	})
+	if err != nil {
+		return err
+	}
+
+	authCfg := config.AuthConfig{
+		Method:        config.AuthMethodOAuth,
+		OAuthClientID: clientID,
+		TokenExpiry:   token.Expiry,
+	}
+	if err := saveToken(token.AccessToken, authCfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Logged in with OAuth device flow (token saved to %s)\n", cfg.Auth.TokenStore)
+	fmt.Println()
+	fmt.Println("Next step: vibe project select")
+	return nil
+}
+
+// loginApp はGitHub Appの設定を保存し、インストールトークンを発行できるか確認する
+func loginApp() error {
+	if loginAppID == 0 || loginPrivateKey == "" {
+		return fmt.Errorf("--app-id and --private-key are required for GitHub App authentication")
+	}
+
+	keyPath, err := filepath.Abs(loginPrivateKey)
+	if err != nil {
+		return fmt.Errorf("failed to resolve private key path: %w", err)
+	}
+
+	appCfg := *cfg
+	appCfg.Auth = config.AuthConfig{
+		Method:            config.AuthMethodApp,
+		AppID:             loginAppID,
+		AppPrivateKeyPath: keyPath,
+		AppInstallationID: loginInstallationID,
+		TokenEnv:          cfg.Auth.TokenEnv,
+	}
+
+	// インストールIDの検索にはProjectのownerが必要なため、未設定なら検証を省略する
+	if loginInstallationID != 0 || cfg.ProjectOwner != "" {
+		src, err := auth.TokenSource(context.Background(), &appCfg, cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		if _, err := src.Token(); err != nil {
+			return err
+		}
+	}
+
+	// 以前に保存したトークンは不要になるため削除する
+	if err := auth.Delete(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	cfg.GitHubToken = ""
+	cfg.Auth = appCfg.Auth
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ GitHub App %d configured\n", loginAppID)
+	fmt.Println()
+	fmt.Println("Next step: vibe project select")
+	return nil
+}
+
+// saveToken はトークンを保存先に書き込み、認証設定を保存する
+func saveToken(token string, authCfg config.AuthConfig) error {
+	store := loginStore
+	if store == "" {
+		store = auth.DefaultStore()
+	}
+
+	// 保存先が変わる場合に古いトークンを残さない
+	if cfg.Auth.TokenStore != "" && cfg.Auth.TokenStore != store {
+		if err := auth.Delete(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
+
+	// プロファイルで指定された環境変数名は維持する
+	authCfg.TokenEnv = cfg.Auth.TokenEnv
+	cfg.Auth = authCfg
+	if err := auth.Save(cfg, token, store); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if store == config.TokenStoreFile {
+		fmt.Fprintf(os.Stderr, "ℹ️  The token file is encrypted with %s; keep it set when running vibe\n", auth.PassphraseEnv)
+	}
+	return nil
+}
+
+// maskToken はトークンの先頭と末尾4文字以外を伏せる（短いトークンは全て伏せる）
+func maskToken(token string) string {
+	if len(token) <= 12 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", len(token)-8) + token[len(token)-4:]
+}
+
 var authStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show authentication status",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if cfg.GitHubToken == "" {
+		if !cfg.HasCredentials() {
 			fmt.Println("✗ Not logged in")
 			fmt.Println()
 			fmt.Println("Run: vibe auth login")
 			return nil
 		}
 
-		// トークンの一部を表示
-		token := cfg.GitHubToken
-		masked := token[:4] + strings.Repeat("*", len(token)-8) + token[len(token)-4:]
-		fmt.Printf("✓ Logged in (token: %s)\n", masked)
+		switch {
+		case cfg.AuthMethod() == config.AuthMethodApp && !auth.FromEnv(cfg):
+			fmt.Printf("✓ Logged in as GitHub App %d\n", cfg.Auth.AppID)
+			fmt.Printf("  Private key: %s\n", cfg.Auth.AppPrivateKeyPath)
+			if cfg.Auth.AppInstallationID != 0 {
+				fmt.Printf("  Installation: %d\n", cfg.Auth.AppInstallationID)
+			}
+		default:
+			// トークンの一部を表示
+			fmt.Printf("✓ Logged in (token: %s)\n", maskToken(cfg.Token()))
+			fmt.Printf("  Source: %s\n", cfg.Auth.TokenSource)
+			if cfg.Auth.TokenSource == auth.SourceFile {
+				fmt.Printf("  Encrypted with: %s\n", auth.PassphraseEnv)
+			}
+			if cfg.AuthMethod() == config.AuthMethodOAuth && !auth.FromEnv(cfg) {
+				fmt.Println("  Method: OAuth device flow")
+			}
+			if !cfg.Auth.TokenExpiry.IsZero() && !auth.FromEnv(cfg) {
+				fmt.Printf("  Expires: %s\n", cfg.Auth.TokenExpiry.Local().Format("2006-01-02 15:04"))
+			}
+		}
 
+		if cfg.Profile() != "" {
+			fmt.Printf("  Profile: %s\n", cfg.Profile())
+		}
+		if cfg.GitHubHost() != config.DefaultHost {
+			fmt.Printf("  Host: %s\n", cfg.GitHubHost())
+		}
 		if cfg.ProjectOwner != "" {
 			fmt.Printf("  Project: %s #%d\n", cfg.ProjectOwner, cfg.ProjectNumber)
 		}
@@ -86,11 +275,18 @@ var authLogoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from GitHub",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := auth.Delete(cfg); err != nil {
+			return fmt.Errorf("failed to delete token: %w", err)
+		}
 		cfg.GitHubToken = ""
+		cfg.Auth = config.AuthConfig{TokenEnv: cfg.Auth.TokenEnv}
 		if err := cfg.Save(); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 		fmt.Println("✓ Logged out successfully")
+		if source := envTokenSource(); source != "" {
+			fmt.Printf("  Note: %s is still set and will be used\n", source)
+		}
 		return nil
 	},
 }
@@ -99,4 +295,11 @@ func init() {
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authStatusCmd)
 	authCmd.AddCommand(authLogoutCmd)
+
+	authLoginCmd.Flags().StringVar(&loginStore, "store", "", "Where to store the token: keyring or file (file needs $VIBE_TOKEN_PASSPHRASE; default: keyring when available)")
+	authLoginCmd.Flags().BoolVar(&loginDevice, "device", false, "Login with the OAuth device flow")
+	authLoginCmd.Flags().StringVar(&loginClientID, "client-id", "", "OAuth client ID for --device (default: $VIBE_OAUTH_CLIENT_ID)")
+	authLoginCmd.Flags().Int64Var(&loginAppID, "app-id", 0, "GitHub App ID")
+	authLoginCmd.Flags().StringVar(&loginPrivateKey, "private-key", "", "Path to the GitHub App private key (PEM)")
+	authLoginCmd.Flags().Int64Var(&loginInstallationID, "installation-id", 0, "GitHub App installation ID (default: looked up from the project owner)")
 }
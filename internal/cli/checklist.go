@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/github"
+)
+
+// checklistItems はClaude Codeに達成状況を報告させる受け入れ基準（Issue本文のタスクリスト）を返す
+func checklistItems(task *domain.Task) []domain.ChecklistItem {
+	if cfg.Checklist.Disabled {
+		return nil
+	}
+	return domain.ParseChecklist(task.IssueBody)
+}
+
+// reportChecklist は受け入れ基準の達成状況を表示し、完了と報告された項目をIssue本文でチェックする
+// 報告がなかった成功した実行は、Issue本文のチェックの状態のみを記録する
+func reportChecklist(ctx context.Context, taskSvc *github.TaskService, task *domain.Task, exec *domain.Execution, items []domain.ChecklistItem, prefix string) {
+	if len(items) == 0 || !exec.Success || exec.NeedsInput() {
+		return
+	}
+	if exec.Checklist == nil {
+		fmt.Printf("%s⚠️  Claude Code did not report on the acceptance criteria\n", prefix)
+		exec.Checklist = items
+		return
+	}
+	done, total := domain.ChecklistProgress(exec.Checklist)
+	fmt.Printf("%s☑️  Acceptance criteria: %d/%d done\n", prefix, done, total)
+	if cfg.Checklist.ReadOnly || task.IssueURL == "" {
+		return
+	}
+	n, err := taskSvc.CheckIssueItems(ctx, task, exec.Checklist)
+	if err != nil {
+		fmt.Printf("%s   ⚠️  Failed to update the issue checklist: %v\n", prefix, err)
+	} else if n > 0 {
+		fmt.Printf("%s   ✅ Checked %d item(s) in the issue\n", prefix, n)
+	}
+}
+
+// checklistRow はコメントの表に載せる受け入れ基準の達成数の行を返す
+func checklistRow(exec *domain.Execution) string {
+	if len(exec.Checklist) == 0 {
+		return ""
+	}
+	done, total := domain.ChecklistProgress(exec.Checklist)
+	return fmt.Sprintf("\n| Acceptance criteria | %d/%d |", done, total)
+}
+
+// checklistSection はコメントに載せる受け入れ基準の達成状況を返す
+func checklistSection(exec *domain.Execution) string {
+	if len(exec.Checklist) == 0 {
+		return ""
+	}
+
+	done, total := domain.ChecklistProgress(exec.Checklist)
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n### Acceptance criteria (%d/%d)\n\n", done, total)
+	for _, item := range exec.Checklist {
+		mark := "⬜"
+		if item.Checked || item.Done {
+			mark = "✅"
+		}
+		fmt.Fprintf(&b, "- %s %s", mark, item.Text)
+		if item.Note != "" {
+			fmt.Fprintf(&b, " — %s", item.Note)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
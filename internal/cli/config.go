@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+var (
+	configGlobal bool
+	configDryRun bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change configuration",
+	Long: `Inspect and change configuration.
+
+Each key is resolved from these layers (later wins):
+  1. Built-in defaults
+  2. ~/.vibe/config.json (and the active profile)
+  3. .vibe.yaml (searched upward from the current directory)
+  4. VIBE_* environment variables (e.g. VIBE_BUDGET_DAILY_USD)
+  5. --set key=value flags
+
+Examples:
+  vibe config list
+  vibe config get budget.daily.usd
+  vibe config set budget.daily.usd 20
+  vibe config set --global claude_path /opt/bin/claude
+  vibe config explain project.owner
+  vibe config validate
+  vibe --set retry.max_attempts=1 run`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:          "get <key>",
+	SilenceUsage: true,
+	Short:        "Print the effective value of a key",
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := config.FindKey(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(k.Value(cfg))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:          "set <key> <value>",
+	SilenceUsage: true,
+	Short:        "Set a key in .vibe.yaml (or the global config with --global)",
+	Long: `Set a key in .vibe.yaml, or in ~/.vibe/config.json with --global.
+
+The value is parsed as YAML, so lists and mappings can be given inline:
+  vibe config set retry.retryable '[timeout, rate_limit]'
+
+An empty value removes the key:
+  vibe config set budget.daily.usd ""
+
+With an active profile, --global writes host and project keys to the profile.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		k, err := config.FindKey(key)
+		if err != nil {
+			return err
+		}
+
+		if configGlobal || !k.Local {
+			if !configGlobal {
+				return fmt.Errorf("%s can only be set in the global config. Run: vibe config set --global %s <value>", key, key)
+			}
+			if err := cfg.SetGlobal(key, value); err != nil {
+				return err
+			}
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			fmt.Printf("✓ Set %s in %s\n", key, globalConfigLabel())
+			return nil
+		}
+
+		path, err := localConfigPath()
+		if err != nil {
+			return err
+		}
+		if err := config.SetLocal(path, key, value); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Set %s in %s\n", key, path)
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:          "list",
+	SilenceUsage: true,
+	Short:        "List all keys with their effective values and sources",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, k := range config.Keys {
+			if k.Hidden {
+				continue
+			}
+			value := k.Value(cfg)
+			if value == "" {
+				value = "-"
+			}
+			source := cfg.SourceOf(k.Name).String()
+			if source == "" {
+				source = "-"
+			}
+			fmt.Printf("%-26s %-24s %s\n", k.Name, value, source)
+		}
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:          "edit",
+	Short:        "Open .vibe.yaml (or the global config with --global) in $EDITOR",
+	SilenceUsage: true,
+	// 壊れた設定ファイルも編集できるよう、事前に読み込まない
+	PersistentPreRunE: skipConfigLoad,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configGlobal {
+			dir, err := config.Dir()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return fmt.Errorf("failed to create config dir: %w", err)
+			}
+			path := filepath.Join(dir, "config.json")
+			if err := runEditor(path); err != nil {
+				return err
+			}
+			// 編集結果を読み込み直して問題を報告する
+			if _, err := config.LoadWithPrecedence(loadOptions()); err != nil {
+				return fmt.Errorf("%s has errors: %w", path, err)
+			}
+			fmt.Printf("✓ %s is valid\n", path)
+			return nil
+		}
+
+		path, err := localConfigPath()
+		if err != nil {
+			return err
+		}
+		if err := runEditor(path); err != nil {
+			return err
+		}
+		return validateLocal(path)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check .vibe.yaml against the schema",
+	Long: `Check .vibe.yaml against the schema and report every problem with its line number.
+
+Unknown keys, wrong types, and contradictory settings (such as a project url
+and an owner/number that point to different projects) are errors.
+Files without "version: 1" are read leniently; run "vibe config migrate" to upgrade them.`,
+	Args:              cobra.MaximumNArgs(1),
+	SilenceUsage:      true,
+	PersistentPreRunE: skipConfigLoad,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configFileArg(args)
+		if err != nil {
+			return err
+		}
+		return validateLocal(path)
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [file]",
+	Short: "Upgrade .vibe.yaml to the current schema version",
+	Long: `Upgrade .vibe.yaml to the current schema version.
+
+Deprecated keys are rewritten, and "version" is added. Comments and other settings are kept.`,
+	Args:              cobra.MaximumNArgs(1),
+	SilenceUsage:      true,
+	PersistentPreRunE: skipConfigLoad,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configFileArg(args)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%s not found", path)
+		}
+
+		changes, err := config.MigrateFile(path, !configDryRun)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("✓ %s is already at version %d\n", path, config.SchemaVersion)
+			return nil
+		}
+
+		for _, c := range changes {
+			if c.Line > 0 {
+				fmt.Printf("   line %d: %s %s\n", c.Line, c.Key, c.Message)
+			} else {
+				fmt.Printf("   %s %s\n", c.Key, c.Message)
+			}
+		}
+		if configDryRun {
+			fmt.Println()
+			fmt.Println("Dry run: no changes written")
+			return nil
+		}
+		fmt.Printf("✓ Migrated %s to version %d\n", path, config.SchemaVersion)
+		return validateLocal(path)
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for .vibe.yaml",
+	Long: `Print the JSON Schema for .vibe.yaml.
+
+Editors with the YAML language server can use it for completion and validation
+by adding this line to the top of .vibe.yaml:
+
+  # yaml-language-server: $schema=https://raw.githubusercontent.com/tkc/vibe-project/main/internal/config/vibe.schema.json`,
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: skipConfigLoad,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := os.Stdout.Write(config.Schema)
+		return err
+	},
+}
+
+var configExplainCmd = &cobra.Command{
+	Use:          "explain [key]",
+	SilenceUsage: true,
+	Short:        "Show which file or variable set each value",
+	Long: `Show every layer that set a key, from lowest to highest precedence.
+The effective value is marked with "→". Without a key, all set keys are shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keys := config.Keys
+		if len(args) == 1 {
+			k, err := config.FindKey(args[0])
+			if err != nil {
+				return err
+			}
+			keys = []config.Key{k}
+		}
+
+		shown := 0
+		for _, k := range keys {
+			origins := cfg.Origins(k.Name)
+			if k.Hidden || (len(origins) == 0 && len(args) == 0) {
+				continue
+			}
+			if shown > 0 {
+				fmt.Println()
+			}
+			shown++
+
+			fmt.Printf("%s\n", k.Name)
+			if k.Description != "" {
+				fmt.Printf("  %s\n", k.Description)
+			}
+			if len(origins) == 0 {
+				fmt.Println("  (not set)")
+			}
+			for i, o := range origins {
+				mark := " "
+				if i == len(origins)-1 {
+					mark = "→"
+				}
+				value := o.Value
+				if value == "" {
+					value = `""`
+				}
+				fmt.Printf("  %s %-20s %s\n", mark, value, o.Source)
+			}
+			fmt.Printf("  env: %s\n", k.EnvVar())
+		}
+		if shown == 0 {
+			fmt.Println("No keys are set")
+		}
+		return nil
+	},
+}
+
+// localConfigPath は対象の .vibe.yaml を返す
+// 上位ディレクトリに .vibe.yaml があればそれを、なければカレントディレクトリのパスを返す
+func localConfigPath() (string, error) {
+	if path, err := config.FindProjectConfig(); err == nil {
+		return path, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return config.ProjectConfigPath(wd), nil
+}
+
+// configFileArg は引数で指定された .vibe.yaml（省略時は localConfigPath）を返す
+func configFileArg(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return localConfigPath()
+}
+
+// validateLocal は .vibe.yaml を検証して問題を表示する（エラーがあれば error を返す）
+func validateLocal(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s not found", path)
+	}
+	problems, err := config.ValidateFile(path)
+	if err != nil {
+		return err
+	}
+
+	errors := 0
+	for _, p := range problems {
+		switch p.Severity {
+		case config.SeverityError:
+			errors++
+			fmt.Printf("❌ %s\n", p.Error())
+		case config.SeverityWarning:
+			fmt.Printf("⚠️  %s\n", p.Error())
+		default:
+			fmt.Printf("ℹ️  %s\n", p.Error())
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("%s has %d error(s)", path, errors)
+	}
+	fmt.Printf("✓ %s is valid\n", path)
+	return nil
+}
+
+// skipConfigLoad は設定を読み込まないコマンドの PersistentPreRunE
+// （壊れた設定ファイルを検証・修正するコマンドで使う）
+func skipConfigLoad(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func globalConfigLabel() string {
+	if p := cfg.Profile(); p != "" {
+		return fmt.Sprintf("global config (profile %s)", p)
+	}
+	return "global config"
+}
+
+// runEditor は $VISUAL / $EDITOR（未設定なら vi）でファイルを開く
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+func init() {
+	configSetCmd.Flags().BoolVar(&configGlobal, "global", false, "Write to ~/.vibe/config.json instead of .vibe.yaml")
+	configEditCmd.Flags().BoolVar(&configGlobal, "global", false, "Edit ~/.vibe/config.json instead of .vibe.yaml")
+	configMigrateCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "Show the changes without writing them")
+
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configExplainCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
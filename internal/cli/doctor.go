@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/auth"
+	"github.com/tkc/vibe-project/internal/claude"
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/notify"
+)
+
+var (
+	doctorFix    bool
+	doctorNotify bool
+)
+
+// requiredScopes は Classic トークンに必要なスコープ
+var requiredScopes = []string{"project", "repo"}
+
+// checkResult は診断項目の結果
+type checkResult int
+
+const (
+	checkOK checkResult = iota
+	checkWarn
+	checkFail
+)
+
+// doctor は診断結果を出力しながら集計する
+type doctor struct {
+	failures int
+	warnings int
+}
+
+func (d *doctor) report(result checkResult, name, detail, fix string) {
+	mark := "✓"
+	switch result {
+	case checkWarn:
+		mark = "!"
+		d.warnings++
+	case checkFail:
+		mark = "✗"
+		d.failures++
+	}
+
+	line := fmt.Sprintf("%s %s", mark, name)
+	if detail != "" {
+		line += ": " + detail
+	}
+	fmt.Println(line)
+	if fix != "" && result != checkOK {
+		fmt.Printf("    Fix: %s\n", fix)
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:          "doctor",
+	Short:        "Validate the setup end-to-end",
+	SilenceUsage: true,
+	Long: `Check that everything vibe needs is in place before running tasks:
+
+  - GitHub token and its scopes
+  - Project reachability
+  - Required project fields, their types, and Status options
+  - claude binary and its version
+  - Working directory is a git repository
+  - Notifier configuration (and connectivity with --notify)
+
+With --fix, missing fields and Status options are created in the project.
+
+Examples:
+  vibe doctor
+  vibe doctor --fix
+  vibe doctor --notify   # Send a test notification to every notifier`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		d := &doctor{}
+
+		d.checkToken(ctx)
+		if cfg.HasCredentials() {
+			d.checkProject(ctx)
+		}
+		d.checkClaude()
+		d.checkWorkDir()
+		d.checkNotifiers(ctx)
+
+		fmt.Println()
+		if d.failures > 0 {
+			return fmt.Errorf("%d check(s) failed, %d warning(s)", d.failures, d.warnings)
+		}
+		if d.warnings > 0 {
+			fmt.Printf("All required checks passed (%d warning(s))\n", d.warnings)
+			return nil
+		}
+		fmt.Println("All checks passed")
+		return nil
+	},
+}
+
+func (d *doctor) checkToken(ctx context.Context) {
+	if !cfg.HasCredentials() {
+		d.report(checkFail, "GitHub token", "not configured", "vibe auth login")
+		return
+	}
+
+	client, err := newGitHubClient(cfg.ProjectOwner)
+	if err != nil {
+		d.report(checkFail, "GitHub token", err.Error(), "vibe auth login")
+		return
+	}
+	if cfg.AuthMethod() == config.AuthMethodApp {
+		// インストールトークンにはユーザーもスコープもないため、発行できるかだけ確認する
+		if _, err := client.GetOwnerID(ctx); err != nil {
+			d.report(checkFail, "GitHub App", err.Error(), "check app_id, the private key, and that the app is installed for "+cfg.ProjectOwner)
+			return
+		}
+		d.report(checkOK, "GitHub App", fmt.Sprintf("app %d installed for %s", cfg.Auth.AppID, cfg.ProjectOwner), "")
+		return
+	}
+
+	if cfg.Auth.TokenSource == auth.SourceConfig {
+		d.report(checkWarn, "Token storage", "stored in plaintext in ~/.vibe/config.json",
+			"run vibe auth login to move it to the OS keyring (or set VIBE_GITHUB_TOKEN)")
+	} else {
+		d.report(checkOK, "Token storage", cfg.Auth.TokenSource, "")
+	}
+
+	info, err := client.GetTokenInfo(ctx)
+	if err != nil {
+		d.report(checkFail, "GitHub token", err.Error(), "vibe auth login (the token may be expired or revoked)")
+		return
+	}
+
+	if !info.Classic {
+		d.report(checkOK, "GitHub token", fmt.Sprintf("%s (fine-grained token, scopes not listed)", info.Login), "")
+		return
+	}
+
+	var missing []string
+	for _, scope := range requiredScopes {
+		if !hasScope(info.Scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		d.report(checkFail, "GitHub token", fmt.Sprintf("%s, missing scopes: %s", info.Login, strings.Join(missing, ", ")),
+			"regenerate the token with the missing scopes at https://github.com/settings/tokens, then run vibe auth login")
+		return
+	}
+	d.report(checkOK, "GitHub token", fmt.Sprintf("%s (scopes: %s)", info.Login, strings.Join(info.Scopes, ", ")), "")
+
+	if !hasScope(info.Scopes, "read:org") {
+		d.report(checkWarn, "GitHub token", "missing read:org scope (required for organization projects)",
+			"add the read:org scope at https://github.com/settings/tokens if the project belongs to an organization")
+	}
+}
+
+// hasScope はスコープが含まれているか確認する（write:org は read:org を含むなど上位スコープも考慮）
+func hasScope(scopes []string, want string) bool {
+	for _, s := range scopes {
+		if s == want || (want == "read:org" && (s == "write:org" || s == "admin:org")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *doctor) checkProject(ctx context.Context) {
+	if cfg.ProjectOwner == "" || cfg.ProjectNumber == 0 {
+		d.report(checkFail, "Project", "not configured", "vibe project select <owner> <number>, or set project.url in .vibe.yaml")
+		return
+	}
+
+	client, err := newGitHubClient(cfg.ProjectOwner)
+	if err != nil {
+		d.report(checkFail, "Project", err.Error(), "vibe auth login")
+		return
+	}
+	taskSvc := newTaskService(client, cfg.ProjectNumber, true)
+	if err := taskSvc.Initialize(ctx); err != nil {
+		d.report(checkFail, "Project", err.Error(),
+			fmt.Sprintf("check that %s/#%d exists and the token can access it (read:org for organizations)", cfg.ProjectOwner, cfg.ProjectNumber))
+		return
+	}
+	d.report(checkOK, "Project", fmt.Sprintf("%s #%d", cfg.ProjectOwner, cfg.ProjectNumber), "")
+
+	problems := taskSvc.CheckSchema()
+	if len(problems) > 0 && doctorFix {
+		fmt.Println("  Creating missing fields and options...")
+		if err := taskSvc.FixSchema(ctx, problems); err != nil {
+			d.report(checkFail, "Project fields", err.Error(), "")
+			return
+		}
+		problems = taskSvc.CheckSchema()
+	}
+
+	for _, p := range problems {
+		result := checkFail
+		if !p.Spec.Required {
+			result = checkWarn
+		}
+		fix := "vibe doctor --fix"
+		if !p.Fixable() {
+			fix = fmt.Sprintf("rename or delete the %q field in the project settings, then run vibe doctor --fix", p.Spec.Name)
+		}
+		d.report(result, "Project fields", p.String(), fix)
+	}
+	if len(problems) == 0 {
+		d.report(checkOK, "Project fields", "all fields and Status options exist", "")
+	}
+}
+
+func (d *doctor) checkClaude() {
+	version, err := claude.NewExecutor(cfg.ClaudePath).Version()
+	if err != nil {
+		d.report(checkFail, "Claude Code", err.Error(), "install Claude Code, or set claude_path in .vibe.yaml")
+		return
+	}
+	d.report(checkOK, "Claude Code", fmt.Sprintf("%s (%s)", version, cfg.ClaudePath), "")
+}
+
+func (d *doctor) checkWorkDir() {
+	wd, err := os.Getwd()
+	if err != nil {
+		d.report(checkFail, "WorkDir", err.Error(), "")
+		return
+	}
+
+	out, err := exec.Command("git", "-C", wd, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		d.report(checkWarn, "WorkDir", wd+" is not a git repository", "run vibe from inside the repository Claude should work on")
+		return
+	}
+	d.report(checkOK, "WorkDir", fmt.Sprintf("%s (repo: %s)", wd, strings.TrimSpace(string(out))), "")
+}
+
+func (d *doctor) checkNotifiers(ctx context.Context) {
+	notifier, err := notify.New(cfg.Notifiers)
+	if err != nil {
+		d.report(checkFail, "Notifiers", err.Error(), "fix the notifiers section in .vibe.yaml")
+		return
+	}
+
+	if !doctorNotify {
+		d.report(checkOK, "Notifiers", fmt.Sprintf("%d configured (use --notify to send a test)", max(len(cfg.Notifiers), 1)), "")
+		return
+	}
+
+	for _, r := range notifier.Test(ctx) {
+		if r.Err != nil {
+			d.report(checkFail, "Notifier "+r.Name, r.Err.Error(), "check the URL or SMTP settings and network access")
+			continue
+		}
+		d.report(checkOK, "Notifier "+r.Name, "test notification sent", "")
+	}
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Create missing project fields and Status options")
+	doctorCmd.Flags().BoolVar(&doctorNotify, "notify", false, "Send a test notification to every notifier")
+}
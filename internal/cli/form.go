@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/github"
+)
+
+// formProblem はIssueフォームの項目の誤り
+type formProblem struct {
+	Section string // 見出しのラベル（テンプレートの誤りは空）
+	Message string
+}
+
+// promptTemplateData はプロンプトのテンプレートに渡す値
+type promptTemplateData struct {
+	Prompt   string            // Issueのスレッドから組み立てたプロンプト
+	Vars     map[string]string // issue_form.variables の変数
+	Form     domain.IssueForm  // 全ての項目（見出しのラベル -> 値）
+	Title    string
+	IssueURL string
+}
+
+// applyIssueForm は issue_form の対応に従ってIssueフォームの項目をタスクの設定に反映する
+// Issue本文は信頼できない入力のため、作成者が input.Trusts を満たさないIssueのフォームは無視する
+// 作業ディレクトリはProjectのWorkDirが空の場合のみ、baseDir（空ならカレントディレクトリ）の中に限って設定する
+// プロンプトのテンプレートはセッションを再開しない実行にのみ適用する。誤りがあれば一覧を返す
+func applyIssueForm(task *domain.Task, baseDir, prefix string) []formProblem {
+	f := cfg.IssueForm
+	if f.IsZero() {
+		return nil
+	}
+	if !cfg.Input.Trusts(task.IssueAuthor, task.IssueAssociation) {
+		fmt.Printf("%s⚠️  Ignoring the issue form: @%s is not a trusted user (see input.trusted_users)\n", prefix, task.IssueAuthor)
+		return nil
+	}
+	form := domain.ParseIssueForm(task.IssueBody)
+	var problems []formProblem
+	value := func(section string) string {
+		if section == "" {
+			return ""
+		}
+		v, _ := form.Get(section)
+		return v
+	}
+
+	if v := value(f.WorkDir); v != "" && task.WorkDir == "" {
+		dir, err := confineDir(baseDir, v)
+		if err != nil {
+			problems = append(problems, formProblem{f.WorkDir, err.Error()})
+		} else {
+			task.WorkDir = dir
+		}
+	}
+
+	if v := value(f.Timeout); v != "" {
+		d, err := time.ParseDuration(v)
+		limit := f.EffectiveMaxTimeout(cfg.Budget.PerTask.Time.Duration)
+		switch {
+		case err != nil || d <= 0:
+			problems = append(problems, formProblem{f.Timeout, fmt.Sprintf("invalid duration %q (use e.g. 45m or 2h)", v)})
+		case d > limit:
+			problems = append(problems, formProblem{f.Timeout, fmt.Sprintf("%s exceeds the maximum of %s (see issue_form.max_timeout)", d, limit)})
+		default:
+			task.Timeout = d
+		}
+	}
+
+	if v := value(f.Model); v != "" {
+		if !f.AllowsModel(v) {
+			problems = append(problems, formProblem{f.Model, fmt.Sprintf("model %q is not allowed (see issue_form.models)", v)})
+		} else {
+			task.Model = v
+		}
+	}
+
+	if v := value(f.AllowedTools); v != "" {
+		var tools []string
+		for _, tool := range domain.FormList(v) {
+			if f.AllowsTool(tool) {
+				tools = append(tools, tool)
+			} else {
+				problems = append(problems, formProblem{f.AllowedTools, fmt.Sprintf("tool %q is not allowed (see issue_form.tool_allowlist)", tool)})
+			}
+		}
+		task.AllowedTools = tools
+	}
+
+	if !task.Resume {
+		tmpl, err := f.ParseTemplate()
+		if err != nil {
+			problems = append(problems, formProblem{Message: fmt.Sprintf("invalid prompt template: %v", err)})
+		} else if tmpl != nil {
+			data := promptTemplateData{
+				Prompt:   task.Prompt,
+				Vars:     make(map[string]string, len(f.Variables)),
+				Form:     form,
+				Title:    task.Title,
+				IssueURL: task.IssueURL,
+			}
+			for name, section := range f.Variables {
+				data.Vars[name] = value(section)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				problems = append(problems, formProblem{Message: fmt.Sprintf("failed to render the prompt template: %v", err)})
+			} else {
+				task.Prompt = strings.TrimSpace(b.String())
+			}
+		}
+	}
+	return problems
+}
+
+// confineDir はIssueフォームの作業ディレクトリを baseDir の中の既存のディレクトリとして解決する
+// 絶対パス・~・.. で baseDir の外に出るパス（シンボリックリンクを含む）は受け付けない
+func confineDir(baseDir, dir string) (string, error) {
+	if filepath.IsAbs(dir) || filepath.VolumeName(dir) != "" || strings.HasPrefix(dir, "~") {
+		return "", fmt.Errorf("%q must be a path relative to the working directory", dir)
+	}
+	base, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the working directory: %w", err)
+	}
+	path := filepath.Join(base, filepath.Clean(dir))
+	if !within(base, path) {
+		return "", fmt.Errorf("%q is outside the working directory", dir)
+	}
+
+	// シンボリックリンクで外に出ていないかを実体のパスで確認する
+	realBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the working directory: %w", err)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("directory %q does not exist", dir)
+	}
+	if !within(realBase, realPath) {
+		return "", fmt.Errorf("%q is outside the working directory", dir)
+	}
+	if info, err := os.Stat(realPath); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%q is not a directory", dir)
+	}
+	return path, nil
+}
+
+// within は path が base 自身またはその中にあるかを返す
+func within(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// rejectIssueForm はIssueフォームの誤りを表示し、ドライランでなければIssueにコメントしてタスクを In review にする
+func rejectIssueForm(ctx context.Context, taskSvc *github.TaskService, task *domain.Task, problems []formProblem, dryRun bool, prefix string) error {
+	fmt.Printf("%s⚠️  The issue form has %d problem(s):\n", prefix, len(problems))
+	for _, p := range problems {
+		fmt.Printf("%s   - %s\n", prefix, p)
+	}
+	if !dryRun {
+		if err := taskSvc.AddIssueComment(ctx, task, buildFormComment(problems)); err != nil {
+			fmt.Printf("%s   ⚠️  Failed to add comment: %v\n", prefix, err)
+		}
+		if err := taskSvc.RejectTask(ctx, task, fmt.Sprintf("Invalid issue form: %s", problems[0])); err != nil {
+			fmt.Printf("%s   ⚠️  Failed to update task: %v\n", prefix, err)
+		}
+	}
+	return fmt.Errorf("invalid issue form (%d problem(s))", len(problems))
+}
+
+func (p formProblem) String() string {
+	if p.Section == "" {
+		return p.Message
+	}
+	return p.Section + ": " + p.Message
+}
+
+// buildFormComment はIssueフォームの誤りを知らせるコメントを生成する
+func buildFormComment(problems []formProblem) string {
+	var b strings.Builder
+	b.WriteString(github.VibeCommentMarker + "\n## ⚠️ vibe: Invalid Issue Form\n\n")
+	b.WriteString("This task was not run because of the following problems in the issue form:\n\n")
+	for _, p := range problems {
+		if p.Section != "" {
+			fmt.Fprintf(&b, "- **%s**: %s\n", p.Section, p.Message)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", p.Message)
+		}
+	}
+	b.WriteString("\nEdit the issue to fix them, then move the task back to Ready.\n\n---\n<sub>Auto-generated by vibe-project</sub>")
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfineDir(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "README.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(base, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string // base からの相対パス（エラーなら空）
+		wantErr bool
+	}{
+		{name: "current directory", dir: ".", want: "."},
+		{name: "subdirectory", dir: "services/api", want: "services/api"},
+		{name: "cleaned path", dir: "./services/../services/api", want: "services/api"},
+		{name: "absolute path", dir: "/etc", wantErr: true},
+		{name: "home directory", dir: "~/src", wantErr: true},
+		{name: "parent directory", dir: "..", wantErr: true},
+		{name: "escape through parent", dir: "services/../../etc", wantErr: true},
+		{name: "symlink outside", dir: "escape", wantErr: true},
+		{name: "missing directory", dir: "nope", wantErr: true},
+		{name: "file", dir: "README.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confineDir(base, tt.dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("confineDir(%q) = %q, want an error", tt.dir, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("confineDir(%q) returned an error: %v", tt.dir, err)
+			}
+			if want := filepath.Join(base, tt.want); got != want {
+				t.Errorf("confineDir(%q) = %q, want %q", tt.dir, got, want)
+			}
+		})
+	}
+}
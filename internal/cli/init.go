@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/github"
+)
+
+var (
+	initProject int
+	initCreate  string
+	initRepos   []string
+	initYes     bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [owner]",
+	Short: "Bootstrap a project board and .vibe.yaml",
+	Long: `Set up a GitHub Project for vibe.
+
+vibe init selects an existing project (or creates one), creates any missing
+custom fields and Status options, links repositories, and writes the
+project URL to .vibe.yaml in the current directory.
+
+It is idempotent: running it again only applies what is missing.
+A plan is shown before anything is changed.
+
+Examples:
+  vibe init tkc                          # Choose a project interactively
+  vibe init tkc --project 6              # Use project #6
+  vibe init my-org --create "Agent board" --repo my-org/api --repo my-org/web
+  vibe init tkc --project 6 --yes        # Apply without confirmation`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cfg.HasCredentials() {
+			return fmt.Errorf("not logged in. Run: vibe auth login")
+		}
+
+		owner := cfg.ProjectOwner
+		if len(args) > 0 {
+			owner = args[0]
+		}
+		if owner == "" {
+			return fmt.Errorf("owner is required. Usage: vibe init <owner>")
+		}
+
+		ctx := context.Background()
+		client, err := newGitHubClient(owner)
+		if err != nil {
+			return err
+		}
+		reader := bufio.NewReader(os.Stdin)
+
+		// 対象のProjectを決める
+		project, err := resolveInitProject(ctx, client, owner, reader)
+		if err != nil {
+			return err
+		}
+
+		// 計画を作成
+		var plan []string
+		var taskSvc *github.TaskService
+		var problems []github.SchemaProblem
+		var repos []string
+
+		if project == nil {
+			plan = append(plan, fmt.Sprintf("Create project %q for %s", initCreate, owner))
+			plan = append(plan, "Create fields Result, SessionID, ExecutedAt, Priority, Cost, Attempts and the Status options")
+			repos = initRepos
+		} else {
+			taskSvc = newTaskService(client, project.Number, true)
+			if err := taskSvc.Initialize(ctx); err != nil {
+				return fmt.Errorf("failed to initialize: %w", err)
+			}
+
+			problems = taskSvc.CheckSchema()
+			for _, p := range problems {
+				switch {
+				case !p.Fixable():
+					fmt.Printf("⚠️  Cannot fix automatically: %s\n", p)
+				case p.Missing:
+					plan = append(plan, fmt.Sprintf("Create field %s (%s)", p.Spec.Name, p.Spec.DataType()))
+				default:
+					plan = append(plan, fmt.Sprintf("Add %s options: %s", p.Spec.Name, strings.Join(p.MissingOptions, ", ")))
+				}
+			}
+
+			linked, err := client.GetLinkedRepositories(ctx, project.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get linked repositories: %w", err)
+			}
+			for _, r := range initRepos {
+				if !containsFold(linked, r) {
+					repos = append(repos, r)
+				}
+			}
+		}
+
+		for _, r := range repos {
+			plan = append(plan, fmt.Sprintf("Link repository %s", r))
+		}
+
+		yamlPath, err := projectConfigPath()
+		if err != nil {
+			return err
+		}
+		currentURL, err := config.ReadProjectURL(yamlPath)
+		if err != nil {
+			return err
+		}
+		if project == nil || currentURL != project.URL {
+			plan = append(plan, fmt.Sprintf("Write project.url to %s", yamlPath))
+		}
+
+		if len(plan) == 0 {
+			fmt.Printf("✓ Already initialized: %s (#%d)\n", project.Title, project.Number)
+			return nil
+		}
+
+		fmt.Println("Plan:")
+		for _, step := range plan {
+			fmt.Printf("  + %s\n", step)
+		}
+		fmt.Println()
+
+		if !initYes {
+			fmt.Print("Apply? [y/N]: ")
+			answer, _ := reader.ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		// 適用
+		if project == nil {
+			project, err = client.CreateProject(ctx, initCreate)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Created project: %s (#%d)\n", project.Title, project.Number)
+
+			taskSvc = newTaskService(client, project.Number, true)
+			if err := taskSvc.Initialize(ctx); err != nil {
+				return fmt.Errorf("failed to initialize: %w", err)
+			}
+			problems = taskSvc.CheckSchema()
+		}
+
+		if len(problems) > 0 {
+			if err := taskSvc.FixSchema(ctx, problems); err != nil {
+				return err
+			}
+			fmt.Println("✓ Fields and Status options are ready")
+		}
+
+		for _, r := range repos {
+			if err := client.LinkRepository(ctx, project.ID, r); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Linked repository: %s\n", r)
+		}
+
+		if currentURL != project.URL {
+			if err := config.SaveProjectURL(yamlPath, project.URL); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Wrote %s\n", yamlPath)
+		}
+
+		fmt.Println()
+		fmt.Println("Next step: vibe doctor")
+		return nil
+	},
+}
+
+// resolveInitProject は対象のProjectを返す
+// --create で未作成の場合は nil を返す（適用時に作成する）
+func resolveInitProject(ctx context.Context, client *github.Client, owner string, reader *bufio.Reader) (*github.Project, error) {
+	if initProject > 0 {
+		return client.GetProjectByNumber(ctx, initProject)
+	}
+
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	if initCreate != "" {
+		// 同名のProjectがあれば再利用する
+		for _, p := range projects {
+			if p.Title == initCreate {
+				return &p, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// 設定済みのProjectがあればそれを使う
+	if cfg.ProjectOwner == owner && cfg.ProjectNumber > 0 {
+		return client.GetProjectByNumber(ctx, cfg.ProjectNumber)
+	}
+
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects found for %s. Use --create <title> to create one", owner)
+	}
+
+	fmt.Printf("Projects for %s:\n\n", owner)
+	for _, p := range projects {
+		fmt.Printf("  #%-4d %s\n", p.Number, p.Title)
+	}
+	fmt.Println()
+	fmt.Print("Select a project number: ")
+
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selection: %w", err)
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(answer), "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project number: %s", strings.TrimSpace(answer))
+	}
+	for _, p := range projects {
+		if p.Number == number {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("project #%d not found", number)
+}
+
+// projectConfigPath はカレントディレクトリの .vibe.yaml のパスを返す
+func projectConfigPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return config.ProjectConfigPath(wd), nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	initCmd.Flags().IntVarP(&initProject, "project", "p", 0, "Use an existing project number")
+	initCmd.Flags().StringVar(&initCreate, "create", "", "Create a project with this title (reused if it already exists)")
+	initCmd.Flags().StringArrayVar(&initRepos, "repo", nil, "Link a repository (owner/name), repeatable")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Apply the plan without confirmation")
+}
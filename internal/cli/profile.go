@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/auth"
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+var (
+	profileHost       string
+	profileProjectURL string
+	profileTokenEnv   string
+	profileDefault    bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles",
+	Long: `Manage named profiles in ~/.vibe/config.json.
+
+A profile holds a GitHub host, how to authenticate, and a default project.
+Select one with --profile, the VIBE_PROFILE environment variable,
+"profile:" in .vibe.yaml, or "vibe profile use".
+
+Examples:
+  vibe profile add work --project-url https://github.com/orgs/my-org/projects/3
+  vibe profile add ghes --host github.example.com --token-env GHES_TOKEN
+  vibe auth login --profile work
+  vibe profile use work`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := cfg.ProfileNames()
+		if len(names) == 0 {
+			fmt.Println("No profiles configured")
+			fmt.Println()
+			fmt.Println("Add one with: vibe profile add <name>")
+			return nil
+		}
+
+		for _, name := range names {
+			p := cfg.Profiles[name]
+			mark := " "
+			if name == cfg.Profile() {
+				mark = "*"
+			}
+
+			host := p.Host
+			if host == "" {
+				host = config.DefaultHost
+			}
+			project := "-"
+			if p.ProjectOwner != "" {
+				project = fmt.Sprintf("%s #%d", p.ProjectOwner, p.ProjectNumber)
+			}
+			suffix := ""
+			if name == cfg.DefaultProfile {
+				suffix = " (default)"
+			}
+			fmt.Printf("%s %-12s %-20s %s%s\n", mark, name, host, project, suffix)
+		}
+		return nil
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or update a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		p := cfg.Profiles[name]
+
+		if cmd.Flags().Changed("host") {
+			p.Host = profileHost
+		}
+		if profileProjectURL != "" {
+			owner, number, err := config.ParseProjectURL(profileProjectURL)
+			if err != nil {
+				return err
+			}
+			p.ProjectOwner, p.ProjectNumber = owner, number
+		}
+		if cmd.Flags().Changed("token-env") {
+			p.Auth.TokenEnv = profileTokenEnv
+		}
+
+		// 使用中のプロファイルは保存時に現在の値で上書きされるため、先に解除する
+		if cfg.Profile() == name {
+			cfg.ClearProfile()
+		}
+		cfg.SetProfile(name, p)
+		if profileDefault {
+			cfg.DefaultProfile = name
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("✓ Saved profile: %s\n", name)
+		if p.Auth.TokenEnv == "" {
+			fmt.Println()
+			fmt.Printf("Next step: vibe auth login --profile %s\n", name)
+		}
+		return nil
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile: %s", name)
+		}
+
+		cfg.DefaultProfile = name
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Default profile: %s\n", name)
+		return nil
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile and its stored token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile: %s", name)
+		}
+
+		// プロファイルごとに保存したトークンを削除する
+		profileCfg, err := cfg.WithProfile(name)
+		if err != nil {
+			return err
+		}
+		if err := auth.Delete(profileCfg); err != nil {
+			fmt.Printf("⚠️  Failed to delete token: %v\n", err)
+		}
+
+		if cfg.Profile() == name {
+			cfg.ClearProfile()
+		}
+		delete(cfg.Profiles, name)
+		if cfg.DefaultProfile == name {
+			cfg.DefaultProfile = ""
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Removed profile: %s\n", name)
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+
+	profileAddCmd.Flags().StringVar(&profileHost, "host", "", "GitHub host (default: github.com)")
+	profileAddCmd.Flags().StringVar(&profileProjectURL, "project-url", "", "Default project URL")
+	profileAddCmd.Flags().StringVar(&profileTokenEnv, "token-env", "", "Read the token from this environment variable")
+	profileAddCmd.Flags().BoolVar(&profileDefault, "default", false, "Make this the default profile")
+}
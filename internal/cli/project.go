@@ -6,7 +6,6 @@ import (
 	"strconv"
 
 	"github.com/spf13/cobra"
-	"github.com/tkc/vibe-project/internal/github"
 )
 
 var projectCmd = &cobra.Command{
@@ -19,7 +18,7 @@ var projectListCmd = &cobra.Command{
 	Short: "List projects for a user or organization",
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if cfg.GitHubToken == "" {
+		if !cfg.HasCredentials() {
 			return fmt.Errorf("not logged in. Run: vibe auth login")
 		}
 
@@ -31,7 +30,10 @@ var projectListCmd = &cobra.Command{
 		} else {
 			return fmt.Errorf("owner is required. Usage: vibe project list <owner>")
 		}
-		client := github.NewClient(cfg.GitHubToken, owner)
+		client, err := newGitHubClient(owner)
+		if err != nil {
+			return err
+		}
 
 		ctx := context.Background()
 		projects, err := client.GetProjects(ctx)
@@ -61,7 +63,7 @@ var projectSelectCmd = &cobra.Command{
 	Short: "Select a project to work with",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if cfg.GitHubToken == "" {
+		if !cfg.HasCredentials() {
 			return fmt.Errorf("not logged in. Run: vive auth login")
 		}
 
@@ -72,7 +74,10 @@ var projectSelectCmd = &cobra.Command{
 		}
 
 		// プロジェクトが存在するか確認
-		client := github.NewClient(cfg.GitHubToken, owner)
+		client, err := newGitHubClient(owner)
+		if err != nil {
+			return err
+		}
 		ctx := context.Background()
 
 		project, err := client.GetProjectByNumber(ctx, number)
@@ -102,7 +107,10 @@ var projectShowCmd = &cobra.Command{
 			return err
 		}
 
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
 		ctx := context.Background()
 
 		project, err := client.GetProjectByNumber(ctx, cfg.ProjectNumber)
@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/claude"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/github"
+	"github.com/tkc/vibe-project/internal/runlog"
+	"github.com/tkc/vibe-project/internal/usage"
+)
+
+// defaultResumePrompt は --prompt を指定せず、Issueに新しいコメントもないときにセッションを再開するプロンプト
+const defaultResumePrompt = "Continue the task from where you left off."
+
+var (
+	resumePrompt     string
+	resumePromptFile string
+	resumeDryRun     bool
+	resumeTimeout    time.Duration
+
+	rerunFresh   bool
+	rerunDryRun  bool
+	rerunTimeout time.Duration
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <task-id>",
+	Short: "Continue the task's stored Claude Code session with a new instruction",
+	Long: `Resume the Claude Code session stored in the task's SessionID field.
+
+Only the new instruction is sent; the session already has the issue and the
+earlier conversation. Without --prompt or --prompt-file, the comments posted on
+the issue since the last run are sent as new feedback, or Claude Code is asked
+to continue where it left off if there are none. The result is saved and
+commented on the issue like vibe run.
+
+Examples:
+  vibe resume <task-id> --prompt "Also update the README"
+  vibe resume <task-id> --prompt-file feedback.md
+  git diff | vibe resume <task-id> --prompt-file -`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		prompt, err := readResumePrompt()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		taskSvc, executor, budget, err := connectRun(ctx, resumeDryRun)
+		if err != nil {
+			return err
+		}
+		task, err := getIdleTask(ctx, taskSvc, args[0])
+		if err != nil {
+			return err
+		}
+		if task.SessionID == "" {
+			return fmt.Errorf("task has no stored Claude Code session to resume (start over with: vibe rerun %s)", task.ID)
+		}
+		if prompt == "" {
+			prompt, err = loadDeltaPrompt(ctx, taskSvc, task)
+			if err != nil {
+				return err
+			}
+		}
+		task.Prompt = prompt
+		task.Resume = true
+		if problems := applyIssueForm(task, "", ""); len(problems) > 0 {
+			return rejectIssueForm(ctx, taskSvc, task, problems, resumeDryRun, "")
+		}
+		if err := setDefaultWorkDir(task); err != nil {
+			return err
+		}
+		return runSingleTask(ctx, taskSvc, executor, budget, task, resumeDryRun, resumeTimeout)
+	},
+}
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun <task-id>",
+	Short: "Reset a task to Ready and execute it again from the issue",
+	Long: `Move the task back to Ready and execute it again with the prompt built from
+the issue, in a new Claude Code session.
+
+With --fresh, the stored SessionID and Result are cleared first, so nothing
+from the previous run is kept on the board.
+
+Examples:
+  vibe rerun <task-id>
+  vibe rerun <task-id> --fresh`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		taskSvc, executor, budget, err := connectRun(ctx, rerunDryRun)
+		if err != nil {
+			return err
+		}
+		task, err := getIdleTask(ctx, taskSvc, args[0])
+		if err != nil {
+			return err
+		}
+
+		if rerunDryRun {
+			fmt.Println("[DRY RUN] Would reset the status to Ready")
+			if rerunFresh {
+				fmt.Println("[DRY RUN] Would clear the stored SessionID and Result")
+			}
+		} else {
+			if rerunFresh {
+				fmt.Println("🧹 Clearing the stored session and result...")
+				if err := taskSvc.ClearTaskSession(ctx, task); err != nil {
+					return err
+				}
+			}
+			if task.Status != domain.StatusReady {
+				fmt.Println("↩️  Resetting status to Ready...")
+				if err := taskSvc.SetTaskStatus(ctx, task.ID, domain.StatusReady); err != nil {
+					return fmt.Errorf("failed to update status: %w", err)
+				}
+			}
+		}
+		task.Status = domain.StatusReady
+
+		fmt.Println("📥 Loading prompt from issue comments...")
+		if err := taskSvc.LoadTaskPrompt(ctx, task); err != nil {
+			return fmt.Errorf("failed to load prompt: %w", err)
+		}
+		if task.PromptNote != "" {
+			fmt.Printf("✂️  %s\n", task.PromptNote)
+		}
+		if problems := applyIssueForm(task, "", ""); len(problems) > 0 {
+			return rejectIssueForm(ctx, taskSvc, task, problems, rerunDryRun, "")
+		}
+		if err := setDefaultWorkDir(task); err != nil {
+			return err
+		}
+		if task.IsBlocked() {
+			return fmt.Errorf("task is blocked by unfinished dependencies (see: vibe task show %s)", task.ID)
+		}
+		return runSingleTask(ctx, taskSvc, executor, budget, task, rerunDryRun, rerunTimeout)
+	},
+}
+
+// connectRun はClaude Code（ドライランでなければ）とGitHub Projectへの接続を確認する
+// 返す予算はプロンプトの要約と実行の両方に使う
+func connectRun(ctx context.Context, dryRun bool) (*github.TaskService, *claude.Executor, *usage.Budget, error) {
+	executor := claude.NewExecutor(cfg.ClaudePath)
+	if !dryRun {
+		if err := executor.CheckInstalled(); err != nil {
+			return nil, nil, nil, fmt.Errorf("claude is not installed: %w", err)
+		}
+	}
+
+	client, err := newGitHubClient(cfg.ProjectOwner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	budget, err := newBudget()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	taskSvc := newTaskService(client, cfg.ProjectNumber, false)
+	taskSvc.SetAssembler(newAssembler(executor, budget, dryRun))
+	if err := taskSvc.Initialize(ctx); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	return taskSvc, executor, budget, nil
+}
+
+// getIdleTask はタスクを取得する（他のプロセスで実行中ならエラー）
+func getIdleTask(ctx context.Context, taskSvc *github.TaskService, taskID string) (*domain.Task, error) {
+	task, err := taskSvc.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.IssueURL == "" {
+		return nil, fmt.Errorf("task has no associated issue")
+	}
+
+	runs, err := runlog.Running()
+	if err != nil {
+		fmt.Printf("   ⚠️  Failed to check running executions: %v\n", err)
+	}
+	for _, r := range runs {
+		if r.TaskID == task.ID {
+			return nil, fmt.Errorf("task is already running (pid %d, started %s)", r.PID, r.StartedAt.Format("15:04:05"))
+		}
+	}
+	return task, nil
+}
+
+// readResumePrompt は --prompt / --prompt-file からセッションを再開するプロンプトを読み込む（指定がなければ空）
+func readResumePrompt() (string, error) {
+	if resumePrompt != "" && resumePromptFile != "" {
+		return "", fmt.Errorf("--prompt and --prompt-file cannot be used together")
+	}
+	prompt := resumePrompt
+	if resumePromptFile != "" {
+		var data []byte
+		var err error
+		if resumePromptFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(resumePromptFile)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read prompt file: %w", err)
+		}
+		prompt = string(data)
+	}
+
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		if resumePromptFile != "" {
+			return "", fmt.Errorf("prompt file is empty: %s", resumePromptFile)
+		}
+	}
+	return prompt, nil
+}
+
+// loadDeltaPrompt は前回の実行後にIssueに追加されたコメントをプロンプトにする
+// 新しいコメントがなければ defaultResumePrompt を返す
+func loadDeltaPrompt(ctx context.Context, taskSvc *github.TaskService, task *domain.Task) (string, error) {
+	local, err := runlog.LoadCursor(task.ID)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	fmt.Println("📥 Loading new comments from the issue...")
+	found, err := taskSvc.LoadDeltaPrompt(ctx, task, local)
+	if err != nil {
+		return "", fmt.Errorf("failed to load prompt: %w", err)
+	}
+	if !found {
+		fmt.Println("   No new comments since the last run")
+		return defaultResumePrompt, nil
+	}
+	fmt.Println("💬 Sending the new feedback to the session")
+	return task.Prompt, nil
+}
+
+func init() {
+	resumeCmd.Flags().StringVarP(&resumePrompt, "prompt", "p", "", "Instruction to send to the resumed session")
+	resumeCmd.Flags().StringVar(&resumePromptFile, "prompt-file", "", "Read the instruction from a file (- for stdin)")
+	resumeCmd.Flags().BoolVar(&resumeDryRun, "dry-run", false, "Preview execution without running")
+	resumeCmd.Flags().DurationVar(&resumeTimeout, "timeout", 0, "Timeout for the task (default: the issue form's timeout, or 30m)")
+
+	rerunCmd.Flags().BoolVar(&rerunFresh, "fresh", false, "Clear the stored SessionID and Result before running")
+	rerunCmd.Flags().BoolVar(&rerunDryRun, "dry-run", false, "Preview without changing or running the task")
+	rerunCmd.Flags().DurationVar(&rerunTimeout, "timeout", 0, "Timeout for the task (default: the issue form's timeout, or 30m)")
+}
@@ -1,16 +1,23 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/auth"
 	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/github"
 )
 
 var (
-	cfg     *config.Config
-	verbose bool
+	cfg          *config.Config
+	verbose      bool
+	profile      string
+	setOverrides []string
+	refresh      bool
 )
 
 // rootCmd はルートコマンド
@@ -23,14 +30,30 @@ It fetches tasks from GitHub Projects, executes them using Claude Code,
 and updates the results back to the project.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		var err error
-		cfg, err = config.LoadWithPrecedence()
+		cfg, err = config.LoadWithPrecedence(loadOptions())
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+		for _, w := range cfg.Warnings {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", w)
+		}
+		// 読み込みに失敗しても他の取得元や auth login/logout は使えるよう警告に留める
+		if err := auth.Resolve(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
 		return nil
 	},
 }
 
+// loadOptions はコマンドラインと環境変数から設定の読み込みオプションを作る
+func loadOptions() config.LoadOptions {
+	name := profile
+	if name == "" {
+		name = os.Getenv("VIBE_PROFILE")
+	}
+	return config.LoadOptions{Profile: name, Overrides: setOverrides}
+}
+
 // Execute はCLIを実行する
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -41,11 +64,53 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile to use (default: $VIBE_PROFILE or default_profile)")
+	rootCmd.PersistentFlags().StringArrayVar(&setOverrides, "set", nil, "Override a config key for this run (key=value, repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "Fetch the project ID and fields again instead of using the cache")
 
 	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(projectCmd)
 	rootCmd.AddCommand(taskCmd)
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(rerunCmd)
 	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(uiCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(usageCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(initCmd)
+}
+
+// newGitHubClient は設定された認証方式でGitHubクライアントを作成する
+func newGitHubClient(owner string) (*github.Client, error) {
+	return newGitHubClientFor(cfg, owner)
+}
+
+// newTaskService はProjectのメタデータのキャッシュ（~/.vibe/cache）を使うTaskServiceを作成する
+// --refresh が指定された場合と forceRefresh が true の場合は、キャッシュを読まずに取得し直して保存する
+func newTaskService(client *github.Client, number int, forceRefresh bool) *github.TaskService {
+	taskSvc := github.NewTaskService(client, number)
+	taskSvc.SetFailureStatus(cfg.Retry.FailureStatusOrDefault())
+	dir, err := config.Dir()
+	if err != nil {
+		return taskSvc
+	}
+	taskSvc.SetCache(&github.MetadataCache{
+		Dir:     filepath.Join(dir, "cache"),
+		TTL:     github.DefaultMetadataTTL,
+		Refresh: refresh || forceRefresh,
+	})
+	return taskSvc
+}
+
+// newGitHubClientFor は指定された設定（プロファイル）でGitHubクライアントを作成する
+func newGitHubClientFor(c *config.Config, owner string) (*github.Client, error) {
+	src, err := auth.TokenSource(context.Background(), c, owner)
+	if err != nil {
+		return nil, err
+	}
+	return github.NewClient(src, c.Host, owner), nil
 }
@@ -1,21 +1,35 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tkc/vibe-project/internal/claude"
+	"github.com/tkc/vibe-project/internal/config"
 	"github.com/tkc/vibe-project/internal/domain"
 	"github.com/tkc/vibe-project/internal/github"
+	"github.com/tkc/vibe-project/internal/hook"
 	"github.com/tkc/vibe-project/internal/notify"
+	"github.com/tkc/vibe-project/internal/prompt"
+	"github.com/tkc/vibe-project/internal/runlog"
+	"github.com/tkc/vibe-project/internal/usage"
 )
 
 var (
-	runDryRun  bool
-	runTimeout time.Duration
+	runDryRun   bool
+	runTimeout  time.Duration
+	runAll      bool
+	runLabels   []string
+	runRepo     string
+	runLimit    int
+	runParallel int
 )
 
 var runCmd = &cobra.Command{
@@ -27,14 +41,33 @@ If no task ID is specified, the first Ready task will be executed.
 The task's Prompt field will be passed to Claude Code,
 and the result will be commented on the associated Issue.
 
+With --all, every executable Ready task is executed in priority order,
+optionally filtered by --label and --repo and capped by --limit.
+A failed task does not stop the others. A summary is printed at the end,
+and the command exits with an error if any task failed.
+
 Examples:
   vibe run              # Run the first Ready task
   vibe run <task-id>    # Run a specific task
-  vibe run --dry-run    # Preview without executing`,
+  vibe run --dry-run    # Preview without executing
+  vibe run --all --label backend --parallel 2`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := cfg.Validate(); err != nil {
 			return err
 		}
+		if runAll && len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with a task ID")
+		}
+		if !runAll && (len(runLabels) > 0 || runRepo != "" || runLimit != 0 || cmd.Flags().Changed("parallel")) {
+			return fmt.Errorf("--label, --repo, --limit and --parallel require --all")
+		}
+		if runParallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+		if runLimit < 0 {
+			return fmt.Errorf("--limit must not be negative")
+		}
 
 		// Claude Codeの確認
 		executor := claude.NewExecutor(cfg.ClaudePath)
@@ -45,17 +78,28 @@ Examples:
 		}
 
 		// GitHub接続
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskSvc := github.NewTaskService(client, cfg.ProjectNumber)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		budget, err := newBudget()
+		if err != nil {
+			return err
+		}
+		taskSvc := newTaskService(client, cfg.ProjectNumber, false)
+		taskSvc.SetAssembler(newAssembler(executor, budget, runDryRun))
 
 		ctx := context.Background()
 		if err := taskSvc.Initialize(ctx); err != nil {
 			return fmt.Errorf("failed to initialize: %w", err)
 		}
 
+		if runAll {
+			return runAllTasks(ctx, taskSvc, executor, budget)
+		}
+
 		// タスク取得
 		var task *domain.Task
-		var err error
 
 		if len(args) > 0 {
 			// 指定されたタスクIDを取得
@@ -75,119 +119,622 @@ Examples:
 			}
 		}
 
-		// WorkDirが空の場合はカレントディレクトリを使用
-		if task.WorkDir == "" {
-			wd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("failed to get current working directory: %w", err)
-			}
-			task.WorkDir = wd
+		if err := prepareTask(ctx, taskSvc, task); err != nil {
+			return err
 		}
 
-		// Issueのコメントからプロンプトを読み込む
-		fmt.Println("📥 Loading prompt from issue comments...")
-		if err := taskSvc.LoadTaskPrompt(ctx, task); err != nil {
-			return fmt.Errorf("failed to load prompt: %w", err)
-		}
+		return runSingleTask(ctx, taskSvc, executor, budget, task, runDryRun, runTimeout)
+	},
+}
 
-		// 実行可能か確認
-		if !task.IsExecutable() {
-			return fmt.Errorf("task is not executable (Status: %s, Prompt: %v)",
-				task.Status, task.Prompt != "")
-		}
+// runSingleTask は実行の準備ができたタスクを1つ実行する（vibe run / rerun / resume）
+// budget はプロンプトの要約にも使ったもので、要約のコストと実行を同じ予算で数える
+func runSingleTask(ctx context.Context, taskSvc *github.TaskService, executor *claude.Executor, budget *usage.Budget, task *domain.Task, dryRun bool, timeout time.Duration) error {
+	fmt.Printf("📋 Task: %s\n", task.Title)
+	fmt.Printf("   ID: %s\n", task.ID)
+	if task.Resume {
+		fmt.Printf("   Session: %s\n", task.SessionID)
+	}
+	fmt.Printf("   WorkDir: %s\n", task.WorkDir)
+	fmt.Printf("   Prompt: %s\n", truncate(task.Prompt, 80))
+	fmt.Println()
+
+	// ドライラン
+	if dryRun {
+		printDryRun(task)
+		return nil
+	}
 
-		fmt.Printf("📋 Task: %s\n", task.Title)
-		fmt.Printf("   ID: %s\n", task.ID)
-		fmt.Printf("   WorkDir: %s\n", task.WorkDir)
-		fmt.Printf("   Prompt: %s\n", truncate(task.Prompt, 80))
+	runner, err := newTaskRunner(taskSvc, executor, budget, timeout)
+	if err != nil {
+		return err
+	}
+	recordSummarization(runner.budget, task, "")
+
+	// 予算の確認
+	if err := runner.budget.Check(time.Now()); err != nil {
+		sendNotification(ctx, runner.notifier, notify.Message{Event: notify.EventBudgetExceeded, Detail: err.Error()})
+		return err
+	}
+
+	exec, err := runner.execute(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	if exec.NeedsInput() {
 		fmt.Println()
+		fmt.Printf("⏸  Waiting for a reply on the issue. Once a trusted user replies, vibe watch (or vibe run %s) resumes the session.\n", task.ID)
+		return nil
+	}
 
-		// ドライラン
-		if runDryRun {
-			fmt.Println("[DRY RUN] Would execute:")
-			fmt.Printf("  claude --print \"%s\"\n", truncate(task.Prompt, 50))
-			return nil
-		}
+	fmt.Println()
+	fmt.Println("🎉 Done!")
+	return nil
+}
 
-		// InProgressに設定
-		fmt.Println("⏳ Setting status to InProgress...")
-		if err := taskSvc.SetTaskInProgress(ctx, task.ID); err != nil {
-			fmt.Printf("   ⚠️  Failed to update status: %v\n", err)
-		}
+// prepareTask は作業ディレクトリとプロンプトを設定し、タスクが実行できるか確認する
+func prepareTask(ctx context.Context, taskSvc *github.TaskService, task *domain.Task) error {
+	// Issueのコメントからプロンプトを読み込む
+	fmt.Println("📥 Loading prompt from issue comments...")
+	if err := loadPrompt(ctx, taskSvc, task); err != nil {
+		return fmt.Errorf("failed to load prompt: %w", err)
+	}
+	if task.Resume {
+		fmt.Println("💬 Resuming the session with the replies to Claude Code's question")
+	}
+	if task.PromptNote != "" {
+		fmt.Printf("✂️  %s\n", task.PromptNote)
+	}
+	if problems := applyIssueForm(task, "", ""); len(problems) > 0 {
+		return rejectIssueForm(ctx, taskSvc, task, problems, runDryRun, "")
+	}
+	if err := setDefaultWorkDir(task); err != nil {
+		return err
+	}
 
-		// 実行オプション
-		opt := &claude.ExecuteOption{
-			Timeout: runTimeout,
+	// 実行可能か確認
+	if task.Status == domain.StatusNeedsInput {
+		return fmt.Errorf("task is waiting for a reply to Claude Code's question on the issue")
+	}
+	if !task.IsExecutable() {
+		return fmt.Errorf("task is not executable (Status: %s, Prompt: %v)",
+			task.Status, task.Prompt != "")
+	}
+	return nil
+}
+
+// newAssembler は設定からIssueのスレッドのプロンプトの組み立て方を作成する
+// ドライランではClaude Codeを実行しないよう、要約せずに切り詰める
+// 予算を使い切っていれば要約せずに切り詰める（要約のコストは recordSummarization で budget に記録する）
+func newAssembler(executor *claude.Executor, budget *usage.Budget, dryRun bool) *prompt.Assembler {
+	a := &prompt.Assembler{
+		MaxTokens:  cfg.Prompt.EffectiveMaxTokens(),
+		KeepRecent: cfg.Prompt.EffectiveKeepRecent(),
+	}
+	if cfg.Prompt.Summarize && !dryRun && executor != nil {
+		a.Summarize = func(ctx context.Context, text string) (string, *domain.Execution, error) {
+			if budget != nil {
+				if err := budget.Check(time.Now()); err != nil {
+					return "", nil, err
+				}
+			}
+			return executor.Summarize(ctx, text)
 		}
+	}
+	return a
+}
 
-		// Claude Code実行
-		fmt.Println("🚀 Executing Claude Code...")
-		exec, err := executor.Execute(ctx, task, opt)
-		if err != nil {
-			return fmt.Errorf("execution error: %w", err)
+// recordSummarization はプロンプトの組み立てでコメントの要約に使った実行を予算に記録し、タスクの累計コストに加える
+// 累計コストはタスクの実行後にProjectのフィールドに書き込まれる
+func recordSummarization(budget *usage.Budget, task *domain.Task, prefix string) {
+	exec := task.Summarization
+	if exec == nil {
+		return
+	}
+	task.Summarization = nil
+	task.Cost += exec.CostUSD
+	if err := budget.Record(task, exec); err != nil {
+		fmt.Printf("%s   ⚠️  Failed to record usage: %v\n", prefix, err)
+	}
+}
+
+// taskTimeout は1タスクのタイムアウトを返す
+// --timeout の指定（flag）、Issueフォームのタイムアウト、claude.DefaultTimeout の順に使う
+func taskTimeout(task *domain.Task, flag time.Duration) time.Duration {
+	switch {
+	case flag > 0:
+		return flag
+	case task.Timeout > 0:
+		return task.Timeout
+	default:
+		return claude.DefaultTimeout
+	}
+}
+
+// setDefaultWorkDir はWorkDirが空の場合にカレントディレクトリを設定する
+func setDefaultWorkDir(task *domain.Task) error {
+	if task.WorkDir != "" {
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	task.WorkDir = wd
+	return nil
+}
+
+// printDryRun は実行せずに、実行するコマンドを表示する
+func printDryRun(task *domain.Task) {
+	hooks := hook.New(cfg.Hooks)
+	verifier := hook.NewVerifier(cfg.Verify)
+
+	fmt.Printf("[DRY RUN] Prompt: ~%d tokens (budget %d)\n", prompt.EstimateTokens(task.Prompt), cfg.Prompt.EffectiveMaxTokens())
+	if cfg.Prompt.Summarize && task.PromptNote != "" {
+		fmt.Println("          Older comments are truncated in dry runs; a real run summarizes them with Claude Code")
+	}
+	fmt.Println("[DRY RUN] Would execute:")
+	for _, c := range hooks.Commands(hook.PreRun) {
+		fmt.Printf("  %s: %s\n", hook.PreRun, c)
+	}
+	if task.Resume {
+		fmt.Printf("  claude --print --resume %s \"%s\"\n", task.SessionID, truncate(task.Prompt, 50))
+	} else {
+		fmt.Printf("  claude --print \"%s\"\n", truncate(task.Prompt, 50))
+	}
+	if task.Model != "" {
+		fmt.Printf("  model: %s\n", task.Model)
+	}
+	if len(task.AllowedTools) > 0 {
+		fmt.Printf("  allowed tools: %s\n", strings.Join(task.AllowedTools, ", "))
+	}
+	if task.Timeout > 0 {
+		fmt.Printf("  timeout (issue form): %s\n", task.Timeout)
+	}
+	if items := checklistItems(task); len(items) > 0 {
+		done, total := domain.ChecklistProgress(items)
+		fmt.Printf("  acceptance criteria: %d item(s), %d/%d checked\n", total, done, total)
+	}
+	if !cfg.Attachments.Disabled {
+		for _, u := range github.AttachmentURLs(task.Comments, cfg.Attachments, cfg.GitHubHost()) {
+			fmt.Printf("  attachment: %s\n", u)
 		}
+	}
+	if cfg.Related.Enabled {
+		for _, c := range task.Comments {
+			for _, u := range domain.ParseIssueRefs(c.Body, task.IssueURL) {
+				fmt.Printf("  related: %s\n", u)
+			}
+		}
+	}
+	if cmds := verifier.Commands(hook.Verify); len(cmds) > 0 {
+		for _, c := range cmds {
+			fmt.Printf("  %s: %s\n", hook.Verify, c)
+		}
+		fmt.Printf("  (resume with the failures until verification passes, up to %d runs)\n", verifyIterations())
+	}
+	for _, stage := range []string{hook.PostRun, hook.OnSuccess, hook.OnFailure} {
+		for _, c := range hooks.Commands(stage) {
+			fmt.Printf("  %s: %s\n", stage, c)
+		}
+	}
+}
 
-		// 結果を表示
-		fmt.Println()
-		if exec.Success {
-			fmt.Printf("✅ Completed (%.1fs)\n", exec.Duration.Seconds())
-			// macOS notification
-			_ = notify.SendSuccess(task.Title, exec.Duration.Seconds())
+// taskRunner はタスクを実行して結果を反映する（vibe run で使う）
+type taskRunner struct {
+	taskSvc  *github.TaskService
+	executor *claude.Executor
+	retry    *claude.RetryPolicy
+	budget   *usage.Budget
+	notifier *notify.Dispatcher
+	hooks    *hook.Runner
+	verifier *hook.Runner
+	timeout  time.Duration // --timeout の指定（0 ならIssueフォームか既定値。1タスクの予算の時間が短ければそちら）
+}
+
+// newTaskRunner は現在の設定からtaskRunnerを作成する
+// budget は newAssembler に渡したものと同じ予算を渡す
+func newTaskRunner(taskSvc *github.TaskService, executor *claude.Executor, budget *usage.Budget, timeout time.Duration) (*taskRunner, error) {
+	notifier, err := notify.New(cfg.Notifiers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier config: %w", err)
+	}
+	retry, err := claude.NewRetryPolicy(cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
+	return &taskRunner{
+		taskSvc:  taskSvc,
+		executor: executor,
+		retry:    retry,
+		budget:   budget,
+		notifier: notifier,
+		hooks:    hook.New(cfg.Hooks),
+		verifier: hook.NewVerifier(cfg.Verify),
+		timeout:  timeout,
+	}, nil
+}
+
+// execute はプロンプトを読み込んだタスクを実行し、Projectのフィールドを更新してIssueにコメントする
+func (r *taskRunner) execute(ctx context.Context, task *domain.Task) (*domain.Execution, error) {
+	// InProgressに設定
+	fmt.Println("⏳ Setting status to InProgress...")
+	if err := r.taskSvc.SetTaskInProgress(ctx, task.ID); err != nil {
+		fmt.Printf("   ⚠️  Failed to update status: %v\n", err)
+	}
+
+	// 実行オプション
+	opt := &claude.ExecuteOption{
+		Timeout:       r.budget.TaskTimeout(taskTimeout(task, r.timeout)),
+		Hooks:         r.hooks,
+		Verify:        r.verifier,
+		MaxIterations: cfg.Verify.MaxIterations,
+		CheckBudget:   r.budget.CheckTask,
+
+		DetectQuestions: !cfg.Input.Disabled,
+		Checklist:       checklistItems(task),
+		Log:             newPrefixWriter("   "),
+	}
+	if task.Resume {
+		opt.SessionID = task.SessionID
+	}
+	expandRelated(ctx, r.taskSvc, task, "")
+	defer attachFiles(ctx, r.taskSvc, task, opt, "")()
+
+	// 実行中の出力を記録する（vibe ui で表示する）
+	live, err := runlog.Start(usage.ProjectKey(cfg.ProjectOwner, cfg.ProjectNumber), task)
+	if err != nil {
+		fmt.Printf("   ⚠️  Failed to record the run: %v\n", err)
+	} else {
+		opt.Output = live
+		defer live.Close()
+	}
+
+	// Claude Code実行
+	fmt.Println("🚀 Executing Claude Code...")
+	sendNotification(ctx, r.notifier, notify.Message{Event: notify.EventStarted, TaskTitle: task.Title, TaskURL: task.IssueURL})
+	exec, err := r.executor.ExecuteWithRetry(ctx, task, opt, r.retry)
+	if err != nil {
+		return nil, fmt.Errorf("execution error: %w", err)
+	}
+	if err := live.Finish(exec); err != nil {
+		fmt.Printf("   ⚠️  %v\n", err)
+	}
+	saveCursor(task, exec, "   ")
+	reportChecklist(ctx, r.taskSvc, task, exec, opt.Checklist, "")
+
+	// 予算を記録
+	exec.BudgetExceeded = r.budget.CheckTask(exec)
+	if err := r.budget.Record(task, exec); err != nil {
+		fmt.Printf("   ⚠️  Failed to record usage: %v\n", err)
+	}
+
+	// 結果を表示
+	fmt.Println()
+	if exec.NeedsInput() {
+		fmt.Printf("❓ Claude Code needs input (%.1fs, $%.2f)\n", exec.Duration.Seconds(), exec.CostUSD)
+		fmt.Printf("   %s\n", truncate(exec.Question, 200))
+	} else if exec.Success {
+		fmt.Printf("✅ Completed (%.1fs, $%.2f)\n", exec.Duration.Seconds(), exec.CostUSD)
+	} else {
+		fmt.Printf("❌ Failed (%.1fs)\n", exec.Duration.Seconds())
+		fmt.Printf("   Error: %s\n", truncate(exec.Error, 100))
+	}
+	sendNotification(ctx, r.notifier, resultMessage(task, exec))
+	if exec.BudgetExceeded != "" {
+		fmt.Printf("   ⚠️  Per-task budget exceeded: %s\n", exec.BudgetExceeded)
+		sendNotification(ctx, r.notifier, notify.Message{Event: notify.EventBudgetExceeded, TaskTitle: task.Title, TaskURL: task.IssueURL, Detail: exec.BudgetExceeded})
+	}
+
+	// Projectのフィールドを更新
+	fmt.Println()
+	fmt.Println("📝 Updating project fields...")
+	if err := r.taskSvc.UpdateTask(ctx, task, exec); err != nil {
+		fmt.Printf("   ⚠️  Failed to update task: %v\n", err)
+	}
+
+	// Issueにコメント
+	if task.IssueURL != "" {
+		fmt.Println("💬 Adding comment to Issue...")
+		comment := buildIssueComment(task, exec)
+		if err := r.taskSvc.AddIssueComment(ctx, task, comment); err != nil {
+			fmt.Printf("   ⚠️  Failed to add comment: %v\n", err)
 		} else {
-			fmt.Printf("❌ Failed (%.1fs)\n", exec.Duration.Seconds())
-			fmt.Printf("   Error: %s\n", truncate(exec.Error, 100))
-			// macOS notification
-			_ = notify.SendFailure(task.Title, exec.Error)
+			fmt.Println("   ✅ Comment added")
 		}
+	}
 
-		// Projectのフィールドを更新
-		fmt.Println()
-		fmt.Println("📝 Updating project fields...")
-		if err := taskSvc.UpdateTask(ctx, task, exec); err != nil {
-			fmt.Printf("   ⚠️  Failed to update task: %v\n", err)
-		}
-
-		// Issueにコメント
-		if task.IssueURL != "" {
-			fmt.Println("💬 Adding comment to Issue...")
-			comment := buildIssueComment(task, exec)
-			if err := taskSvc.AddIssueComment(ctx, task, comment); err != nil {
-				fmt.Printf("   ⚠️  Failed to add comment: %v\n", err)
-			} else {
-				fmt.Println("   ✅ Comment added")
-			}
-		}
+	return exec, nil
+}
 
-		fmt.Println()
-		fmt.Println("🎉 Done!")
-		return nil
-	},
+// prefixWriter は書き込まれた行に prefix をつけて標準出力に表示する
+// claude / hook の進捗を、vibe watch ではProjectの接頭辞つきで表示するために使う
+type prefixWriter struct {
+	prefix string
+	mu     sync.Mutex
+	buf    []byte // 改行がまだ来ていない行
+}
+
+func newPrefixWriter(prefix string) *prefixWriter {
+	return &prefixWriter{prefix: prefix}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		fmt.Print(w.prefix + string(w.buf[:i+1]))
+		w.buf = w.buf[i+1:]
+	}
 }
 
 // buildIssueComment は実行結果からコメントを生成する
 func buildIssueComment(task *domain.Task, exec *domain.Execution) string {
 	status := "✅ Completed"
-	if !exec.Success {
+	switch {
+	case exec.NeedsInput():
+		status = "❓ Needs input"
+	case !exec.Success:
 		status = "❌ Failed"
 	}
 
 	// HTMLコメントでマーカーを追加（プロンプト読み込み時に除外される）
 	comment := fmt.Sprintf(`<!-- vibe-project-comment -->
-## 🤖 vibe Execution Result
+%s## 🤖 vibe Execution Result
 
 | Item | Value |
 |------|-------|
-| Status | %s |
+| Status | %s |%s
 | Duration | %.1fs |
+| Cost | $%.2f (%d tokens) |
+| Attempts | %d |
 | Task | %s |
-
+%s%s%s%s
 ---
-<sub>Auto-generated by vibe-project</sub>`, status, exec.Duration.Seconds(), task.Title)
+<sub>Auto-generated by vibe-project</sub>%s`, questionMarker(exec), status, checklistRow(exec), exec.Duration.Seconds(), exec.CostUSD, exec.Tokens(), exec.Attempts, task.Title,
+		questionSection(exec), checklistSection(exec), verificationSection(exec.Iterations), hooksSection(exec.Hooks), cursorMarker(task))
 
 	return comment
 }
 
+// questionMarker は質問のコメントに付けるマーカーを返す（返信の検出に使う）
+func questionMarker(exec *domain.Execution) string {
+	if !exec.NeedsInput() {
+		return ""
+	}
+	return github.NeedsInputMarker + "\n"
+}
+
+// expandRelated はIssue・コメントで参照されているIssue/PRを "Related context" としてプロンプトに追加する
+func expandRelated(ctx context.Context, taskSvc *github.TaskService, task *domain.Task, prefix string) {
+	if !cfg.Related.Enabled {
+		return
+	}
+	items, failed := taskSvc.LoadRelated(ctx, task, cfg.Related)
+	for _, f := range failed {
+		fmt.Printf("%s   ⚠️  Failed to load related context %s\n", prefix, f)
+	}
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("%s🔗 Added %d related issue(s) and pull request(s) to the prompt\n", prefix, len(items))
+	task.Prompt += "\n\n" + github.RelatedSection(items, cfg.Related.EffectiveMaxChars())
+}
+
+// attachFiles はIssueで参照されている添付ファイルを実行ごとの一時ディレクトリにダウンロードし、
+// ローカルのパスをプロンプトに追加してClaude Codeが読めるようにする。返す関数で一時ディレクトリを削除する
+func attachFiles(ctx context.Context, taskSvc *github.TaskService, task *domain.Task, opt *claude.ExecuteOption, prefix string) func() {
+	if cfg.Attachments.Disabled {
+		return func() {}
+	}
+	urls := github.AttachmentURLs(task.Comments, cfg.Attachments, cfg.GitHubHost())
+	if len(urls) == 0 {
+		return func() {}
+	}
+
+	dir, err := os.MkdirTemp("", "vibe-attachments-")
+	if err != nil {
+		fmt.Printf("%s   ⚠️  Failed to create the attachments dir: %v\n", prefix, err)
+		return func() {}
+	}
+	fmt.Printf("%s📎 Downloading %d attachment(s)...\n", prefix, len(urls))
+	attachments := taskSvc.DownloadAttachments(ctx, task, dir, cfg.Attachments)
+	for _, a := range attachments {
+		if a.Skipped != "" {
+			fmt.Printf("%s   ⚠️  Skipped %s: %s\n", prefix, a.URL, a.Skipped)
+		}
+	}
+
+	task.Prompt += "\n\n" + github.AttachmentSection(attachments)
+	opt.AddDirs = append(opt.AddDirs, dir)
+	return func() { os.RemoveAll(dir) }
+}
+
+// cursorMarker はプロンプトに含めた最後のコメントを示すマーカーを返す（次の再開で新しいコメントだけを送るのに使う）
+func cursorMarker(task *domain.Task) string {
+	if task.PromptCursor == nil {
+		return ""
+	}
+	return "\n" + github.CursorMarker(task.PromptCursor)
+}
+
+// saveCursor はセッションに送ったプロンプトカーソルをローカルに記録する
+// セッションが作られなかった実行では記録しない
+func saveCursor(task *domain.Task, exec *domain.Execution, prefix string) {
+	if task.PromptCursor == nil || exec.SessionID == "" {
+		return
+	}
+	if err := runlog.SaveCursor(task.ID, task.PromptCursor); err != nil {
+		fmt.Printf("%s⚠️  Failed to record the prompt cursor: %v\n", prefix, err)
+	}
+}
+
+// questionSection はClaude Codeの質問をコメント用のMarkdownで返す（質問がなければ空）
+func questionSection(exec *domain.Execution) string {
+	if !exec.NeedsInput() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### ❓ Claude Code needs input\n\n")
+	for _, line := range strings.Split(strings.TrimSpace(exec.Question), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	b.WriteString("\nReply to this issue to answer. Once a trusted user replies, the task returns to Ready and the same session is resumed with the new comments.\n")
+	return b.String()
+}
+
+// loadPrompt はタスクのプロンプトを読み込む
+// Claude Codeの質問に信頼するユーザーが返信していれば返信のみを、なければIssueのコメント全体をプロンプトにする
+func loadPrompt(ctx context.Context, taskSvc *github.TaskService, task *domain.Task) error {
+	replied, err := taskSvc.LoadReplyPrompt(ctx, task, cfg.Input)
+	if err != nil {
+		return err
+	}
+	if !replied {
+		return taskSvc.LoadTaskPrompt(ctx, task)
+	}
+	// 返信があれば Needs input のタスクもReadyに戻して実行する
+	if task.Status == domain.StatusNeedsInput {
+		task.Status = domain.StatusReady
+	}
+	return nil
+}
+
+// maxHookCommentOutput はコメントに載せるフック出力の長さ（超えた分は先頭を省略する）
+const maxHookCommentOutput = 4000
+
+// verificationSection は検証ループの各回をコメント用のMarkdownで返す（検証がなければ空）
+func verificationSection(iterations []domain.Iteration) string {
+	if len(iterations) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### Verification\n\n")
+	b.WriteString("| # | Claude Code | Verification | Cost | Duration |\n")
+	b.WriteString("|---|-------------|--------------|------|----------|\n")
+	for _, it := range iterations {
+		claudeResult, verification := "✅", "-"
+		switch {
+		case !it.Success:
+			// 表が崩れないよう改行と | を置き換える
+			claudeResult = "❌ " + strings.NewReplacer("\n", " ", "|", "\\|").Replace(truncate(it.Error, 60))
+		case it.Verified:
+			verification = "✅ Passed"
+		default:
+			failed := it.Verification[len(it.Verification)-1]
+			verification = "❌ " + hook.Describe(failed)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | $%.2f | %.1fs |\n", it.Number, claudeResult, verification, it.CostUSD, it.Duration.Seconds())
+	}
+
+	for _, it := range iterations {
+		if len(it.Verification) > 0 {
+			fmt.Fprintf(&b, "\n<sub>Iteration %d</sub>\n\n", it.Number)
+			writeHookResults(&b, it.Verification)
+		}
+	}
+	return b.String()
+}
+
+// hooksSection はフックの結果をコメント用のMarkdownで返す（フックがなければ空）
+func hooksSection(results []domain.HookResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### Hooks\n\n")
+	writeHookResults(&b, results)
+	return b.String()
+}
+
+// writeHookResults はフック・検証コマンドの結果を出力つきの折りたたみで書き込む
+func writeHookResults(b *strings.Builder, results []domain.HookResult) {
+	for _, h := range results {
+		mark := "✅"
+		detail := fmt.Sprintf("%.1fs", h.Duration.Seconds())
+		if h.Failed() {
+			mark = "❌"
+			detail = hook.Describe(h) + ", " + detail
+		}
+		fmt.Fprintf(b, "<details><summary>%s <code>%s</code>: <code>%s</code> (%s)</summary>\n\n",
+			mark, h.Stage, html.EscapeString(h.Command), detail)
+
+		output := strings.TrimRight(h.Output, "\n")
+		if output == "" {
+			output = "(no output)"
+		}
+		if runes := []rune(output); len(runes) > maxHookCommentOutput {
+			output = "..." + string(runes[len(runes)-maxHookCommentOutput:])
+		}
+		fence := codeFence(output)
+		fmt.Fprintf(b, "%s\n%s\n%s\n\n</details>\n", fence, output, fence)
+	}
+}
+
+// codeFence は s の中のバッククォートより長いコードフェンスを返す
+func codeFence(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// verifyIterations は検証ループでのClaude Codeの最大実行回数を返す
+func verifyIterations() int {
+	if n := cfg.Verify.MaxIterations; n > 0 {
+		return n
+	}
+	return config.DefaultVerifyMaxIterations
+}
+
+// resultMessage は実行結果から通知メッセージを作成する
+func resultMessage(task *domain.Task, exec *domain.Execution) notify.Message {
+	msg := notify.Message{
+		Event:     notify.EventSucceeded,
+		TaskTitle: task.Title,
+		TaskURL:   task.IssueURL,
+		Duration:  exec.Duration,
+		CostUSD:   exec.CostUSD,
+	}
+	switch {
+	case exec.NeedsInput():
+		msg.Event = notify.EventNeedsInput
+		msg.Detail = exec.Question
+	case !exec.Success:
+		msg.Event = notify.EventFailed
+		msg.Error = exec.Error
+	}
+	return msg
+}
+
+// sendNotification は通知を送信し、失敗した場合は警告を表示する
+func sendNotification(ctx context.Context, notifier *notify.Dispatcher, msg notify.Message) {
+	if err := notifier.Notify(ctx, msg); err != nil {
+		fmt.Printf("   ⚠️  Failed to send notification: %v\n", err)
+	}
+}
+
 func init() {
 	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Preview execution without running")
-	runCmd.Flags().DurationVar(&runTimeout, "timeout", 30*time.Minute, "Timeout for the task")
+	runCmd.Flags().DurationVar(&runTimeout, "timeout", 0, "Timeout for the task (default: the issue form's timeout, or 30m)")
+	runCmd.Flags().BoolVar(&runAll, "all", false, "Execute every executable Ready task")
+	runCmd.Flags().StringSliceVar(&runLabels, "label", nil, "With --all: only tasks whose issue has this label (repeatable)")
+	runCmd.Flags().StringVar(&runRepo, "repo", "", "With --all: only tasks in this repository (owner/name or name)")
+	runCmd.Flags().IntVar(&runLimit, "limit", 0, "With --all: execute at most N tasks (0 for no limit)")
+	runCmd.Flags().IntVar(&runParallel, "parallel", 1, "With --all: number of tasks to execute at a time")
 }
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/claude"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/github"
+	"github.com/tkc/vibe-project/internal/notify"
+	"github.com/tkc/vibe-project/internal/usage"
+)
+
+// batchResult は vibe run --all での1タスクの結果
+type batchResult struct {
+	task     *domain.Task
+	exec     *domain.Execution // 実行しなかった場合は nil
+	err      error             // 実行前・実行中のエラー
+	skipped  string            // 実行しなかった理由（予算超過など）
+	duration time.Duration
+}
+
+// state は結果の分類を返す
+func (r batchResult) state() string {
+	switch {
+	case r.skipped != "":
+		return "skipped"
+	case r.err != nil || r.exec == nil:
+		return "failed"
+	case r.exec.NeedsInput():
+		return "needs input"
+	case r.exec.Success:
+		return "completed"
+	default:
+		return "failed"
+	}
+}
+
+// runAllTasks はフィルタに一致する実行可能なReadyタスクを優先度順に全て実行し、結果の一覧を表示する
+// 失敗したタスクがあっても残りを実行し、最後に失敗があればエラーを返す
+func runAllTasks(ctx context.Context, taskSvc *github.TaskService, executor *claude.Executor, budget *usage.Budget) error {
+	filter := &domain.TaskFilter{Labels: runLabels, Repository: runRepo, Limit: runLimit}
+	tasks, err := taskSvc.GetReadyTasks(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to get ready tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No Ready tasks found")
+		return nil
+	}
+
+	fmt.Printf("📋 %d task(s) to run", len(tasks))
+	if runParallel > 1 {
+		fmt.Printf(" (%d at a time)", runParallel)
+	}
+	fmt.Println()
+	for i, t := range tasks {
+		fmt.Printf("   %d. %s\n", i+1, t.Title)
+	}
+
+	// ドライラン
+	if runDryRun {
+		for i, t := range tasks {
+			fmt.Printf("\n━━ [%d/%d] %s\n", i+1, len(tasks), t.Title)
+			if err := prepareTask(ctx, taskSvc, t); err != nil {
+				fmt.Printf("   ❌ %v\n", err)
+				continue
+			}
+			printDryRun(t)
+		}
+		return nil
+	}
+
+	runner, err := newTaskRunner(taskSvc, executor, budget, runTimeout)
+	if err != nil {
+		return err
+	}
+
+	results := make([]batchResult, len(tasks))
+	slots := make(chan struct{}, runParallel)
+	var wg sync.WaitGroup
+	for i, t := range tasks {
+		slots <- struct{}{}
+
+		// 予算を使い切ったら残りのタスクは実行しない（並行して実行中のタスクの見積もりも含めて確認する）
+		release, err := runner.budget.Reserve(time.Now())
+		if err != nil {
+			<-slots
+			fmt.Printf("\n⏸  %v\n", err)
+			sendNotification(ctx, runner.notifier, notify.Message{Event: notify.EventBudgetExceeded, Detail: err.Error()})
+			for j := i; j < len(tasks); j++ {
+				results[j] = batchResult{task: tasks[j], skipped: "budget exceeded"}
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, t *domain.Task) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer release()
+			fmt.Printf("\n━━ [%d/%d] %s\n", i+1, len(tasks), t.Title)
+			results[i] = runner.runBatchTask(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
+
+	printRunSummary(results)
+
+	var failed, skipped int
+	for _, r := range results {
+		switch r.state() {
+		case "failed":
+			failed++
+		case "skipped":
+			skipped++
+		}
+	}
+	switch {
+	case failed > 0 && skipped > 0:
+		return fmt.Errorf("%d of %d task(s) failed, %d skipped", failed, len(results), skipped)
+	case failed > 0:
+		return fmt.Errorf("%d of %d task(s) failed", failed, len(results))
+	case skipped > 0:
+		return fmt.Errorf("%d of %d task(s) skipped", skipped, len(results))
+	}
+	return nil
+}
+
+// runBatchTask はプロンプトを読み込んでタスクを1つ実行する（エラーは結果に記録する）
+func (r *taskRunner) runBatchTask(ctx context.Context, task *domain.Task) batchResult {
+	start := time.Now()
+	result := batchResult{task: task}
+	err := prepareTask(ctx, r.taskSvc, task)
+	recordSummarization(r.budget, task, "")
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		result.err = err
+	} else if exec, err := r.execute(ctx, task); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		result.err = err
+	} else {
+		result.exec = exec
+	}
+	result.duration = time.Since(start)
+	return result
+}
+
+// printRunSummary は vibe run --all の結果を表で表示する
+func printRunSummary(results []batchResult) {
+	fmt.Println()
+	fmt.Println("📊 Summary")
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tRESULT\tDURATION\tCOST\tPR")
+	fmt.Fprintln(w, "----\t------\t--------\t----\t--")
+
+	counts := make(map[string]int)
+	var cost float64
+	var duration time.Duration
+	for _, r := range results {
+		state := r.state()
+		counts[state]++
+
+		result, elapsed, spent, pr := summaryIcon(state)+" "+state, "-", "-", "-"
+		if r.skipped != "" {
+			result += " (" + r.skipped + ")"
+		}
+		if r.exec != nil {
+			elapsed = fmt.Sprintf("%.1fs", r.exec.Duration.Seconds())
+			spent = fmt.Sprintf("$%.2f", r.exec.CostUSD)
+			cost += r.exec.CostUSD
+			duration += r.exec.Duration
+			if url := r.exec.PullRequestURL(); url != "" {
+				pr = url
+			}
+		} else if r.skipped == "" {
+			elapsed = fmt.Sprintf("%.1fs", r.duration.Seconds())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", truncate(r.task.Title, 40), result, elapsed, spent, pr)
+	}
+	w.Flush()
+
+	var parts []string
+	for _, state := range []string{"completed", "needs input", "failed", "skipped"} {
+		if n := counts[state]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, state))
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Total: %d tasks (%s), %.1fs, $%.2f\n", len(results), strings.Join(parts, ", "), duration.Seconds(), cost)
+
+	// 失敗の理由
+	for _, r := range results {
+		if r.state() != "failed" {
+			continue
+		}
+		reason := ""
+		if r.err != nil {
+			reason = r.err.Error()
+		} else {
+			reason = r.exec.Error
+		}
+		fmt.Printf("   ❌ %s: %s\n", r.task.Title, truncate(reason, 100))
+	}
+}
+
+// summaryIcon は結果の分類のアイコンを返す
+func summaryIcon(state string) string {
+	switch state {
+	case "completed":
+		return "✅"
+	case "needs input":
+		return "❓"
+	case "skipped":
+		return "⏭"
+	default:
+		return "❌"
+	}
+}
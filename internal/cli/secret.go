@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/auth"
+)
+
+// readSecret は端末に表示せずに1行読み込む
+// 標準入力が端末でない場合（パイプなど）はそのまま読み込む
+func readSecret(reader *bufio.Reader) (string, error) {
+	if isTerminal(os.Stdin) && runtime.GOOS != "windows" {
+		if err := stty("-echo"); err == nil {
+			// Ctrl-C で中断された場合もエコーを戻す
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt)
+			done := make(chan struct{})
+			go func() {
+				select {
+				case <-sig:
+					_ = stty("echo")
+					fmt.Println()
+					os.Exit(130)
+				case <-done:
+				}
+			}()
+			defer func() {
+				signal.Stop(sig)
+				close(done)
+				_ = stty("echo")
+				fmt.Println()
+			}()
+		}
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// envTokenSource はトークンが設定されている環境変数名を返す
+func envTokenSource() string {
+	for _, name := range auth.TokenEnvVars {
+		if os.Getenv(name) != "" {
+			return name
+		}
+	}
+	return ""
+}
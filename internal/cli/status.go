@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/tkc/vibe-project/internal/github"
 )
 
 var statusCmd = &cobra.Command{
@@ -17,7 +16,7 @@ var statusListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available status options",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if cfg.GitHubToken == "" {
+		if !cfg.HasCredentials() {
 			return fmt.Errorf("not logged in. Run: vibe auth login")
 		}
 		if cfg.ProjectOwner == "" || cfg.ProjectNumber == 0 {
@@ -25,8 +24,11 @@ var statusListCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskService := github.NewTaskService(client, cfg.ProjectNumber)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskService := newTaskService(client, cfg.ProjectNumber, false)
 
 		if err := taskService.Initialize(ctx); err != nil {
 			return fmt.Errorf("failed to initialize: %w", err)
@@ -53,7 +55,7 @@ var statusFieldsCmd = &cobra.Command{
 	Use:   "fields",
 	Short: "List all project fields",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if cfg.GitHubToken == "" {
+		if !cfg.HasCredentials() {
 			return fmt.Errorf("not logged in. Run: vibe auth login")
 		}
 		if cfg.ProjectOwner == "" || cfg.ProjectNumber == 0 {
@@ -61,8 +63,11 @@ var statusFieldsCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskService := github.NewTaskService(client, cfg.ProjectNumber)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskService := newTaskService(client, cfg.ProjectNumber, false)
 
 		if err := taskService.Initialize(ctx); err != nil {
 			return fmt.Errorf("failed to initialize: %w", err)
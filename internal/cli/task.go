@@ -4,15 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/tkc/vibe-project/internal/domain"
-	"github.com/tkc/vibe-project/internal/github"
 )
 
 var (
 	taskStatusFilter string
+	taskGraphFormat  string
 )
 
 var taskCmd = &cobra.Command{
@@ -28,8 +29,11 @@ var taskListCmd = &cobra.Command{
 			return err
 		}
 
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskSvc := github.NewTaskService(client, cfg.ProjectNumber)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskSvc := newTaskService(client, cfg.ProjectNumber, false)
 
 		ctx := context.Background()
 		if err := taskSvc.Initialize(ctx); err != nil {
@@ -38,7 +42,7 @@ var taskListCmd = &cobra.Command{
 
 		var filter *domain.TaskFilter
 		if taskStatusFilter != "" {
-			status := domain.Status(taskStatusFilter)
+			status := taskSvc.ParseStatus(taskStatusFilter)
 			filter = &domain.TaskFilter{Status: &status}
 		}
 
@@ -84,8 +88,11 @@ var taskShowCmd = &cobra.Command{
 
 		taskID := args[0]
 
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskSvc := github.NewTaskService(client, cfg.ProjectNumber)
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskSvc := newTaskService(client, cfg.ProjectNumber, false)
 
 		ctx := context.Background()
 		if err := taskSvc.Initialize(ctx); err != nil {
@@ -102,6 +109,62 @@ var taskShowCmd = &cobra.Command{
 	},
 }
 
+var taskGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the task dependency graph",
+	Long: `Show the dependency graph of tasks in the project.
+
+Dependencies are read from task lists and "Blocked by" lines in the issue body,
+and from the issues that track the task. A task runs only after all of its
+dependencies are closed.
+
+Examples:
+  vibe task graph                         # Text tree
+  vibe task graph --format dot | dot -Tpng -o graph.png`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskSvc := newTaskService(client, cfg.ProjectNumber, false)
+
+		ctx := context.Background()
+		if err := taskSvc.Initialize(ctx); err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+
+		tasks, err := taskSvc.GetTasks(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		graph := domain.NewGraph(tasks)
+		switch taskGraphFormat {
+		case "text":
+			err = graph.WriteText(os.Stdout)
+		case "dot":
+			err = graph.WriteDOT(os.Stdout)
+		default:
+			return fmt.Errorf("unknown format: %s (text, dot)", taskGraphFormat)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render graph: %w", err)
+		}
+
+		if cycles := graph.Cycles(); len(cycles) > 0 {
+			for _, c := range cycles {
+				fmt.Fprintf(os.Stderr, "⚠️  Dependency cycle: %s\n", strings.Join(c, " -> "))
+			}
+			return fmt.Errorf("%d dependency cycle(s) detected", len(cycles))
+		}
+		return nil
+	},
+}
+
 func printTaskDetail(t *domain.Task) {
 	fmt.Printf("Task: %s\n", t.Title)
 	fmt.Printf("ID:     %s\n", t.ID)
@@ -132,10 +195,29 @@ func printTaskDetail(t *domain.Task) {
 		fmt.Printf("ExecutedAt: %s\n", t.ExecutedAt.Format("2006-01-02 15:04:05"))
 	}
 
+	if t.Priority != nil {
+		fmt.Printf("Priority: %g\n", *t.Priority)
+	}
+
 	if t.IssueURL != "" {
 		fmt.Printf("\nIssue: %s\n", t.IssueURL)
 	}
 
+	if len(t.Dependencies) > 0 {
+		fmt.Println()
+		fmt.Println("Blocked by:")
+		for _, d := range t.Dependencies {
+			mark, note := "[ ]", ""
+			if d.Done {
+				mark = "[x]"
+			}
+			if d.Unresolved {
+				note = " (not found or not accessible)"
+			}
+			fmt.Printf("  %s %s%s\n", mark, d.URL, note)
+		}
+	}
+
 	if t.IsExecutable() {
 		fmt.Println()
 		fmt.Println("This task is executable. Run:")
@@ -151,21 +233,29 @@ func statusIcon(s domain.Status) string {
 		return "◐"
 	case domain.StatusInReview:
 		return "●"
+	case domain.StatusNeedsInput:
+		return "◇"
+	case domain.StatusFailed:
+		return "✕"
 	default:
 		return "?"
 	}
 }
 
+// truncate は s を最大 max 文字に切り詰める（マルチバイト文字の途中では切らない）
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	runes := []rune(s)
+	if len(runes) <= max {
 		return s
 	}
-	return s[:max-3] + "..."
+	return string(runes[:max-3]) + "..."
 }
 
 func init() {
 	taskListCmd.Flags().StringVarP(&taskStatusFilter, "status", "s", "", "Filter by status (Ready, InProgress, InReview)")
+	taskGraphCmd.Flags().StringVarP(&taskGraphFormat, "format", "f", "text", "Output format (text, dot)")
 
 	taskCmd.AddCommand(taskListCmd)
 	taskCmd.AddCommand(taskShowCmd)
+	taskCmd.AddCommand(taskGraphCmd)
 }
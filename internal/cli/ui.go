@@ -0,0 +1,624 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/github"
+	"github.com/tkc/vibe-project/internal/runlog"
+	"github.com/tkc/vibe-project/internal/tui"
+	"github.com/tkc/vibe-project/internal/usage"
+)
+
+var uiRefresh time.Duration
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Interactive board of tasks and running executions",
+	Long: `Open a full-screen board of the project's tasks grouped by status.
+
+Select a task to see its issue prompt and last result, run it, preview it with
+a dry run, resume its Claude Code session, or move it to another status.
+Executions started by vibe run and vibe watch (in any terminal) are marked as
+running, and their live output can be followed.
+
+Keys:
+  ←/→ h/l    Move between status columns
+  ↑/↓ k/j    Move between tasks
+  enter      Show task details (issue prompt and last result)
+  r          Run the task (vibe rerun if it is not Ready)
+  d          Dry run
+  R          Resume the stored Claude Code session (vibe resume)
+  [ / ]      Move the task to the previous / next status
+  1-9        Move the task to the N-th status
+  o          Follow the task's output
+  w          List running executions
+  g          Refresh
+  esc        Back
+  q          Quit`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		client, err := newGitHubClient(cfg.ProjectOwner)
+		if err != nil {
+			return err
+		}
+		taskSvc := newTaskService(client, cfg.ProjectNumber, false)
+		taskSvc.SetAssembler(newAssembler(nil, nil, true))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		fmt.Println("⏳ Loading project...")
+		if err := taskSvc.Initialize(ctx); err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+
+		term, err := tui.Open()
+		if err != nil {
+			return err
+		}
+		b := newBoard(term, taskSvc, usage.ProjectKey(cfg.ProjectOwner, cfg.ProjectNumber))
+		err = b.run(ctx)
+		if cerr := term.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if n := b.activeChildren(); n > 0 {
+			fmt.Printf("ℹ️  %d execution(s) started from the UI continue in the background\n", n)
+		}
+		return err
+	},
+}
+
+// boardView は vibe ui の画面
+type boardView int
+
+const (
+	viewBoard  boardView = iota // ステータスごとのタスク一覧
+	viewDetail                  // タスクの詳細
+	viewOutput                  // 実行中・直近の出力
+	viewRuns                    // 実行中の一覧
+)
+
+// board は vibe ui の状態（イベントループのゴルーチンだけが変更する）
+type board struct {
+	term    *tui.Terminal
+	taskSvc *github.TaskService
+	project string // owner/#number
+	wd      string
+
+	columns []boardColumn
+	col     int
+	rows    map[domain.Status]int // 列ごとの選択行
+	loading bool
+	loaded  time.Time
+
+	view    boardView
+	detail  *taskDetail
+	output  outputSource
+	scroll  int
+	runs    []runlog.Run
+	runRow  int
+	running map[string]runlog.Run // TaskID -> 実行中の記録
+
+	children map[string]*uiChild // このUIから起動した vibe run（TaskID -> プロセス）
+	message  string
+	updates  chan func() // バックグラウンド処理の結果をイベントループで反映する
+}
+
+// boardColumn はステータス1つ分の列
+type boardColumn struct {
+	status domain.Status
+	tasks  []*domain.Task
+}
+
+// taskDetail は詳細画面に表示するタスク（プロンプトは開いたときに読み込む）
+type taskDetail struct {
+	task      *domain.Task
+	prompt    string
+	promptErr error
+	loading   bool
+}
+
+// outputSource は出力画面に表示するファイル
+type outputSource struct {
+	title   string
+	taskID  string
+	console bool // true: UIから起動した vibe run の出力、false: Claude Codeの出力
+}
+
+// uiChild はUIから起動した vibe run のプロセス
+type uiChild struct {
+	action string // run / dry-run / resume
+	title  string
+	done   bool
+}
+
+func newBoard(term *tui.Terminal, taskSvc *github.TaskService, project string) *board {
+	wd, _ := os.Getwd()
+	return &board{
+		term:     term,
+		taskSvc:  taskSvc,
+		project:  project,
+		wd:       wd,
+		rows:     make(map[domain.Status]int),
+		running:  make(map[string]runlog.Run),
+		children: make(map[string]*uiChild),
+		updates:  make(chan func(), 16),
+	}
+}
+
+// run はキー入力・定期更新・バックグラウンド処理の結果を処理し、終了するまで描画を続ける
+func (b *board) run(ctx context.Context) error {
+	keys := b.term.Keys()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	b.reload(ctx)
+	b.refreshRuns()
+	for {
+		b.draw()
+		select {
+		case k, ok := <-keys:
+			if !ok || b.handleKey(ctx, k) {
+				return nil
+			}
+		case f := <-b.updates:
+			f()
+		case <-ticker.C:
+			b.refreshRuns()
+			if uiRefresh > 0 && !b.loading && time.Since(b.loaded) >= uiRefresh {
+				b.reload(ctx)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// async は fn をバックグラウンドで実行し、返した関数をイベントループで実行する
+func (b *board) async(fn func() func()) {
+	go func() {
+		b.updates <- fn()
+	}()
+}
+
+// reload はタスクを読み込み直す
+func (b *board) reload(ctx context.Context) {
+	if b.loading {
+		return
+	}
+	b.loading = true
+	b.async(func() func() {
+		b.taskSvc.ResetDependencyCache()
+		tasks, err := b.taskSvc.GetTasks(ctx, nil)
+		return func() {
+			b.loading = false
+			b.loaded = time.Now()
+			if err != nil {
+				b.message = "✗ Failed to load tasks: " + err.Error()
+				return
+			}
+			b.setTasks(tasks)
+		}
+	})
+}
+
+// setTasks はタスクをStatusフィールドの選択肢の順に列に振り分ける
+func (b *board) setTasks(tasks []*domain.Task) {
+	var selected string
+	if t := b.selected(); t != nil {
+		selected = t.ID
+	}
+
+	var columns []boardColumn
+	index := make(map[domain.Status]int)
+	for _, opt := range b.taskSvc.GetStatusOptions() {
+		status := b.taskSvc.ParseStatus(opt.Name)
+		index[status] = len(columns)
+		columns = append(columns, boardColumn{status: status})
+	}
+	for _, t := range tasks {
+		i, ok := index[t.Status]
+		if !ok {
+			// 選択肢にないステータス（未設定など）も列として表示する
+			i = len(columns)
+			index[t.Status] = i
+			columns = append(columns, boardColumn{status: t.Status})
+		}
+		columns[i].tasks = append(columns[i].tasks, t)
+	}
+	b.columns = columns
+	if b.col >= len(columns) {
+		b.col = max(0, len(columns)-1)
+	}
+
+	// 選択していたタスクを選択し直す
+	for c, column := range columns {
+		for r, t := range column.tasks {
+			if t.ID == selected {
+				b.col = c
+				b.rows[column.status] = r
+			}
+			if b.detail != nil && t.ID == b.detail.task.ID {
+				b.detail.task = t
+			}
+		}
+	}
+}
+
+// refreshRuns は実行中の一覧を読み込み直す（vibe watch など他のプロセスの実行を含む）
+func (b *board) refreshRuns() {
+	runs, err := runlog.Running()
+	if err != nil {
+		return
+	}
+	b.runs = runs
+	b.running = make(map[string]runlog.Run, len(runs))
+	for _, r := range runs {
+		b.running[r.TaskID] = r
+	}
+	if b.runRow >= len(runs) {
+		b.runRow = max(0, len(runs)-1)
+	}
+}
+
+// selected は選択中のタスクを返す（なければ nil）
+func (b *board) selected() *domain.Task {
+	if b.view != viewBoard && b.detail != nil {
+		return b.detail.task
+	}
+	if b.col >= len(b.columns) {
+		return nil
+	}
+	column := b.columns[b.col]
+	row := b.rows[column.status]
+	if row >= len(column.tasks) {
+		return nil
+	}
+	return column.tasks[row]
+}
+
+// handleKey はキー入力を処理する（終了する場合は true）
+func (b *board) handleKey(ctx context.Context, k tui.Key) bool {
+	if k == tui.KeyCtrlC || (k == "q" && b.view == viewBoard) {
+		return true
+	}
+	b.message = ""
+
+	switch b.view {
+	case viewBoard:
+		b.handleBoardKey(ctx, k)
+	case viewDetail:
+		b.handleDetailKey(ctx, k)
+	case viewOutput:
+		b.handleOutputKey(k)
+	case viewRuns:
+		b.handleRunsKey(k)
+	}
+	return false
+}
+
+func (b *board) handleBoardKey(ctx context.Context, k tui.Key) {
+	switch k {
+	case tui.KeyLeft, "h":
+		b.col = max(0, b.col-1)
+	case tui.KeyRight, "l":
+		b.col = min(len(b.columns)-1, b.col+1)
+	case tui.KeyUp, "k":
+		b.moveRow(-1)
+	case tui.KeyDown, "j":
+		b.moveRow(1)
+	case tui.KeyPageUp:
+		b.moveRow(-10)
+	case tui.KeyPageDown:
+		b.moveRow(10)
+	case tui.KeyEnter:
+		if t := b.selected(); t != nil {
+			b.openDetail(ctx, t)
+		}
+	case "w":
+		b.view = viewRuns
+	case "g":
+		b.reload(ctx)
+		b.message = "Refreshing..."
+	default:
+		b.handleTaskKey(ctx, k)
+	}
+}
+
+func (b *board) handleDetailKey(ctx context.Context, k tui.Key) {
+	switch k {
+	case tui.KeyEsc, "q", tui.KeyBackspace:
+		b.view = viewBoard
+		b.detail = nil
+	case tui.KeyUp, "k":
+		b.scroll = max(0, b.scroll-1)
+	case tui.KeyDown, "j":
+		b.scroll++
+	case tui.KeyPageUp:
+		b.scroll = max(0, b.scroll-10)
+	case tui.KeyPageDown:
+		b.scroll += 10
+	case "g":
+		b.openDetail(ctx, b.detail.task)
+	default:
+		b.handleTaskKey(ctx, k)
+	}
+}
+
+func (b *board) handleOutputKey(k tui.Key) {
+	switch k {
+	case tui.KeyEsc, "q", tui.KeyBackspace:
+		if b.detail != nil {
+			b.view = viewDetail
+		} else {
+			b.view = viewBoard
+		}
+	case tui.KeyTab:
+		// Claude Codeの出力と vibe run の出力を切り替える
+		b.output.console = !b.output.console
+	}
+}
+
+func (b *board) handleRunsKey(k tui.Key) {
+	switch k {
+	case tui.KeyEsc, "q", tui.KeyBackspace:
+		b.view = viewBoard
+	case tui.KeyUp, "k":
+		b.runRow = max(0, b.runRow-1)
+	case tui.KeyDown, "j":
+		b.runRow = min(max(0, len(b.runs)-1), b.runRow+1)
+	case tui.KeyEnter, "o":
+		if b.runRow < len(b.runs) {
+			r := b.runs[b.runRow]
+			b.detail = nil
+			b.output = outputSource{title: r.Title, taskID: r.TaskID}
+			b.view = viewOutput
+		}
+	}
+}
+
+// handleTaskKey は一覧・詳細画面で選択中のタスクに対する操作を処理する
+func (b *board) handleTaskKey(ctx context.Context, k tui.Key) {
+	t := b.selected()
+	if t == nil {
+		return
+	}
+	switch k {
+	case "r":
+		b.start(ctx, t, "run")
+	case "d":
+		b.start(ctx, t, "dry-run")
+	case "R":
+		b.start(ctx, t, "resume")
+	case "[":
+		b.shiftStatus(ctx, t, -1)
+	case "]":
+		b.shiftStatus(ctx, t, 1)
+	case "o":
+		b.output = outputSource{title: t.Title, taskID: t.ID, console: b.children[t.ID] != nil}
+		b.view = viewOutput
+	default:
+		if len(k) == 1 && k[0] >= '1' && k[0] <= '9' {
+			if i := int(k[0] - '1'); i < len(b.columns) {
+				b.setStatus(ctx, t, b.columns[i].status)
+			}
+		}
+	}
+}
+
+func (b *board) moveRow(delta int) {
+	if b.col >= len(b.columns) {
+		return
+	}
+	column := b.columns[b.col]
+	row := b.rows[column.status] + delta
+	b.rows[column.status] = max(0, min(len(column.tasks)-1, row))
+}
+
+// openDetail は詳細画面を開き、Issueのコメントからプロンプトを読み込む
+func (b *board) openDetail(ctx context.Context, t *domain.Task) {
+	detail := &taskDetail{task: t, loading: t.IssueURL != ""}
+	b.detail = detail
+	b.view = viewDetail
+	b.scroll = 0
+	if !detail.loading {
+		return
+	}
+	b.async(func() func() {
+		// 一覧のタスクを書き換えないよう複製に読み込む
+		loaded := *t
+		err := b.taskSvc.LoadTaskPrompt(ctx, &loaded)
+		return func() {
+			detail.loading = false
+			detail.prompt = loaded.Prompt
+			detail.promptErr = err
+		}
+	})
+}
+
+// shiftStatus はタスクを隣の列のステータスに移動する
+func (b *board) shiftStatus(ctx context.Context, t *domain.Task, delta int) {
+	for i, column := range b.columns {
+		if column.status == t.Status {
+			if j := i + delta; j >= 0 && j < len(b.columns) {
+				b.setStatus(ctx, t, b.columns[j].status)
+			}
+			return
+		}
+	}
+}
+
+// setStatus はタスクのステータスを変更する（画面には先に反映する）
+func (b *board) setStatus(ctx context.Context, t *domain.Task, status domain.Status) {
+	if t.Status == status || status == "" {
+		return
+	}
+	prev := t.Status
+	b.moveTask(t, status)
+	b.message = fmt.Sprintf("Moving %q to %s...", t.Title, status)
+	b.async(func() func() {
+		err := b.taskSvc.SetTaskStatus(ctx, t.ID, status)
+		return func() {
+			if err != nil {
+				b.moveTask(t, prev)
+				b.message = "✗ Failed to update status: " + err.Error()
+				return
+			}
+			b.message = fmt.Sprintf("✓ Moved %q to %s", t.Title, status)
+		}
+	})
+}
+
+// moveTask はタスクを画面上で別の列に移す（選択中のタスクなら選択も移る）
+func (b *board) moveTask(t *domain.Task, status domain.Status) {
+	var tasks []*domain.Task
+	for _, column := range b.columns {
+		for _, task := range column.tasks {
+			if task != t {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	t.Status = status
+	b.setTasks(append(tasks, t))
+}
+
+// start は vibe run / rerun / resume を子プロセスとして起動し、出力を ~/.vibe/runs/<task-id>.out に書き込む
+// Ready でないタスクの run と dry-run は vibe rerun で Ready に戻してから実行する
+func (b *board) start(ctx context.Context, t *domain.Task, action string) {
+	if _, ok := b.running[t.ID]; ok {
+		b.message = "✗ This task is already running"
+		return
+	}
+	if c := b.children[t.ID]; c != nil && !c.done {
+		b.message = fmt.Sprintf("✗ A %s of this task is still in progress", c.action)
+		return
+	}
+	if t.IssueURL == "" {
+		b.message = "✗ This task has no linked issue to read the prompt from"
+		return
+	}
+	if action == "resume" && t.SessionID == "" {
+		b.message = "✗ This task has no Claude Code session to resume"
+		return
+	}
+
+	path, err := consolePath(t.ID)
+	if err != nil {
+		b.message = "✗ " + err.Error()
+		return
+	}
+	child := &uiChild{action: action, title: t.Title}
+	b.children[t.ID] = child
+	b.output = outputSource{title: t.Title, taskID: t.ID, console: true}
+	b.view = viewOutput
+	rerun := action != "resume" && t.Status != domain.StatusReady
+	if rerun && action == "run" {
+		b.moveTask(t, domain.StatusReady)
+	}
+
+	b.async(func() func() {
+		cmd, out, err := b.command(t.ID, action, rerun, path)
+		if err == nil {
+			err = cmd.Start()
+		}
+		if err != nil {
+			if out != nil {
+				out.Close()
+			}
+			return func() {
+				child.done = true
+				b.message = fmt.Sprintf("✗ Failed to start %s: %v", action, err)
+			}
+		}
+		b.updates <- func() { b.message = fmt.Sprintf("▶ Started %s of %q", action, t.Title) }
+
+		err = cmd.Wait()
+		out.Close()
+		return func() {
+			child.done = true
+			if err != nil {
+				b.message = fmt.Sprintf("✗ %s of %q failed (%v); press o to see the output", action, t.Title, err)
+			} else {
+				b.message = fmt.Sprintf("✓ %s of %q finished", action, t.Title)
+			}
+			b.reload(ctx)
+		}
+	})
+}
+
+// command は vibe run / rerun / resume の子プロセスを作成する（プロファイルと --set は引き継ぐ）
+func (b *board) command(taskID, action string, rerun bool, outPath string) (*exec.Cmd, *os.File, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find the vibe executable: %w", err)
+	}
+	var args []string
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	for _, s := range setOverrides {
+		args = append(args, "--set", s)
+	}
+	switch {
+	case action == "resume":
+		args = append(args, "resume", taskID)
+	case rerun:
+		args = append(args, "rerun", taskID)
+	default:
+		args = append(args, "run", taskID)
+	}
+	if action == "dry-run" {
+		args = append(args, "--dry-run")
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	fmt.Fprintf(out, "$ vibe %s\n\n", strings.Join(args, " "))
+
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = b.wd
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd, out, nil
+}
+
+// activeChildren は実行中の子プロセスの数を返す
+func (b *board) activeChildren() int {
+	n := 0
+	for _, c := range b.children {
+		if !c.done {
+			n++
+		}
+	}
+	return n
+}
+
+// consolePath はUIから起動した vibe run の出力ファイルのパスを返す
+func consolePath(taskID string) (string, error) {
+	path, err := runlog.LogPath(taskID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create runs dir: %w", err)
+	}
+	return strings.TrimSuffix(path, ".log") + ".out", nil
+}
+
+func init() {
+	uiCmd.Flags().DurationVar(&uiRefresh, "refresh", 30*time.Second, "Interval for reloading tasks from GitHub (0 disables)")
+}
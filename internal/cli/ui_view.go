@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/runlog"
+	"github.com/tkc/vibe-project/internal/tui"
+)
+
+// minColumnWidth は一覧画面の列の最小幅（収まらない列は横にスクロールする）
+const minColumnWidth = 24
+
+// draw は現在の画面を描画する
+func (b *board) draw() {
+	width, height := b.term.Size()
+	var lines []string
+	switch b.view {
+	case viewBoard:
+		lines = b.boardLines(width, height)
+	case viewDetail:
+		lines = b.detailLines(width, height)
+	case viewOutput:
+		lines = b.outputLines(width, height)
+	case viewRuns:
+		lines = b.runsLines(width, height)
+	}
+	b.term.Draw(lines)
+}
+
+// header は画面上部の行（左にタイトル、右に状態）を返す
+func (b *board) header(title string, width int) string {
+	left := tui.Style(" vibe ", tui.Bold, tui.Reverse) + " " + tui.Style(title, tui.Bold)
+
+	var info []string
+	if b.loading {
+		info = append(info, "loading…")
+	} else if !b.loaded.IsZero() {
+		info = append(info, "updated "+b.loaded.Format("15:04:05"))
+	}
+	if n := len(b.runs); n > 0 {
+		info = append(info, tui.Style(fmt.Sprintf("● %d running", n), tui.Green))
+	}
+	right := strings.Join(info, "  ") + " "
+
+	gap := width - tui.Width(left) - tui.Width(right)
+	if gap < 1 {
+		return left
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// footer は画面下部のメッセージとキーの説明を返す
+func (b *board) footer(help string) []string {
+	return []string{b.message, tui.Style(" "+help, tui.Dim)}
+}
+
+// screen はヘッダー・本文・フッターを高さ height に収める
+func screen(header string, body []string, footer []string, height int) []string {
+	bodyHeight := max(0, height-1-len(footer))
+	if len(body) > bodyHeight {
+		body = body[:bodyHeight]
+	}
+	lines := append([]string{header}, body...)
+	for len(lines) < height-len(footer) {
+		lines = append(lines, "")
+	}
+	return append(lines, footer...)
+}
+
+func (b *board) boardLines(width, height int) []string {
+	header := b.header(b.project, width)
+	footer := b.footer("←→↑↓ move  enter details  r run  d dry-run  R resume  [ ] 1-9 status  o output  w runs  g refresh  q quit")
+	if len(b.columns) == 0 {
+		body := []string{"", "  Loading tasks…"}
+		if !b.loading {
+			body = []string{"", "  No tasks found"}
+		}
+		return screen(header, body, footer, height)
+	}
+
+	// 収まる数の列を、選択中の列が見えるように表示する
+	visible := max(1, min(len(b.columns), (width+1)/(minColumnWidth+1)))
+	first := max(0, min(b.col-visible/2, len(b.columns)-visible))
+	colWidth := (width - (visible - 1)) / visible
+	rowsHeight := max(1, height-1-2-len(footer))
+	sep := tui.Style("│", tui.Dim)
+
+	titles := make([]string, 0, visible)
+	rules := make([]string, 0, visible)
+	cells := make([][]string, 0, visible)
+	for c := first; c < first+visible; c++ {
+		column := b.columns[c]
+		title := fmt.Sprintf(" %s (%d)", statusName(column.status), len(column.tasks))
+		if c < 9 {
+			title = fmt.Sprintf(" %d %s (%d)", c+1, statusName(column.status), len(column.tasks))
+		}
+		if c == b.col {
+			title = tui.Style(tui.Fit(title, colWidth), tui.Bold, tui.Reverse)
+		} else {
+			title = tui.Style(title, tui.Bold)
+		}
+		titles = append(titles, tui.Fit(title, colWidth))
+		rules = append(rules, tui.Style(strings.Repeat("─", colWidth), tui.Dim))
+		cells = append(cells, b.columnCells(c, colWidth, rowsHeight))
+	}
+
+	body := []string{strings.Join(titles, sep), strings.Join(rules, tui.Style("┼", tui.Dim))}
+	for r := 0; r < rowsHeight; r++ {
+		row := make([]string, len(cells))
+		for i, column := range cells {
+			row[i] = strings.Repeat(" ", colWidth)
+			if r < len(column) {
+				row[i] = column[r]
+			}
+		}
+		body = append(body, strings.Join(row, sep))
+	}
+	return screen(header, body, footer, height)
+}
+
+// columnCells は列 c のタスクを、選択行が見えるようにスクロールして返す
+func (b *board) columnCells(c, width, height int) []string {
+	column := b.columns[c]
+	row := b.rows[column.status]
+	visible := height
+	if len(column.tasks) > height {
+		// 最後の1行を「↓ N more」に使う
+		visible = max(1, height-1)
+	}
+	offset := max(0, row-visible+1)
+
+	var cells []string
+	for i := offset; i < len(column.tasks) && len(cells) < visible; i++ {
+		t := column.tasks[i]
+		mark, styles := " ", []string(nil)
+		switch {
+		case b.isRunning(t.ID):
+			mark = tui.Style("●", tui.Green)
+		case t.IsBlocked():
+			mark = tui.Style("⊘", tui.Yellow)
+			styles = append(styles, tui.Dim)
+		}
+		cell := " " + mark + " " + tui.Style(tui.Truncate(t.Title, width-4), styles...)
+		if c == b.col && i == row {
+			cell = tui.Style(tui.Fit(" "+mark+" "+tui.Truncate(t.Title, width-4), width), tui.Reverse)
+		}
+		cells = append(cells, tui.Fit(cell, width))
+	}
+	if rest := len(column.tasks) - offset - len(cells); rest > 0 {
+		cells = append(cells, tui.Fit(tui.Style(fmt.Sprintf("   ↓ %d more", rest), tui.Dim), width))
+	}
+	return cells
+}
+
+func (b *board) detailLines(width, height int) []string {
+	d := b.detail
+	t := d.task
+	header := b.header(t.Title, width)
+	footer := b.footer("↑↓ scroll  r run  d dry-run  R resume  [ ] 1-9 status  o output  g reload  esc back")
+
+	var body []string
+	field := func(name, value string) {
+		if value != "" {
+			body = append(body, fmt.Sprintf(" %-11s %s", name, value))
+		}
+	}
+	field("Status", statusName(t.Status))
+	if r, ok := b.running[t.ID]; ok {
+		field("Running", tui.Style(fmt.Sprintf("● since %s (pid %d)", r.StartedAt.Format("15:04:05"), r.PID), tui.Green))
+	} else if c := b.children[t.ID]; c != nil && !c.done {
+		field("Running", tui.Style(c.action+" started from this UI", tui.Green))
+	}
+	field("Issue", t.IssueURL)
+	field("ID", t.ID)
+	field("WorkDir", t.WorkDir)
+	field("Session", t.SessionID)
+	if t.ExecutedAt != nil {
+		field("Executed", t.ExecutedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if t.Cost > 0 {
+		field("Cost", fmt.Sprintf("$%.2f", t.Cost))
+	}
+	if t.Attempts > 0 {
+		field("Attempts", fmt.Sprintf("%d", t.Attempts))
+	}
+	if t.Priority != nil {
+		field("Priority", fmt.Sprintf("%g", *t.Priority))
+	}
+	for _, dep := range t.Dependencies {
+		switch {
+		case dep.Unresolved:
+			field("Blocked by", dep.URL+" (not found)")
+		case !dep.Done:
+			field("Blocked by", dep.URL)
+		}
+	}
+
+	section := func(title string) {
+		body = append(body, "", tui.Style(" "+title, tui.Bold, tui.Cyan))
+	}
+	section("Last result")
+	if t.Result == "" {
+		body = append(body, tui.Style("  (not executed yet)", tui.Dim))
+	} else {
+		for _, line := range tui.Wrap(t.Result, width-2) {
+			body = append(body, "  "+line)
+		}
+	}
+
+	section("Prompt (issue comments)")
+	switch {
+	case d.loading:
+		body = append(body, tui.Style("  Loading…", tui.Dim))
+	case d.promptErr != nil:
+		body = append(body, tui.Style("  "+d.promptErr.Error(), tui.Red))
+	case t.IssueURL == "":
+		body = append(body, tui.Style("  (no linked issue)", tui.Dim))
+	default:
+		for _, line := range tui.Wrap(d.prompt, width-2) {
+			body = append(body, "  "+line)
+		}
+	}
+
+	// スクロール位置を本文の範囲に収める
+	bodyHeight := max(1, height-1-len(footer))
+	b.scroll = max(0, min(b.scroll, len(body)-bodyHeight))
+	return screen(header, body[b.scroll:], footer, height)
+}
+
+func (b *board) outputLines(width, height int) []string {
+	src := b.output
+	tabs := []string{" Claude Code ", " vibe run "}
+	active := 0
+	if src.console {
+		active = 1
+	}
+	tabs[active] = tui.Style(tabs[active], tui.Reverse)
+	title := "Output: " + src.title + "  " + strings.Join(tabs, " ")
+	if b.isRunning(src.taskID) {
+		title += "  " + tui.Style("● live", tui.Green)
+	}
+	header := b.header(title, width)
+	footer := b.footer("tab switch Claude Code / vibe run output  esc back")
+
+	path, err := runlog.LogPath(src.taskID)
+	if err == nil && src.console {
+		path, err = consolePath(src.taskID)
+	}
+	var lines []string
+	if err == nil {
+		lines, err = runlog.Tail(path, height)
+	}
+
+	var body []string
+	switch {
+	case err != nil:
+		body = []string{tui.Style(" "+err.Error(), tui.Red)}
+	case len(lines) == 0:
+		body = []string{tui.Style(" (no output yet)", tui.Dim)}
+	}
+	for _, line := range lines {
+		for _, l := range tui.Wrap(line, width-1) {
+			body = append(body, " "+l)
+		}
+	}
+	// 末尾（最新の出力）を表示する
+	if bodyHeight := max(1, height-1-len(footer)); len(body) > bodyHeight {
+		body = body[len(body)-bodyHeight:]
+	}
+	return screen(header, body, footer, height)
+}
+
+func (b *board) runsLines(width, height int) []string {
+	header := b.header(fmt.Sprintf("Running executions (%d)", len(b.runs)), width)
+	footer := b.footer("↑↓ select  enter follow output  esc back")
+
+	if len(b.runs) == 0 {
+		body := []string{"", "  No executions are running. Start one with r on the board, vibe run, or vibe watch."}
+		return screen(header, body, footer, height)
+	}
+
+	body := []string{tui.Style(fmt.Sprintf(" %-18s %-9s %-8s %s", "PROJECT", "ELAPSED", "PID", "TASK"), tui.Bold)}
+	for i, r := range b.runs {
+		elapsed := time.Since(r.StartedAt).Truncate(time.Second)
+		line := fmt.Sprintf(" %-18s %-9s %-8d %s", tui.Truncate(r.Project, 18), elapsed, r.PID, r.Title)
+		if i == b.runRow {
+			line = tui.Style(tui.Fit(line, width), tui.Reverse)
+		}
+		body = append(body, line)
+	}
+	return screen(header, body, footer, height)
+}
+
+// isRunning はタスクが実行中か（他のプロセスの実行・このUIから起動した実行を含む）を返す
+func (b *board) isRunning(taskID string) bool {
+	if _, ok := b.running[taskID]; ok {
+		return true
+	}
+	c := b.children[taskID]
+	return c != nil && !c.done && c.action != "dry-run"
+}
+
+// statusName はステータスの表示名を返す（未設定は "No status"）
+func statusName(s domain.Status) string {
+	if s == "" {
+		return "No status"
+	}
+	return string(s)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/usage"
+)
+
+var (
+	usageBy   string
+	usageDays int
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show spend by day, task, or repository",
+	Long: `Show the cost, tokens, and execution time recorded for past runs.
+
+Spend is recorded locally in ~/.vibe/usage.jsonl from the cost data
+reported by Claude Code, and is used to enforce the budgets in the config.
+
+Examples:
+  vibe usage                # Spend per day for the last 30 days
+  vibe usage --by task      # Spend per task
+  vibe usage --by repo --days 7`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ledger, err := usage.NewLedger()
+		if err != nil {
+			return err
+		}
+
+		records, err := ledger.Records()
+		if err != nil {
+			return err
+		}
+
+		// 期間で絞り込む
+		if usageDays > 0 {
+			since := time.Now().AddDate(0, 0, -usageDays)
+			filtered := records[:0]
+			for _, r := range records {
+				if r.Time.After(since) {
+					filtered = append(filtered, r)
+				}
+			}
+			records = filtered
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No usage recorded")
+			return nil
+		}
+
+		var key func(usage.Record) string
+		var header string
+		switch usageBy {
+		case "day":
+			key, header = usage.Day, "DAY"
+		case "task":
+			key, header = func(r usage.Record) string { return truncate(r.Title, 50) }, "TASK"
+		case "repo":
+			key, header = func(r usage.Record) string {
+				if r.Repo == "" {
+					return "(none)"
+				}
+				return r.Repo
+			}, "REPO"
+		default:
+			return fmt.Errorf("unknown grouping: %s (day, task, repo)", usageBy)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tRUNS\tCOST\tTOKENS\tTIME\n", header)
+		for _, t := range usage.GroupBy(records, key) {
+			fmt.Fprintf(w, "%s\t%d\t$%.2f\t%d\t%s\n", t.Key, t.Runs, t.CostUSD, t.Tokens, t.Duration.Round(time.Second))
+		}
+		w.Flush()
+
+		total := usage.Sum(records, nil)
+		fmt.Println()
+		fmt.Printf("Total: $%.2f, %d tokens, %s (%d runs)\n",
+			total.CostUSD, total.Tokens, total.Duration.Round(time.Second), total.Runs)
+		return nil
+	},
+}
+
+// newBudget は現在のプロジェクトの予算チェッカーを作成する
+func newBudget() (*usage.Budget, error) {
+	ledger, err := usage.NewLedger()
+	if err != nil {
+		return nil, err
+	}
+	return usage.NewBudget(cfg.Budget, ledger, usage.ProjectKey(cfg.ProjectOwner, cfg.ProjectNumber)), nil
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageBy, "by", "day", "Group by (day, task, repo)")
+	usageCmd.Flags().IntVar(&usageDays, "days", 30, "Only include the last N days (0 for all)")
+}
@@ -5,33 +5,50 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tkc/vibe-project/internal/auth"
 	"github.com/tkc/vibe-project/internal/claude"
+	"github.com/tkc/vibe-project/internal/config"
 	"github.com/tkc/vibe-project/internal/domain"
 	"github.com/tkc/vibe-project/internal/github"
+	"github.com/tkc/vibe-project/internal/hook"
 	"github.com/tkc/vibe-project/internal/notify"
+	"github.com/tkc/vibe-project/internal/runlog"
+	"github.com/tkc/vibe-project/internal/usage"
 )
 
 var (
 	watchInterval time.Duration
+	watchWorkers  int
 )
 
+// watchQueueSize は実行待ちキューの長さ（溢れたタスクは次のポーリングで拾う）
+const watchQueueSize = 64
+
 var watchCmd = &cobra.Command{
 	Use:   "watch",
 	Short: "Watch for new tasks and execute them automatically",
-	Long: `Watch the GitHub Project for new Ready tasks and execute them automatically.
+	Long: `Watch GitHub Projects for new Ready tasks and execute them automatically.
 
 This command polls the project at regular intervals, picks up Ready tasks,
-executes them one by one using Claude Code, and updates the results.
+executes them using Claude Code, and updates the results.
+
+When .vibe.yaml lists several projects under "projects:", all of them are
+watched at once. Tasks from every project share one pool of --workers
+workers (default 1, i.e. one task at a time).
 
 Press Ctrl+C to stop watching.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := cfg.Validate(); err != nil {
 			return err
 		}
+		if watchWorkers < 1 {
+			return fmt.Errorf("--workers must be at least 1")
+		}
 
 		// Claude Codeの確認
 		executor := claude.NewExecutor(cfg.ClaudePath)
@@ -39,22 +56,53 @@ Press Ctrl+C to stop watching.`,
 			return fmt.Errorf("claude is not installed: %w", err)
 		}
 
-		// GitHub接続
-		client := github.NewClient(cfg.GitHubToken, cfg.ProjectOwner)
-		taskSvc := github.NewTaskService(client, cfg.ProjectNumber)
-
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		if err := taskSvc.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize: %w", err)
+		// GitHub接続
+		projects, err := watchProjects(ctx, executor)
+		if err != nil {
+			return err
+		}
+
+		retry, err := claude.NewRetryPolicy(cfg.Retry)
+		if err != nil {
+			return err
+		}
+
+		notifier, err := notify.New(cfg.Notifiers)
+		if err != nil {
+			return fmt.Errorf("invalid notifier config: %w", err)
+		}
+
+		w := &watcher{
+			projects: projects,
+			executor: executor,
+			retry:    retry,
+			notifier: notifier,
+			hooks:    hook.New(cfg.Hooks),
+			verifier: hook.NewVerifier(cfg.Verify),
+			jobs:     make(chan watchJob, watchQueueSize),
+			queued:   make(map[string]bool),
 		}
 
-		fmt.Printf("👀 Watching project #%d for new tasks...\n", cfg.ProjectNumber)
+		if len(projects) == 1 {
+			fmt.Printf("👀 Watching project #%d for new tasks...\n", projects[0].number)
+		} else {
+			fmt.Printf("👀 Watching %d projects for new tasks...\n", len(projects))
+			for _, p := range projects {
+				fmt.Printf("   • %s\n", p.name)
+			}
+		}
 		fmt.Printf("   Interval: %s\n", watchInterval)
+		fmt.Printf("   Workers: %d\n", watchWorkers)
 		fmt.Println("   Press Ctrl+C to stop")
 		fmt.Println()
 
+		for i := 0; i < watchWorkers; i++ {
+			go w.work(ctx)
+		}
+
 		// シグナルハンドリング
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -63,12 +111,12 @@ Press Ctrl+C to stop watching.`,
 		defer ticker.Stop()
 
 		// 初回実行
-		processNewTasks(ctx, taskSvc, executor)
+		w.poll(ctx)
 
 		for {
 			select {
 			case <-ticker.C:
-				processNewTasks(ctx, taskSvc, executor)
+				w.poll(ctx)
 			case <-sigCh:
 				fmt.Println("\n👋 Stopping watch...")
 				return nil
@@ -79,66 +127,382 @@ Press Ctrl+C to stop watching.`,
 	},
 }
 
-func processNewTasks(ctx context.Context, taskSvc *github.TaskService, executor *claude.Executor) {
-	status := domain.StatusReady
-	filter := &domain.TaskFilter{Status: &status}
+// watchProjects は監視対象のProjectを初期化する
+// .vibe.yaml の projects があればその全て、なければ現在のProjectのみを監視する
+func watchProjects(ctx context.Context, executor *claude.Executor) ([]*watchedProject, error) {
+	refs := cfg.Projects
+	if len(refs) == 0 {
+		refs = []config.ProjectRef{{Owner: cfg.ProjectOwner, Number: cfg.ProjectNumber}}
+	}
 
-	tasks, err := taskSvc.GetTasks(ctx, filter)
+	ledger, err := usage.NewLedger()
 	if err != nil {
-		fmt.Printf("⚠️  Failed to get tasks: %v\n", err)
-		return
+		return nil, err
 	}
 
-	executableTasks := make([]*domain.Task, 0)
-	for _, t := range tasks {
-		if t.IsExecutable() {
-			executableTasks = append(executableTasks, t)
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	projects := make([]*watchedProject, 0, len(refs))
+	for _, ref := range refs {
+		name := fmt.Sprintf("%s/#%d", ref.Owner, ref.Number)
+
+		// Projectごとにプロファイル（ホスト・認証）を切り替える
+		projectCfg, err := cfg.WithProfile(ref.Profile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if projectCfg.Auth.Token == "" {
+			if err := auth.Resolve(projectCfg); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		client, err := newGitHubClientFor(projectCfg, ref.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		budget := usage.NewBudget(cfg.Budget, ledger, usage.ProjectKey(ref.Owner, ref.Number))
+		taskSvc := newTaskService(client, ref.Number, false)
+		taskSvc.SetAssembler(newAssembler(executor, budget, false))
+		if err := taskSvc.Initialize(ctx); err != nil {
+			return nil, fmt.Errorf("failed to initialize %s: %w", name, err)
+		}
+
+		workDir := ref.WorkDir
+		if workDir == "" {
+			workDir = wd
 		}
+
+		projects = append(projects, &watchedProject{
+			name:    name,
+			number:  ref.Number,
+			taskSvc: taskSvc,
+			budget:  budget,
+			workDir: workDir,
+			label:   len(refs) > 1,
+		})
 	}
+	return projects, nil
+}
 
-	if len(executableTasks) == 0 {
-		timestamp := time.Now().Format("15:04:05")
-		fmt.Printf("[%s] No new tasks\n", timestamp)
-		return
+// watchedProject は監視対象のProjectごとの状態
+type watchedProject struct {
+	name    string // owner/#number
+	number  int
+	taskSvc *github.TaskService
+	budget  *usage.Budget
+	workDir string
+	label   bool // 出力にProject名を付けるか（複数Project監視時）
+
+	mu     sync.Mutex
+	paused bool // 予算超過で一時停止中か
+}
+
+// prefix は出力行の先頭に付けるProject名を返す
+func (p *watchedProject) prefix() string {
+	if !p.label {
+		return ""
 	}
+	return "[" + p.name + "] "
+}
+
+// watchJob は実行待ちのタスク
+type watchJob struct {
+	project *watchedProject
+	task    *domain.Task
+}
 
-	fmt.Printf("📋 Found %d new task(s)\n", len(executableTasks))
+// watcher はwatchモードの状態を保持する
+// ポーリングで見つけたタスクをキューに入れ、ワーカーが並行して実行する
+type watcher struct {
+	projects []*watchedProject
+	executor *claude.Executor
+	retry    *claude.RetryPolicy
+	notifier *notify.Dispatcher
+	hooks    *hook.Runner
+	verifier *hook.Runner
+	jobs     chan watchJob
 
-	opt := &claude.ExecuteOption{
-		Timeout: 30 * time.Minute,
+	mu     sync.Mutex
+	queued map[string]bool // キュー投入済み・実行中のアイテムID
+}
+
+// checkBudget は予算を確認し、使い切っていれば一時停止する
+func (w *watcher) checkBudget(ctx context.Context, p *watchedProject) bool {
+	return w.pauseIf(ctx, p, p.budget.Check(time.Now()))
+}
+
+// reserveBudget は実行中の1回として予算を予約し、予約を解放する関数を返す
+// 複数のワーカーが同じ残りを見て予算を超えないよう、実行中のタスクの見積もりも含めて確認する
+// 使い切っていれば一時停止して nil を返す
+func (w *watcher) reserveBudget(ctx context.Context, p *watchedProject) func() {
+	release, err := p.budget.Reserve(time.Now())
+	if !w.pauseIf(ctx, p, err) {
+		return nil
 	}
+	return release
+}
 
-	for _, task := range executableTasks {
-		fmt.Printf("▶  Executing: %s\n", task.Title)
+// pauseIf は予算の確認結果 err に応じて一時停止・再開し、実行してよければ true を返す
+// 一時停止に入ったときだけ通知する
+func (w *watcher) pauseIf(ctx context.Context, p *watchedProject, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-		// InProgressに設定
-		if err := taskSvc.SetTaskInProgress(ctx, task.ID); err != nil {
-			fmt.Printf("   ⚠️  Failed to update status: %v\n", err)
+	if err == nil {
+		if p.paused {
+			fmt.Printf("%s▶  Budget available again, resuming\n", p.prefix())
 		}
+		p.paused = false
+		return true
+	}
+
+	timestamp := time.Now().Format("15:04:05")
+	fmt.Printf("[%s] %s⏸  Paused: %v\n", timestamp, p.prefix(), err)
+	if !p.paused {
+		w.notify(ctx, notify.Message{Event: notify.EventBudgetExceeded, Detail: err.Error()})
+	}
+	p.paused = true
+	return false
+}
+
+func (w *watcher) notify(ctx context.Context, msg notify.Message) {
+	sendNotification(ctx, w.notifier, msg)
+}
 
-		// 実行
-		exec, err := executor.Execute(ctx, task, opt)
+// poll は全Projectから実行可能なタスクを取得してキューに入れる
+func (w *watcher) poll(ctx context.Context) {
+	for _, p := range w.projects {
+		// 予算を使い切っている場合は一時停止
+		if !w.checkBudget(ctx, p) {
+			continue
+		}
+
+		// 依存先の状態はポーリングごとに取り直す
+		p.taskSvc.ResetDependencyCache()
+
+		// 質問に返信があったタスクをReadyに戻す
+		w.resumeAnswered(ctx, p)
+
+		// 依存関係を満たしたタスクを優先度順に取得
+		executableTasks, err := p.taskSvc.GetReadyTasks(ctx, nil)
 		if err != nil {
-			fmt.Printf("   ❌ Error: %v\n", err)
+			fmt.Printf("%s⚠️  Failed to get tasks: %v\n", p.prefix(), err)
 			continue
 		}
 
-		// 結果を更新
-		if err := taskSvc.UpdateTask(ctx, task, exec); err != nil {
-			fmt.Printf("   ⚠️  Failed to update task: %v\n", err)
+		added := 0
+		for _, task := range executableTasks {
+			if w.enqueue(watchJob{project: p, task: task}) {
+				added++
+			}
 		}
 
-		if exec.Success {
-			fmt.Printf("   ✅ Done (%.1fs)\n", exec.Duration.Seconds())
-			_ = notify.SendSuccess(task.Title, exec.Duration.Seconds())
-		} else {
-			fmt.Printf("   ❌ Failed: %s\n", truncate(exec.Error, 100))
-			_ = notify.SendFailure(task.Title, exec.Error)
+		if added == 0 {
+			timestamp := time.Now().Format("15:04:05")
+			fmt.Printf("[%s] %sNo new tasks\n", timestamp, p.prefix())
+			continue
 		}
+		fmt.Printf("%s📋 Found %d new task(s)\n", p.prefix(), added)
+	}
+}
+
+// resumeAnswered は Needs input のタスクのうち、信頼するユーザーが質問に返信したものをReadyに戻す
+// 返信のみをプロンプトにしたセッションの再開は、Readyのタスクとして実行するときに行う（loadPrompt）
+func (w *watcher) resumeAnswered(ctx context.Context, p *watchedProject) {
+	status := domain.StatusNeedsInput
+	tasks, err := p.taskSvc.GetTasks(ctx, &domain.TaskFilter{Status: &status})
+	if err != nil {
+		fmt.Printf("%s⚠️  Failed to get tasks waiting for input: %v\n", p.prefix(), err)
+		return
+	}
+	for _, task := range tasks {
+		replied, err := p.taskSvc.LoadReplyPrompt(ctx, task, cfg.Input)
+		if err != nil {
+			fmt.Printf("%s⚠️  Failed to check replies for %s: %v\n", p.prefix(), task.Title, err)
+			continue
+		}
+		if !replied {
+			continue
+		}
+		if err := p.taskSvc.SetTaskStatus(ctx, task.ID, domain.StatusReady); err != nil {
+			fmt.Printf("%s⚠️  Failed to update status: %v\n", p.prefix(), err)
+			continue
+		}
+		fmt.Printf("%s💬 Reply received, back to Ready: %s\n", p.prefix(), task.Title)
+	}
+}
+
+// enqueue はタスクをキューに入れる（投入済み・実行中やキューが満杯の場合は false）
+func (w *watcher) enqueue(job watchJob) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.queued[job.task.ID] {
+		return false
+	}
+	select {
+	case w.jobs <- job:
+		w.queued[job.task.ID] = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *watcher) done(taskID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.queued, taskID)
+}
+
+// work はキューからタスクを取り出して実行するワーカー
+func (w *watcher) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-w.jobs:
+			w.execute(ctx, job.project, job.task)
+			w.done(job.task.ID)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh はキューに入れた時点のタスクを取り直し、まだ実行できれば返す（できなければ nil）
+// キューで待っている間に、別の vibe や手作業で Status が変わったり依存先が開き直されたりすることがある
+// 返信待ちから再開するタスクも resumeAnswered で Ready に戻してからキューに入れるため、Ready だけを実行する
+func (w *watcher) refresh(ctx context.Context, p *watchedProject, queued *domain.Task) *domain.Task {
+	task, err := p.taskSvc.GetTask(ctx, queued.ID)
+	if err != nil {
+		fmt.Printf("%s⚠️  Failed to refresh %s: %v\n", p.prefix(), queued.Title, err)
+		return nil
+	}
+	switch {
+	case task.Status != domain.StatusReady:
+		fmt.Printf("%s⏭️  Skipped %s: now %s\n", p.prefix(), task.Title, task.Status)
+		return nil
+	case task.IsBlocked():
+		fmt.Printf("%s⏭️  Skipped %s: blocked by open dependencies\n", p.prefix(), task.Title)
+		return nil
+	}
+	return task
+}
+
+// execute は1つのタスクを実行して結果を反映する
+func (w *watcher) execute(ctx context.Context, p *watchedProject, queued *domain.Task) {
+	task := w.refresh(ctx, p, queued)
+	if task == nil {
+		return
+	}
+	release := w.reserveBudget(ctx, p)
+	if release == nil {
+		return
+	}
+	defer release()
+
+	prefix := p.prefix()
+	fmt.Printf("%s▶  Executing: %s\n", prefix, task.Title)
+
+	err := loadPrompt(ctx, p.taskSvc, task)
+	recordSummarization(p.budget, task, prefix)
+	if err != nil {
+		fmt.Printf("%s   ❌ Failed to load prompt: %v\n", prefix, err)
+		return
+	}
+	if task.Resume {
+		fmt.Printf("%s   💬 Resuming the session with the replies to Claude Code's question\n", prefix)
+	}
+	if task.PromptNote != "" {
+		fmt.Printf("%s   ✂️  %s\n", prefix, task.PromptNote)
+	}
+	if problems := applyIssueForm(task, p.workDir, prefix+"   "); len(problems) > 0 {
+		rejectIssueForm(ctx, p.taskSvc, task, problems, false, prefix+"   ")
+		return
+	}
+	if task.WorkDir == "" {
+		task.WorkDir = p.workDir
+	}
+
+	// InProgressに設定
+	if err := p.taskSvc.SetTaskInProgress(ctx, task.ID); err != nil {
+		fmt.Printf("%s   ⚠️  Failed to update status: %v\n", prefix, err)
+	}
+	w.notify(ctx, notify.Message{Event: notify.EventStarted, TaskTitle: task.Title, TaskURL: task.IssueURL})
+
+	// 実行
+	opt := &claude.ExecuteOption{
+		Timeout:       p.budget.TaskTimeout(taskTimeout(task, 0)),
+		Hooks:         w.hooks,
+		Verify:        w.verifier,
+		MaxIterations: cfg.Verify.MaxIterations,
+		CheckBudget:   p.budget.CheckTask,
+
+		DetectQuestions: !cfg.Input.Disabled,
+		Checklist:       checklistItems(task),
+		Log:             newPrefixWriter(prefix + "   "),
+	}
+	if task.Resume {
+		opt.SessionID = task.SessionID
+	}
+	expandRelated(ctx, p.taskSvc, task, prefix+"   ")
+	defer attachFiles(ctx, p.taskSvc, task, opt, prefix+"   ")()
+	// 実行中の出力を記録する（vibe ui で表示する）
+	live, err := runlog.Start(p.name, task)
+	if err != nil {
+		fmt.Printf("%s   ⚠️  Failed to record the run: %v\n", prefix, err)
+	} else {
+		opt.Output = live
+		defer live.Close()
+	}
+	exec, err := w.executor.ExecuteWithRetry(ctx, task, opt, w.retry)
+	if err != nil {
+		fmt.Printf("%s   ❌ Error: %v\n", prefix, err)
+		return
+	}
+	if err := live.Finish(exec); err != nil {
+		fmt.Printf("%s   ⚠️  %v\n", prefix, err)
+	}
+	saveCursor(task, exec, prefix+"   ")
+	reportChecklist(ctx, p.taskSvc, task, exec, opt.Checklist, prefix+"   ")
+
+	// 予算を記録
+	exec.BudgetExceeded = p.budget.CheckTask(exec)
+	if err := p.budget.Record(task, exec); err != nil {
+		fmt.Printf("%s   ⚠️  Failed to record usage: %v\n", prefix, err)
+	}
+
+	// 結果を更新
+	if err := p.taskSvc.UpdateTask(ctx, task, exec); err != nil {
+		fmt.Printf("%s   ⚠️  Failed to update task: %v\n", prefix, err)
+	}
+
+	// vibe run と同じ結果のコメント（フック・検証・受け入れ基準・プロンプトカーソルを含む）を追加する
+	if task.IssueURL != "" {
+		if err := p.taskSvc.AddIssueComment(ctx, task, buildIssueComment(task, exec)); err != nil {
+			fmt.Printf("%s   ⚠️  Failed to add comment: %v\n", prefix, err)
+		}
+	}
+
+	switch {
+	case exec.NeedsInput():
+		fmt.Printf("%s   ❓ Needs input: %s: %s\n", prefix, task.Title, truncate(exec.Question, 100))
+	case exec.Success:
+		fmt.Printf("%s   ✅ Done: %s (%.1fs, $%.2f)\n", prefix, task.Title, exec.Duration.Seconds(), exec.CostUSD)
+	default:
+		fmt.Printf("%s   ❌ Failed: %s: %s\n", prefix, task.Title, truncate(exec.Error, 100))
+	}
+	w.notify(ctx, resultMessage(task, exec))
+	if exec.BudgetExceeded != "" {
+		fmt.Printf("%s   ⚠️  Per-task budget exceeded: %s\n", prefix, exec.BudgetExceeded)
+		w.notify(ctx, notify.Message{Event: notify.EventBudgetExceeded, TaskTitle: task.Title, TaskURL: task.IssueURL, Detail: exec.BudgetExceeded})
 	}
-	fmt.Println()
 }
 
 func init() {
 	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 5*time.Minute, "Polling interval")
+	watchCmd.Flags().IntVarP(&watchWorkers, "workers", "w", 1, "Number of tasks to execute concurrently across all projects")
 }
@@ -0,0 +1,61 @@
+package config
+
+import "strings"
+
+// AttachmentsConfig はIssueで参照されている画像・添付ファイルのダウンロード設定
+// ダウンロードしたファイルは実行ごとの一時ディレクトリに置き、プロンプトでローカルのパスを伝える
+type AttachmentsConfig struct {
+	Disabled     bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`           // 添付ファイルをダウンロードしない
+	AllowedHosts []string `json:"allowed_hosts,omitempty" yaml:"allowed_hosts,omitempty"` // ダウンロードを許可するホスト（省略時は DefaultAttachmentHosts）
+	MaxSizeMB    int      `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"`     // 1ファイルの上限（MB）
+	MaxTotalMB   int      `json:"max_total_mb,omitempty" yaml:"max_total_mb,omitempty"`   // 1回の実行の合計の上限（MB）
+}
+
+// DefaultAttachmentHosts はGitHubにアップロードされた画像・ファイルのホスト
+// github.com は /user-attachments/ などの添付ファイルのパスのみをダウンロードする
+var DefaultAttachmentHosts = []string{
+	"github.com",
+	"user-images.githubusercontent.com",
+	"private-user-images.githubusercontent.com",
+}
+
+// 添付ファイルのデフォルトの上限
+const (
+	DefaultAttachmentMaxSizeMB  = 10
+	DefaultAttachmentMaxTotalMB = 50
+)
+
+// IsZero は設定されていないかどうかを返す
+func (a AttachmentsConfig) IsZero() bool {
+	return !a.Disabled && len(a.AllowedHosts) == 0 && a.MaxSizeMB == 0 && a.MaxTotalMB == 0
+}
+
+// Allows はホストからのダウンロードを許可するかどうかを返す
+func (a AttachmentsConfig) Allows(host string) bool {
+	hosts := a.AllowedHosts
+	if len(hosts) == 0 {
+		hosts = DefaultAttachmentHosts
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSize は1ファイルの上限（バイト）を返す
+func (a AttachmentsConfig) MaxSize() int64 {
+	if a.MaxSizeMB > 0 {
+		return int64(a.MaxSizeMB) << 20
+	}
+	return DefaultAttachmentMaxSizeMB << 20
+}
+
+// MaxTotal は1回の実行の合計の上限（バイト）を返す
+func (a AttachmentsConfig) MaxTotal() int64 {
+	if a.MaxTotalMB > 0 {
+		return int64(a.MaxTotalMB) << 20
+	}
+	return DefaultAttachmentMaxTotalMB << 20
+}
@@ -0,0 +1,60 @@
+package config
+
+import "time"
+
+// 認証方式
+const (
+	AuthMethodToken = "token" // Personal Access Token（デフォルト）
+	AuthMethodOAuth = "oauth" // OAuth デバイスフロー
+	AuthMethodApp   = "app"   // GitHub App のインストールトークン
+)
+
+// トークンの保存先
+const (
+	TokenStoreKeyring = "keyring" // OSのキーリング
+	TokenStoreFile    = "file"    // 暗号化ファイル (~/.vibe/token.enc。鍵は VIBE_TOKEN_PASSPHRASE から導出する)
+)
+
+// AuthConfig はGitHubの認証設定
+type AuthConfig struct {
+	Method     string `json:"method,omitempty"`      // token / oauth / app（空なら token）
+	TokenStore string `json:"token_store,omitempty"` // keyring / file（vibe auth login の保存先）
+	TokenEnv   string `json:"token_env,omitempty"`   // トークンを読み込む環境変数（プロファイルごとの指定用）
+
+	// 解決済みのトークンと取得元（保存しない）
+	Token       string `json:"-"`
+	TokenSource string `json:"-"` // env:VIBE_GITHUB_TOKEN / keyring / file / config / gh
+
+	// OAuth デバイスフロー
+	OAuthClientID string    `json:"oauth_client_id,omitempty"`
+	TokenExpiry   time.Time `json:"token_expiry,omitzero"` // 有効期限つきトークンの場合のみ
+
+	// GitHub App
+	AppID             int64  `json:"app_id,omitempty"`
+	AppPrivateKeyPath string `json:"app_private_key_path,omitempty"` // PEM形式の秘密鍵ファイル
+	AppInstallationID int64  `json:"app_installation_id,omitempty"`  // 省略時はProjectのownerから検索
+}
+
+// AuthMethod は使用する認証方式を返す
+func (c *Config) AuthMethod() string {
+	if c.Auth.Method == "" {
+		return AuthMethodToken
+	}
+	return c.Auth.Method
+}
+
+// Token は解決済みのトークンを返す（未解決なら設定ファイルの github_token）
+func (c *Config) Token() string {
+	if c.Auth.Token != "" {
+		return c.Auth.Token
+	}
+	return c.GitHubToken
+}
+
+// HasCredentials は認証情報が設定されているかどうかを返す
+func (c *Config) HasCredentials() bool {
+	if c.AuthMethod() == AuthMethodApp && c.Auth.AppID != 0 && c.Auth.AppPrivateKeyPath != "" {
+		return true
+	}
+	return c.Token() != ""
+}
@@ -0,0 +1,27 @@
+package config
+
+// BudgetConfig は無人実行のための予算設定
+// 各上限は 0 のとき無制限として扱う。上限は実行の合間（実行前・リトライや検証の再開の前）に確認するため、
+// 実行中の1回が上限を超えた分は止めない（ソフトな上限。per_task.time だけはタイムアウトにも使う）
+type BudgetConfig struct {
+	PerTask BudgetLimit `json:"per_task,omitzero" yaml:"per_task"` // 1タスクあたりの上限
+	Daily   BudgetLimit `json:"daily,omitzero" yaml:"daily"`       // 1日あたりの上限（ローカル時刻で日付を区切る）
+	Project BudgetLimit `json:"project,omitzero" yaml:"project"`   // プロジェクト全体の累計上限
+}
+
+// BudgetLimit は金額・トークン数・実行時間の上限
+type BudgetLimit struct {
+	USD    float64  `json:"usd,omitempty" yaml:"usd,omitempty"`       // 金額（ドル）
+	Tokens int      `json:"tokens,omitempty" yaml:"tokens,omitempty"` // 入出力トークン数の合計
+	Time   Duration `json:"time,omitzero" yaml:"time,omitempty"`      // 実行時間の合計
+}
+
+// IsZero は上限が設定されていないかどうかを返す
+func (l BudgetLimit) IsZero() bool {
+	return l.USD == 0 && l.Tokens == 0 && l.Time.Duration == 0
+}
+
+// IsZero は予算が一切設定されていないかどうかを返す
+func (b BudgetConfig) IsZero() bool {
+	return b.PerTask.IsZero() && b.Daily.IsZero() && b.Project.IsZero()
+}
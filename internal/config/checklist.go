@@ -0,0 +1,13 @@
+package config
+
+// ChecklistConfig はIssue本文の受け入れ基準（- [ ] のタスクリスト）の扱い
+// Claude Codeに項目ごとの達成状況を報告させ、完了した項目をIssue本文でチェックする
+type ChecklistConfig struct {
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`   // 達成状況の報告を求めない
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"` // Issue本文のチェックボックスを更新しない（達成状況の表示のみ）
+}
+
+// IsZero は設定されていないかどうかを返す
+func (c ChecklistConfig) IsZero() bool {
+	return !c.Disabled && !c.ReadOnly
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,16 +18,81 @@ type Config struct {
 	ProjectOwner  string `json:"project_owner" yaml:"project_owner"`   // org or user
 	ProjectNumber int    `json:"project_number" yaml:"project_number"` // project number
 	ClaudePath    string `json:"claude_path" yaml:"claude_path"`       // claude コマンドのパス
+
+	Budget BudgetConfig `json:"budget,omitzero" yaml:"budget,omitempty"` // 予算設定
+	Retry  RetryConfig  `json:"retry,omitzero" yaml:"retry,omitempty"`   // リトライ設定
+
+	Notifiers []NotifierConfig `json:"notifiers,omitempty" yaml:"notifiers,omitempty"` // 通知先
+	Hooks     HooksConfig      `json:"hooks,omitzero" yaml:"hooks,omitempty"`          // 実行前後のフック
+	Verify    VerifyConfig     `json:"verify,omitzero" yaml:"verify,omitempty"`        // 実行後の検証
+	Input     InputConfig      `json:"input,omitzero" yaml:"input,omitempty"`          // Claude Codeの質問の扱い
+
+	Attachments AttachmentsConfig `json:"attachments,omitzero" yaml:"attachments,omitempty"` // Issueの添付ファイルのダウンロード
+	Related     RelatedConfig     `json:"related,omitzero" yaml:"related,omitempty"`         // 参照されているIssue/PRの展開
+	Prompt      PromptConfig      `json:"prompt,omitzero" yaml:"prompt,omitempty"`           // プロンプトの大きさ
+	IssueForm   IssueFormConfig   `json:"issue_form,omitzero" yaml:"issue_form,omitempty"`   // Issueフォームの項目とタスクの設定の対応
+	Checklist   ChecklistConfig   `json:"checklist,omitzero" yaml:"checklist,omitempty"`     // 受け入れ基準の達成状況の報告
+
+	Auth AuthConfig `json:"auth,omitzero" yaml:"-"`  // 認証設定（グローバル設定のみ）
+	Host string     `json:"host,omitempty" yaml:"-"` // GitHub Enterprise Server のホスト（空なら github.com）
+
+	Profiles       map[string]Profile `json:"profiles,omitempty" yaml:"-"`        // 名前つきプロファイル
+	DefaultProfile string             `json:"default_profile,omitempty" yaml:"-"` // --profile 未指定時のプロファイル
+
+	Projects []ProjectRef `json:"-" yaml:"-"` // .vibe.yaml の projects（vibe watch が監視する）
+
+	profile string   // 使用中のプロファイル名
+	base    identity // プロファイル適用前のトップレベルの値（保存時に戻す）
+
+	Warnings []string `json:"-" yaml:"-"` // 未知のキーなど読み込み時の警告
+
+	global    *Config             // 読み込んだ時点のグローバル設定（保存時に .vibe.yaml などの値を書き込まないため）
+	loadedID  identity            // グローバル設定・プロファイルを適用した時点の値
+	layeredID identity            // 全てのレイヤーを適用した時点の値
+	origins   map[string][]Origin // キーごとの値の出どころ
 }
 
 // ProjectConfig はYAMLファイル用のプロジェクト設定
 type ProjectConfig struct {
-	Project struct {
-		URL    string `yaml:"url"`    // GitHub Project URL (優先)
-		Owner  string `yaml:"owner"`  // 後方互換性のため残す
-		Number int    `yaml:"number"` // 後方互換性のため残す
-	} `yaml:"project"`
-	ClaudePath string `yaml:"claude_path,omitempty"`
+	Version    int              `yaml:"version,omitempty"` // スキーマバージョン（SchemaVersion）
+	Project    ProjectEntry     `yaml:"project"`
+	Projects   []ProjectEntry   `yaml:"projects,omitempty"` // 複数のProjectを監視する場合
+	Profile    string           `yaml:"profile,omitempty"`  // このリポジトリで使うプロファイル
+	ClaudePath string           `yaml:"claude_path,omitempty"`
+	Budget     *BudgetConfig    `yaml:"budget,omitempty"`
+	Retry      *RetryConfig     `yaml:"retry,omitempty"`
+	Notifiers  []NotifierConfig `yaml:"notifiers,omitempty"`
+	Hooks      *HooksConfig     `yaml:"hooks,omitempty"`
+	Verify     *VerifyConfig    `yaml:"verify,omitempty"`
+	Input      *InputConfig     `yaml:"input,omitempty"`
+
+	Attachments *AttachmentsConfig `yaml:"attachments,omitempty"`
+	Related     *RelatedConfig     `yaml:"related,omitempty"`
+	Prompt      *PromptConfig      `yaml:"prompt,omitempty"`
+	IssueForm   *IssueFormConfig   `yaml:"issue_form,omitempty"`
+	Checklist   *ChecklistConfig   `yaml:"checklist,omitempty"`
+}
+
+// ProjectEntry は .vibe.yaml のProject指定
+type ProjectEntry struct {
+	URL     string `yaml:"url"`                // GitHub Project URL (優先)
+	Owner   string `yaml:"owner"`              // 後方互換性のため残す
+	Number  int    `yaml:"number"`             // 後方互換性のため残す
+	Profile string `yaml:"profile,omitempty"`  // projects のみ: このProjectで使うプロファイル
+	WorkDir string `yaml:"work_dir,omitempty"` // projects のみ: Claude Codeを実行するディレクトリ
+}
+
+// ref は owner/number を解決したProjectRefを返す
+func (e ProjectEntry) ref() (ProjectRef, error) {
+	ref := ProjectRef{Owner: e.Owner, Number: e.Number, Profile: e.Profile, WorkDir: e.WorkDir}
+	if e.URL != "" {
+		owner, number, err := ParseProjectURL(e.URL)
+		if err != nil {
+			return ProjectRef{}, fmt.Errorf("failed to parse project URL: %w", err)
+		}
+		ref.Owner, ref.Number = owner, number
+	}
+	return ref, nil
 }
 
 // DefaultClaudePath はデフォルトのclaudeコマンドパス
@@ -43,120 +109,300 @@ const yamlConfigFileName = ".vibe.yaml"
 
 // Load は設定ファイルを読み込む（後方互換性のため残す）
 func Load() (*Config, error) {
+	cfg, _, err := loadGlobal()
+	return cfg, err
+}
+
+// loadGlobal はグローバル設定と、値の設定されたキーのパス（JSON上の名前）を返す
+func loadGlobal() (*Config, []string, error) {
 	path, err := configPath()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	cfg := Config{ClaudePath: DefaultClaudePath}
+	var present []string
+
 	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Config{ClaudePath: DefaultClaudePath}, nil
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config: %w", err)
 		}
-		return nil, fmt.Errorf("failed to read config: %w", err)
-	}
-
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err == nil {
+			present = flattenKeys(raw, "")
+		}
+	case !os.IsNotExist(err):
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
 	if cfg.ClaudePath == "" {
 		cfg.ClaudePath = DefaultClaudePath
 	}
 
-	return &cfg, nil
+	global := cfg
+	cfg.global = &global
+	return &cfg, present, nil
+}
+
+// LoadOptions は設定の読み込みオプション
+type LoadOptions struct {
+	Profile   string   // 使用するプロファイル（空なら .vibe.yaml の profile または default_profile）
+	Overrides []string // コマンドラインで指定された key=value
 }
 
-// LoadWithPrecedence は優先順位に従って設定を読み込む
-// 1. カレントディレクトリの .vibe.yaml (最優先)
-// 2. グローバル設定 ~/.vibe/config.json
-func LoadWithPrecedence() (*Config, error) {
-	// まずグローバル設定を読み込む
-	globalCfg, err := Load()
+// LoadWithPrecedence は優先順位に従って設定を読み込む（後のものが優先）
+//  1. デフォルト値
+//  2. グローバル設定 ~/.vibe/config.json
+//  3. プロファイル（opts.Profile > .vibe.yaml の profile > default_profile）
+//  4. カレントディレクトリから上位に探索した .vibe.yaml
+//  5. 環境変数 VIBE_*（例: VIBE_BUDGET_DAILY_USD）
+//  6. コマンドラインの --set key=value
+//
+// キーごとにどのレイヤーが値を設定したかを記録する（Origins / SourceOf）
+func LoadWithPrecedence(opts LoadOptions) (*Config, error) {
+	cfg, present, err := loadGlobal()
 	if err != nil {
 		return nil, err
 	}
-
-	// プロジェクトローカルの設定を探す
-	yamlPath, err := findProjectConfig()
+	globalPath, err := configPath()
 	if err != nil {
-		// YAML設定が見つからない場合はグローバル設定のみを使用
-		return globalCfg, nil
+		return nil, err
 	}
 
-	// YAML設定を読み込んでマージ
-	localCfg, err := loadYAML(yamlPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load YAML config from %s: %w", yamlPath, err)
+	cfg.origins = map[string][]Origin{
+		"claude_path": {{Source: Source{Layer: LayerDefault}, Value: DefaultClaudePath}},
+	}
+	presentSet := make(map[string]bool, len(present))
+	for _, p := range present {
+		presentSet[p] = true
+	}
+	for _, k := range Keys {
+		// ゼロ値（保存時に書き出される空の値）は設定されていないものとして扱う
+		if k.JSON != "" && presentSet[k.JSON] && k.Value(cfg) != "" {
+			cfg.record(k, Source{Layer: LayerGlobal, Location: globalPath})
+		}
+	}
+
+	// プロジェクトローカルの設定を探す（見つからなければグローバル設定のみ）
+	var local *localConfig
+	if yamlPath, err := FindProjectConfig(); err == nil {
+		local, err = loadYAML(yamlPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Warnings = append(cfg.Warnings, local.warnings...)
 	}
 
-	// ローカル設定で上書き（GitHubトークンはグローバル設定を優先）
-	merged := globalCfg
-	if localCfg.ProjectOwner != "" {
-		merged.ProjectOwner = localCfg.ProjectOwner
+	profile := opts.Profile
+	if profile == "" && local != nil {
+		profile = local.profile
+	}
+	if err := cfg.UseProfile(profile); err != nil {
+		return nil, err
+	}
+	if cfg.profile != "" {
+		src := Source{Layer: LayerProfile, Location: fmt.Sprintf("%s (profile %s)", globalPath, cfg.profile)}
+		for _, k := range Keys {
+			if identityKeys[k.Name] && k.Value(cfg) != "" {
+				cfg.record(k, src)
+			}
+		}
 	}
-	if localCfg.ProjectNumber != 0 {
-		merged.ProjectNumber = localCfg.ProjectNumber
+	cfg.loadedID = cfg.identity()
+
+	// ローカル設定で上書き（認証情報はグローバル設定を優先）
+	if local != nil {
+		src := Source{Layer: LayerLocal, Location: local.path}
+		for _, k := range Keys {
+			if local.present[k.Name] {
+				k.copyValue(cfg, local.cfg)
+				cfg.record(k, src)
+			}
+		}
+		cfg.Projects = local.cfg.Projects
 	}
-	if localCfg.ClaudePath != "" {
-		merged.ClaudePath = localCfg.ClaudePath
+
+	// 環境変数で上書き
+	for _, k := range Keys {
+		v, ok := os.LookupEnv(k.EnvVar())
+		if !ok || v == "" {
+			continue
+		}
+		if err := cfg.apply(k, v, Source{Layer: LayerEnv, Location: k.EnvVar()}); err != nil {
+			return nil, fmt.Errorf("%s: %w", k.EnvVar(), err)
+		}
 	}
+	cfg.Warnings = append(cfg.Warnings, unknownEnvVars()...)
 
-	return merged, nil
+	// コマンドラインで上書き
+	for _, o := range opts.Overrides {
+		name, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", o)
+		}
+		k, ok := LookupKey(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: unknown key %q", o, name)
+		}
+		if err := cfg.apply(k, value, Source{Layer: LayerFlag, Location: "--set " + name}); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.layeredID = cfg.identity()
+	return cfg, nil
+}
+
+// apply は文字列の値でキーを上書きして記録する
+// project.url のような入力用キーは展開先のキーとして記録する
+func (c *Config) apply(k Key, value string, src Source) error {
+	if err := k.Set(c, value); err != nil {
+		return err
+	}
+	for _, name := range k.expands() {
+		target, _ := LookupKey(name)
+		c.record(target, src)
+	}
+	return nil
+}
+
+// expands はキーを設定したときに値が変わるキーを返す
+func (k Key) expands() []string {
+	if k.Name == "project.url" {
+		return []string{"project.owner", "project.number"}
+	}
+	return []string{k.Name}
+}
+
+// localConfig は .vibe.yaml の読み込み結果
+type localConfig struct {
+	path     string
+	cfg      *Config
+	present  map[string]bool // 値の設定されたキー（project.url は owner/number に展開）
+	profile  string          // 使用するプロファイル
+	warnings []string
 }
 
 // loadYAML はYAMLファイルから設定を読み込む
-func loadYAML(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+// スキーマに合わない設定は行番号つきのエラーとして全て返す
+func loadYAML(path string) (*localConfig, error) {
+	doc, err := readYAMLDoc(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read YAML config: %w", err)
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
+	root := doc.Content[0]
 
-	var projectCfg ProjectConfig
-	if err := yaml.Unmarshal(data, &projectCfg); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	projectCfg, problems := checkDocument(path, root)
+	if err := problems.Err(); err != nil {
+		return nil, err
 	}
 
-	var owner string
-	var number int
+	var raw map[string]any
+	if err := root.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse YAML config: %w", path, err)
+	}
+	paths := flattenKeys(raw, "")
+	present := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		if k, ok := LookupKey(p); ok {
+			for _, name := range k.expands() {
+				present[name] = true
+			}
+		}
+	}
 
 	// URLが指定されている場合はURLからパース（優先）
-	if projectCfg.Project.URL != "" {
-		owner, number, err = ParseProjectURL(projectCfg.Project.URL)
+	// 後方互換性: owner/number が直接指定されている場合はそのまま使う
+	project, err := projectCfg.Project.ref()
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []ProjectRef
+	for i, e := range projectCfg.Projects {
+		ref, err := e.ref()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse project URL: %w", err)
+			return nil, fmt.Errorf("projects[%d]: %w", i, err)
+		}
+		if ref.Owner == "" || ref.Number == 0 {
+			return nil, fmt.Errorf("projects[%d]: url or owner/number is required", i)
+		}
+		if ref.WorkDir != "" && !filepath.IsAbs(ref.WorkDir) {
+			ref.WorkDir = filepath.Join(filepath.Dir(path), ref.WorkDir)
+		}
+		projects = append(projects, ref)
+	}
+
+	// project が未指定なら projects の先頭を単一Project用のコマンドで使う
+	profile := projectCfg.Profile
+	if project.Owner == "" && len(projects) > 0 {
+		project = projects[0]
+		present["project.owner"], present["project.number"] = true, true
+		if profile == "" {
+			profile = project.Profile
 		}
-	} else {
-		// 後方互換性: owner/number が直接指定されている場合
-		owner = projectCfg.Project.Owner
-		number = projectCfg.Project.Number
 	}
 
 	cfg := &Config{
-		ProjectOwner:  owner,
-		ProjectNumber: number,
+		ProjectOwner:  project.Owner,
+		ProjectNumber: project.Number,
 		ClaudePath:    projectCfg.ClaudePath,
+		Projects:      projects,
+		Notifiers:     projectCfg.Notifiers,
 	}
-
-	if cfg.ClaudePath == "" {
-		cfg.ClaudePath = DefaultClaudePath
+	if projectCfg.Budget != nil {
+		cfg.Budget = *projectCfg.Budget
+	}
+	if projectCfg.Retry != nil {
+		cfg.Retry = *projectCfg.Retry
+	}
+	if projectCfg.Hooks != nil {
+		cfg.Hooks = *projectCfg.Hooks
+	}
+	if projectCfg.Verify != nil {
+		cfg.Verify = *projectCfg.Verify
+	}
+	if projectCfg.Input != nil {
+		cfg.Input = *projectCfg.Input
+	}
+	if projectCfg.Attachments != nil {
+		cfg.Attachments = *projectCfg.Attachments
+	}
+	if projectCfg.Related != nil {
+		cfg.Related = *projectCfg.Related
+	}
+	if projectCfg.Prompt != nil {
+		cfg.Prompt = *projectCfg.Prompt
+	}
+	if projectCfg.IssueForm != nil {
+		cfg.IssueForm = *projectCfg.IssueForm
+	}
+	if projectCfg.Checklist != nil {
+		cfg.Checklist = *projectCfg.Checklist
 	}
 
-	return cfg, nil
+	return &localConfig{
+		path:     path,
+		cfg:      cfg,
+		present:  present,
+		profile:  profile,
+		warnings: problems.Warnings(),
+	}, nil
 }
 
 // ParseProjectURL はGitHub Project URLからowner と project number を抽出する
-// 対応形式:
+// 対応形式（GitHub Enterprise Server のホストも可）:
 //   - https://github.com/users/{owner}/projects/{number}
 //   - https://github.com/users/{owner}/projects/{number}/views/{view}
 //   - https://github.com/orgs/{owner}/projects/{number}
 //   - https://github.com/orgs/{owner}/projects/{number}/views/{view}
 func ParseProjectURL(url string) (owner string, number int, err error) {
-	// ユーザープロジェクト: https://github.com/users/{owner}/projects/{number}...
-	userPattern := regexp.MustCompile(`^https://github\.com/users/([^/]+)/projects/(\d+)`)
-	// 組織プロジェクト: https://github.com/orgs/{owner}/projects/{number}...
-	orgPattern := regexp.MustCompile(`^https://github\.com/orgs/([^/]+)/projects/(\d+)`)
+	// ユーザープロジェクト: https://{host}/users/{owner}/projects/{number}...
+	userPattern := regexp.MustCompile(`^https://[^/]+/users/([^/]+)/projects/(\d+)`)
+	// 組織プロジェクト: https://{host}/orgs/{owner}/projects/{number}...
+	orgPattern := regexp.MustCompile(`^https://[^/]+/orgs/([^/]+)/projects/(\d+)`)
 
 	if matches := userPattern.FindStringSubmatch(url); len(matches) == 3 {
 		owner = matches[1]
@@ -173,8 +419,8 @@ func ParseProjectURL(url string) (owner string, number int, err error) {
 	return "", 0, fmt.Errorf("invalid GitHub Project URL format: %s", url)
 }
 
-// findProjectConfig はカレントディレクトリから上位ディレクトリへ .vibe.yaml を探索
-func findProjectConfig() (string, error) {
+// FindProjectConfig はカレントディレクトリから上位ディレクトリへ .vibe.yaml を探索する
+func FindProjectConfig() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
@@ -209,7 +455,7 @@ func (c *Config) Save() error {
 		return fmt.Errorf("failed to create config dir: %w", err)
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(c.forSave(), "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -223,7 +469,7 @@ func (c *Config) Save() error {
 
 // Validate は設定が有効かどうかを検証する
 func (c *Config) Validate() error {
-	if c.GitHubToken == "" {
+	if !c.HasCredentials() {
 		return fmt.Errorf("github_token is required. Run: vibe auth login")
 	}
 	if c.ProjectOwner == "" || c.ProjectNumber == 0 {
@@ -234,13 +480,81 @@ func (c *Config) Validate() error {
 
 // IsConfigured はプロジェクトが設定済みかどうかを返す
 func (c *Config) IsConfigured() bool {
-	return c.GitHubToken != "" && c.ProjectOwner != "" && c.ProjectNumber > 0
+	return c.HasCredentials() && c.ProjectOwner != "" && c.ProjectNumber > 0
 }
 
-func configPath() (string, error) {
+// Dir はグローバル設定ディレクトリ (~/.vibe) のパスを返す
+func Dir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home dir: %w", err)
 	}
-	return filepath.Join(home, configDirName, configFileName), nil
+	return filepath.Join(home, configDirName), nil
+}
+
+func configPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName), nil
+}
+
+// ProjectConfigPath は dir 直下の .vibe.yaml のパスを返す
+func ProjectConfigPath(dir string) string {
+	return filepath.Join(dir, yamlConfigFileName)
+}
+
+// ReadProjectURL は .vibe.yaml に書かれた project.url を返す（ファイルがなければ空）
+func ReadProjectURL(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read YAML config: %w", err)
+	}
+
+	var projectCfg ProjectConfig
+	if err := yaml.Unmarshal(data, &projectCfg); err != nil {
+		return "", fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	return projectCfg.Project.URL, nil
+}
+
+// SaveProjectURL は .vibe.yaml の project.url を書き込む
+// 既存ファイルのコメントや他の設定は維持し、owner/number は URL に置き換える
+func SaveProjectURL(path, projectURL string) error {
+	return SetLocal(path, "project.url", projectURL)
+}
+
+// mappingValue はマッピングノードから key の値ノードを返す
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue はマッピングノードの key に値を設定する
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// deleteMappingKey はマッピングノードから key を削除する
+func deleteMappingKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
 }
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWithPrecedence(t *testing.T) {
+	// layers 個のレイヤー（global, profile, .vibe.yaml, VIBE_*, --set の順）が値を設定する
+	// プロファイルは claude_path を持たないので、claude_path の出どころはグローバル設定のまま
+	tests := []struct {
+		name       string
+		layers     int
+		number     string
+		numberFrom string
+		claude     string
+		claudeFrom string
+	}{
+		{name: "defaults", layers: 0, claude: DefaultClaudePath, claudeFrom: LayerDefault},
+		{name: "global", layers: 1, number: "1", numberFrom: LayerGlobal, claude: "/global/claude", claudeFrom: LayerGlobal},
+		{name: "profile", layers: 2, number: "2", numberFrom: LayerProfile, claude: "/global/claude", claudeFrom: LayerGlobal},
+		{name: "project file", layers: 3, number: "3", numberFrom: LayerLocal, claude: "/local/claude", claudeFrom: LayerLocal},
+		{name: "environment", layers: 4, number: "4", numberFrom: LayerEnv, claude: "/env/claude", claudeFrom: LayerEnv},
+		{name: "set flag", layers: 5, number: "5", numberFrom: LayerFlag, claude: "/flag/claude", claudeFrom: LayerFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			project := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Chdir(project)
+
+			var opts LoadOptions
+			if tt.layers >= 1 {
+				global := `{"project_owner": "acme", "project_number": 1, "claude_path": "/global/claude",
+					"profiles": {"work": {"project_owner": "acme", "project_number": 2}}}`
+				if err := os.MkdirAll(filepath.Join(home, configDirName), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(home, configDirName, configFileName), []byte(global), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.layers >= 2 {
+				opts.Profile = "work"
+			}
+			if tt.layers >= 3 {
+				local := "version: 1\nproject:\n  owner: acme\n  number: 3\nclaude_path: /local/claude\n"
+				if err := os.WriteFile(ProjectConfigPath(project), []byte(local), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.layers >= 4 {
+				t.Setenv("VIBE_PROJECT_NUMBER", "4")
+				t.Setenv("VIBE_CLAUDE_PATH", "/env/claude")
+			}
+			if tt.layers >= 5 {
+				opts.Overrides = []string{"project.number=5", "claude_path=/flag/claude"}
+			}
+
+			cfg, err := LoadWithPrecedence(opts)
+			if err != nil {
+				t.Fatalf("LoadWithPrecedence() returned an error: %v", err)
+			}
+
+			number, _ := LookupKey("project.number")
+			if got := number.Value(cfg); got != tt.number {
+				t.Errorf("project.number = %q, want %q", got, tt.number)
+			}
+			if got := cfg.SourceOf("project.number").Layer; got != tt.numberFrom {
+				t.Errorf("project.number comes from %q, want %q", got, tt.numberFrom)
+			}
+			if cfg.ClaudePath != tt.claude {
+				t.Errorf("claude_path = %q, want %q", cfg.ClaudePath, tt.claude)
+			}
+			if got := cfg.SourceOf("claude_path").Layer; got != tt.claudeFrom {
+				t.Errorf("claude_path comes from %q, want %q", got, tt.claudeFrom)
+			}
+
+			// 値を設定した全てのレイヤーが優先順位の低い順に記録される
+			layers, values := []string{}, []string{}
+			for _, o := range cfg.Origins("project.number") {
+				layers = append(layers, o.Source.Layer)
+				values = append(values, o.Value)
+			}
+			allLayers := []string{LayerGlobal, LayerProfile, LayerLocal, LayerEnv, LayerFlag}
+			allValues := []string{"1", "2", "3", "4", "5"}
+			if want := allLayers[:tt.layers]; !reflect.DeepEqual(layers, want) {
+				t.Errorf("project.number origins = %q, want %q", layers, want)
+			}
+			if want := allValues[:tt.layers]; !reflect.DeepEqual(values, want) {
+				t.Errorf("project.number origin values = %q, want %q", values, want)
+			}
+		})
+	}
+}
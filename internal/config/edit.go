@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetLocal は .vibe.yaml のキーに値を書き込む（空文字列の場合はキーを削除する）
+// 既存ファイルのコメントや他の設定は維持する
+func SetLocal(path, key, value string) error {
+	k, err := FindKey(key)
+	if err != nil {
+		return err
+	}
+	if !k.Local {
+		return fmt.Errorf("%s cannot be set in %s (use --global)", key, yamlConfigFileName)
+	}
+	// 書き込む前に値を検証する
+	if err := k.Set(&Config{}, value); err != nil {
+		return err
+	}
+
+	doc, err := readYAMLDoc(path)
+	if err != nil {
+		return err
+	}
+	root := doc.Content[0]
+	if len(root.Content) == 0 {
+		// 新しいファイルには現在のスキーマバージョンを書く
+		setMappingValue(root, "version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(SchemaVersion)})
+	}
+
+	segments := strings.Split(key, ".")
+	if segments[0] == "project" {
+		if err := normalizeProject(root, segments[1]); err != nil {
+			return err
+		}
+	}
+
+	if value == "" {
+		deleteNested(root, segments)
+	} else {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &node); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		setNested(root, segments, node.Content[0])
+	}
+
+	return writeYAMLDoc(path, doc)
+}
+
+// normalizeProject は project.url と project.owner/number が混在しないように揃える
+// url を設定する場合は owner/number を削除し、owner/number を設定する場合は url を展開する
+func normalizeProject(root *yaml.Node, field string) error {
+	project := mappingValue(root, "project")
+	if project == nil || project.Kind != yaml.MappingNode {
+		return nil
+	}
+	if field == "url" {
+		deleteMappingKey(project, "owner")
+		deleteMappingKey(project, "number")
+		return nil
+	}
+
+	url := mappingValue(project, "url")
+	if url == nil || url.Value == "" {
+		return nil
+	}
+	owner, number, err := ParseProjectURL(url.Value)
+	if err != nil {
+		return err
+	}
+	deleteMappingKey(project, "url")
+	setMappingValue(project, "owner", &yaml.Node{Kind: yaml.ScalarNode, Value: owner})
+	setMappingValue(project, "number", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(number)})
+	return nil
+}
+
+// SetGlobal はグローバル設定（プロファイル使用中はプロファイル）のキーに値を設定する
+// .vibe.yaml・環境変数・--set で上書きされた値は保存しない。保存には Save を呼ぶ
+func (c *Config) SetGlobal(key, value string) error {
+	k, err := FindKey(key)
+	if err != nil {
+		return err
+	}
+
+	if identityKeys[k.expands()[0]] {
+		var scratch Config
+		scratch.setIdentity(c.loadedID)
+		if err := k.Set(&scratch, value); err != nil {
+			return err
+		}
+		c.loadedID = scratch.identity()
+		if c.global == nil {
+			c.setIdentity(c.loadedID)
+		}
+		return nil
+	}
+
+	if k.JSON == "" {
+		return fmt.Errorf("%s cannot be set in the global config", key)
+	}
+	target := c.global
+	if target == nil {
+		target = c
+	}
+	return k.Set(target, value)
+}
+
+// FindKey は名前から設定キーを返す（未知のキーは候補つきのエラー）
+func FindKey(name string) (Key, error) {
+	if k, ok := LookupKey(name); ok {
+		return k, nil
+	}
+	var names []string
+	for _, k := range Keys {
+		names = append(names, k.Name)
+	}
+	if s := suggest(name, names); s != "" {
+		return Key{}, fmt.Errorf("unknown config key %q (did you mean %q?)", name, s)
+	}
+	return Key{}, fmt.Errorf("unknown config key %q. Run: vibe config list", name)
+}
+
+// readYAMLDoc は .vibe.yaml をノードとして読み込む（ファイルがなければ空のマッピング）
+func readYAMLDoc(path string) (*yaml.Node, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+	case os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("failed to read YAML config: %w", err)
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid YAML config: top level must be a mapping")
+	}
+	return &doc, nil
+}
+
+// writeYAMLDoc はノードを .vibe.yaml に書き込む
+func writeYAMLDoc(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal YAML config: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write YAML config: %w", err)
+	}
+	return nil
+}
+
+// setNested はマッピングノードのパスに値を設定する（途中のマッピングは作成する）
+func setNested(m *yaml.Node, path []string, value *yaml.Node) {
+	for _, key := range path[:len(path)-1] {
+		child := mappingValue(m, key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(m, key, child)
+		}
+		m = child
+	}
+	setMappingValue(m, path[len(path)-1], value)
+}
+
+// deleteNested はマッピングノードのパスを削除し、空になった親のマッピングも削除する
+func deleteNested(m *yaml.Node, path []string) {
+	if len(path) == 1 {
+		deleteMappingKey(m, path[0])
+		return
+	}
+	child := mappingValue(m, path[0])
+	if child == nil || child.Kind != yaml.MappingNode {
+		return
+	}
+	deleteNested(child, path[1:])
+	if len(child.Content) == 0 {
+		deleteMappingKey(m, path[0])
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultIssueFormMaxTimeout はIssueフォームで指定できるタイムアウトの上限の既定値
+const DefaultIssueFormMaxTimeout = 2 * time.Hour
+
+// modelAliasPattern は models が未設定のときに受け付けるモデル名（エイリアスまたは claude-* のモデルID）
+var modelAliasPattern = regexp.MustCompile(`^(default|opus|sonnet|haiku|opusplan|claude-[a-z0-9.-]+)(\[1m\])?$`)
+
+// IssueFormConfig はIssueフォームの項目（### 見出し）とタスクの設定の対応
+// 値には見出しのラベルを指定する（大文字小文字を区別しない）
+// Issue本文は信頼できない入力のため、投稿者が input.Trusts を満たすIssueにのみ適用し、
+// ツール・モデル・タイムアウト・作業ディレクトリは許可した範囲に限る
+type IssueFormConfig struct {
+	WorkDir      string            `json:"work_dir,omitempty" yaml:"work_dir,omitempty"`           // 作業ディレクトリ（相対パスは実行したディレクトリ、watchでは作業ディレクトリから）
+	Timeout      string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`             // タイムアウト（例: 45m）
+	Model        string            `json:"model,omitempty" yaml:"model,omitempty"`                 // Claude Codeのモデル
+	AllowedTools string            `json:"allowed_tools,omitempty" yaml:"allowed_tools,omitempty"` // Claude Codeに許可するツール（チェックボックス・カンマ区切り）
+	Variables    map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`         // テンプレート変数名 -> 見出しのラベル
+	Template     string            `json:"template,omitempty" yaml:"template,omitempty"`           // プロンプトのテンプレート（text/template）
+
+	ToolAllowlist []string `json:"tool_allowlist,omitempty" yaml:"tool_allowlist,omitempty"` // Issueフォームで許可できるツール（未設定ならツールを許可できない）
+	Models        []string `json:"models,omitempty" yaml:"models,omitempty"`                 // Issueフォームで指定できるモデル（未設定ならエイリアスと claude-* のモデルID）
+	MaxTimeout    Duration `json:"max_timeout,omitzero" yaml:"max_timeout,omitempty"`        // Issueフォームで指定できるタイムアウトの上限（既定: 1タスクの予算の時間、なければ2時間）
+}
+
+// IsZero は設定されていないかどうかを返す
+func (f IssueFormConfig) IsZero() bool {
+	return f.WorkDir == "" && f.Timeout == "" && f.Model == "" && f.AllowedTools == "" && len(f.Variables) == 0 && f.Template == "" &&
+		len(f.ToolAllowlist) == 0 && len(f.Models) == 0 && f.MaxTimeout.Duration == 0
+}
+
+// AllowsTool はIssueフォームでツールを許可してよいかを返す（tool_allowlist と完全に一致するもののみ）
+func (f IssueFormConfig) AllowsTool(tool string) bool {
+	for _, t := range f.ToolAllowlist {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsModel はIssueフォームでモデルを指定してよいかを返す
+func (f IssueFormConfig) AllowsModel(model string) bool {
+	if len(f.Models) == 0 {
+		return modelAliasPattern.MatchString(model)
+	}
+	for _, m := range f.Models {
+		if strings.EqualFold(m, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveMaxTimeout はIssueフォームで指定できるタイムアウトの上限を返す
+// max_timeout、1タスクの予算の時間（taskLimit）、DefaultIssueFormMaxTimeout の順に使う
+func (f IssueFormConfig) EffectiveMaxTimeout(taskLimit time.Duration) time.Duration {
+	if f.MaxTimeout.Duration > 0 {
+		return f.MaxTimeout.Duration
+	}
+	if taskLimit > 0 {
+		return taskLimit
+	}
+	return DefaultIssueFormMaxTimeout
+}
+
+// ParseTemplate はプロンプトのテンプレートを解析する（未設定なら nil）
+// 未定義の変数は空文字列になる
+func (f IssueFormConfig) ParseTemplate() (*template.Template, error) {
+	if f.Template == "" {
+		return nil, nil
+	}
+	return template.New("prompt").Option("missingkey=zero").Parse(f.Template)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssueFormAllowsModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		models []string
+		model  string
+		want   bool
+	}{
+		{name: "alias", model: "sonnet", want: true},
+		{name: "alias with 1m context", model: "opus[1m]", want: true},
+		{name: "full model name", model: "claude-sonnet-4-5", want: true},
+		{name: "unknown name", model: "gpt-4", want: false},
+		{name: "option injection", model: "--dangerously-skip-permissions", want: false},
+		{name: "listed model", models: []string{"Sonnet"}, model: "sonnet", want: true},
+		{name: "alias not in the list", models: []string{"sonnet"}, model: "opus", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := IssueFormConfig{Models: tt.models}
+			if got := f.AllowsModel(tt.model); got != tt.want {
+				t.Errorf("AllowsModel(%q) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueFormEffectiveMaxTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxTimeout time.Duration
+		taskLimit  time.Duration
+		want       time.Duration
+	}{
+		{name: "default", want: DefaultIssueFormMaxTimeout},
+		{name: "per-task budget", taskLimit: 20 * time.Minute, want: 20 * time.Minute},
+		{name: "max_timeout wins", maxTimeout: time.Hour, taskLimit: 20 * time.Minute, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := IssueFormConfig{MaxTimeout: Duration{Duration: tt.maxTimeout}}
+			if got := f.EffectiveMaxTimeout(tt.taskLimit); got != tt.want {
+				t.Errorf("EffectiveMaxTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HooksConfig はClaude Codeの実行前後に実行するシェルコマンド
+// コマンドはタスクの作業ディレクトリで実行し、タスクと実行結果を環境変数と標準入力（JSON）で渡す
+type HooksConfig struct {
+	PreRun    Commands `json:"pre_run,omitempty" yaml:"pre_run,omitempty"`       // 実行前（失敗したらClaude Codeを実行せず失敗にする）
+	PostRun   Commands `json:"post_run,omitempty" yaml:"post_run,omitempty"`     // 実行後に必ず実行（失敗したらタスクを失敗にする）
+	OnSuccess Commands `json:"on_success,omitempty" yaml:"on_success,omitempty"` // post_run の後、成功した場合
+	OnFailure Commands `json:"on_failure,omitempty" yaml:"on_failure,omitempty"` // post_run の後、失敗した場合
+	Timeout   Duration `json:"timeout,omitzero" yaml:"timeout,omitempty"`        // 1コマンドあたりのタイムアウト
+}
+
+// DefaultHookTimeout はフックのデフォルトのタイムアウト
+const DefaultHookTimeout = 10 * time.Minute
+
+// IsZero はフックが設定されていないかどうかを返す
+func (h HooksConfig) IsZero() bool {
+	return len(h.PreRun) == 0 && len(h.PostRun) == 0 && len(h.OnSuccess) == 0 && len(h.OnFailure) == 0 && h.Timeout.Duration == 0
+}
+
+// Commands は1つまたは複数のシェルコマンド（文字列またはリストで設定できる）
+type Commands []string
+
+// UnmarshalYAML は文字列またはリストからCommandsを読み込む
+func (c *Commands) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = Commands{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*c = list
+	return nil
+}
+
+// UnmarshalJSON は文字列またはリストからCommandsを読み込む
+func (c *Commands) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*c = Commands{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*c = list
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import "strings"
+
+// InputConfig はClaude Codeが実行の最後に質問した場合（追加の入力が必要な場合）の扱い
+// 質問はIssueにコメントし、タスクを Needs input にする。信頼するユーザーが返信すると同じセッションを再開する
+type InputConfig struct {
+	Disabled     bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`           // 質問を検出しない（質問で終わっても完了として扱う）
+	TrustedUsers []string `json:"trusted_users,omitempty" yaml:"trusted_users,omitempty"` // 返信で再開できるユーザー（省略時はリポジトリのオーナー・メンバー・コラボレーター）
+}
+
+// trustedAssociations は trusted_users が未設定のときに信頼するリポジトリとの関係
+var trustedAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// IsZero は設定されていないかどうかを返す
+func (i InputConfig) IsZero() bool {
+	return !i.Disabled && len(i.TrustedUsers) == 0
+}
+
+// Trusts はコメントの投稿者の返信でセッションを再開してよいかを返す
+// association はGitHubの authorAssociation（OWNER / MEMBER / COLLABORATOR / CONTRIBUTOR / NONE など）
+func (i InputConfig) Trusts(login, association string) bool {
+	if len(i.TrustedUsers) > 0 {
+		for _, u := range i.TrustedUsers {
+			if strings.EqualFold(strings.TrimPrefix(u, "@"), login) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, a := range trustedAssociations {
+		if association == a {
+			return true
+		}
+	}
+	return false
+}
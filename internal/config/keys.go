@@ -0,0 +1,329 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Key は設定キーの定義
+// 名前は .vibe.yaml 上のパス（ドット区切り）で、環境変数は VIBE_ + 大文字の名前になる
+type Key struct {
+	Name        string // .vibe.yaml 上のパス（例: budget.daily.usd）
+	JSON        string // config.json 上のパス（空ならグローバル設定に書けない）
+	Local       bool   // .vibe.yaml に書けるか
+	Hidden      bool   // list / explain に表示しない（別のキーに展開される入力用キー）
+	Description string
+
+	field func(*Config) any                   // フィールドへのポインタ
+	set   func(c *Config, value string) error // 文字列から設定する（field を使わない場合）
+}
+
+// Keys は設定キーの一覧
+var Keys = []Key{
+	{Name: "project.url", Local: true, Hidden: true, Description: "GitHub Project URL (sets project.owner and project.number)",
+		set: func(c *Config, v string) error {
+			if v == "" {
+				c.ProjectOwner, c.ProjectNumber = "", 0
+				return nil
+			}
+			owner, number, err := ParseProjectURL(v)
+			if err != nil {
+				return err
+			}
+			c.ProjectOwner, c.ProjectNumber = owner, number
+			return nil
+		}},
+	{Name: "project.owner", JSON: "project_owner", Local: true, Description: "Project owner (user or organization)",
+		field: func(c *Config) any { return &c.ProjectOwner }},
+	{Name: "project.number", JSON: "project_number", Local: true, Description: "Project number",
+		field: func(c *Config) any { return &c.ProjectNumber }},
+	{Name: "host", JSON: "host", Description: "GitHub host (GitHub Enterprise Server)",
+		field: func(c *Config) any { return &c.Host }},
+	{Name: "claude_path", JSON: "claude_path", Local: true, Description: "Path to the claude command",
+		field: func(c *Config) any { return &c.ClaudePath }},
+
+	budgetKey("per_task", "usd", "Per-task cost limit (USD)"),
+	budgetKey("per_task", "tokens", "Per-task token limit"),
+	budgetKey("per_task", "time", "Per-task time limit"),
+	budgetKey("daily", "usd", "Daily cost limit (USD)"),
+	budgetKey("daily", "tokens", "Daily token limit"),
+	budgetKey("daily", "time", "Daily execution time limit"),
+	budgetKey("project", "usd", "Project cost limit (USD)"),
+	budgetKey("project", "tokens", "Project token limit"),
+	budgetKey("project", "time", "Project execution time limit"),
+
+	{Name: "retry.max_attempts", JSON: "retry.max_attempts", Local: true, Description: "Maximum attempts per task, including the first",
+		field: func(c *Config) any { return &c.Retry.MaxAttempts }},
+	{Name: "retry.backoff", JSON: "retry.backoff", Local: true, Description: "Wait before the first retry (doubles each time)",
+		field: func(c *Config) any { return &c.Retry.Backoff }},
+	{Name: "retry.max_backoff", JSON: "retry.max_backoff", Local: true, Description: "Upper bound for the retry wait",
+		field: func(c *Config) any { return &c.Retry.MaxBackoff }},
+	{Name: "retry.retryable", JSON: "retry.retryable", Local: true, Description: "Error classes that are retried",
+		field: func(c *Config) any { return &c.Retry.Retryable }},
+	{Name: "retry.failure_status", JSON: "retry.failure_status", Local: true, Description: "Status option for failed runs (default Failed)",
+		field: func(c *Config) any { return &c.Retry.FailureStatus }},
+
+	{Name: "notifiers", JSON: "notifiers", Local: true, Description: "Notification sinks",
+		field: func(c *Config) any { return &c.Notifiers }},
+
+	{Name: "hooks.pre_run", JSON: "hooks.pre_run", Local: true, Description: "Commands run before Claude Code (failure skips the run)",
+		field: func(c *Config) any { return &c.Hooks.PreRun }},
+	{Name: "hooks.post_run", JSON: "hooks.post_run", Local: true, Description: "Commands run after Claude Code (failure marks the task failed)",
+		field: func(c *Config) any { return &c.Hooks.PostRun }},
+	{Name: "hooks.on_success", JSON: "hooks.on_success", Local: true, Description: "Commands run when the task succeeded",
+		field: func(c *Config) any { return &c.Hooks.OnSuccess }},
+	{Name: "hooks.on_failure", JSON: "hooks.on_failure", Local: true, Description: "Commands run when the task failed",
+		field: func(c *Config) any { return &c.Hooks.OnFailure }},
+	{Name: "hooks.timeout", JSON: "hooks.timeout", Local: true, Description: "Timeout per hook command",
+		field: func(c *Config) any { return &c.Hooks.Timeout }},
+
+	{Name: "verify.command", JSON: "verify.command", Local: true, Description: "Commands that verify the changes after Claude Code (e.g. make test)",
+		field: func(c *Config) any { return &c.Verify.Command }},
+	{Name: "verify.max_iterations", JSON: "verify.max_iterations", Local: true, Description: "Maximum Claude Code runs per task, including the first, until verification passes",
+		field: func(c *Config) any { return &c.Verify.MaxIterations }},
+	{Name: "verify.timeout", JSON: "verify.timeout", Local: true, Description: "Timeout per verification command",
+		field: func(c *Config) any { return &c.Verify.Timeout }},
+
+	{Name: "input.disabled", JSON: "input.disabled", Local: true, Description: "Do not detect questions asked by Claude Code at the end of a run",
+		field: func(c *Config) any { return &c.Input.Disabled }},
+	{Name: "input.trusted_users", JSON: "input.trusted_users", Local: true, Description: "Users whose replies resume a task that needs input (default: owners, members and collaborators)",
+		field: func(c *Config) any { return &c.Input.TrustedUsers }},
+
+	{Name: "attachments.disabled", JSON: "attachments.disabled", Local: true, Description: "Do not download images and files attached to the issue",
+		field: func(c *Config) any { return &c.Attachments.Disabled }},
+	{Name: "attachments.allowed_hosts", JSON: "attachments.allowed_hosts", Local: true, Description: "Hosts attachments may be downloaded from (default: GitHub's upload hosts)",
+		field: func(c *Config) any { return &c.Attachments.AllowedHosts }},
+	{Name: "attachments.max_size_mb", JSON: "attachments.max_size_mb", Local: true, Description: "Maximum size of one attachment in MB (default 10)",
+		field: func(c *Config) any { return &c.Attachments.MaxSizeMB }},
+	{Name: "attachments.max_total_mb", JSON: "attachments.max_total_mb", Local: true, Description: "Maximum total size of attachments per run in MB (default 50)",
+		field: func(c *Config) any { return &c.Attachments.MaxTotalMB }},
+
+	{Name: "related.enabled", JSON: "related.enabled", Local: true, Description: "Add the issues and pull requests referenced in the task to the prompt",
+		field: func(c *Config) any { return &c.Related.Enabled }},
+	{Name: "related.depth", JSON: "related.depth", Local: true, Description: "How many levels of references to follow (default 1)",
+		field: func(c *Config) any { return &c.Related.Depth }},
+	{Name: "related.max_items", JSON: "related.max_items", Local: true, Description: "Maximum number of referenced issues and pull requests to add (default 5)",
+		field: func(c *Config) any { return &c.Related.MaxItems }},
+	{Name: "related.max_chars", JSON: "related.max_chars", Local: true, Description: "Maximum characters of each referenced body (default 2000)",
+		field: func(c *Config) any { return &c.Related.MaxChars }},
+
+	{Name: "prompt.max_tokens", JSON: "prompt.max_tokens", Local: true, Description: "Estimated token budget for the prompt built from the issue thread (default 50000)",
+		field: func(c *Config) any { return &c.Prompt.MaxTokens }},
+	{Name: "prompt.keep_recent", JSON: "prompt.keep_recent", Local: true, Description: "Number of most recent comments always kept in full (default 5)",
+		field: func(c *Config) any { return &c.Prompt.KeepRecent }},
+	{Name: "prompt.summarize", JSON: "prompt.summarize", Local: true, Description: "Summarize older comments with Claude Code instead of truncating them",
+		field: func(c *Config) any { return &c.Prompt.Summarize }},
+
+	{Name: "issue_form.work_dir", JSON: "issue_form.work_dir", Local: true, Description: "Issue form section that sets the working directory",
+		field: func(c *Config) any { return &c.IssueForm.WorkDir }},
+	{Name: "issue_form.timeout", JSON: "issue_form.timeout", Local: true, Description: "Issue form section that sets the task timeout",
+		field: func(c *Config) any { return &c.IssueForm.Timeout }},
+	{Name: "issue_form.model", JSON: "issue_form.model", Local: true, Description: "Issue form section that sets the Claude Code model",
+		field: func(c *Config) any { return &c.IssueForm.Model }},
+	{Name: "issue_form.allowed_tools", JSON: "issue_form.allowed_tools", Local: true, Description: "Issue form section that lists the tools Claude Code may use",
+		field: func(c *Config) any { return &c.IssueForm.AllowedTools }},
+	{Name: "issue_form.template", JSON: "issue_form.template", Local: true, Description: "Prompt template with {{.Prompt}} and {{.Vars.name}} from issue form sections",
+		field: func(c *Config) any { return &c.IssueForm.Template }},
+	{Name: "issue_form.tool_allowlist", JSON: "issue_form.tool_allowlist", Local: true, Description: "Tools an issue form may allow (others are rejected; default: none)",
+		field: func(c *Config) any { return &c.IssueForm.ToolAllowlist }},
+	{Name: "issue_form.models", JSON: "issue_form.models", Local: true, Description: "Models an issue form may select (default: aliases and claude-* model IDs)",
+		field: func(c *Config) any { return &c.IssueForm.Models }},
+	{Name: "issue_form.max_timeout", JSON: "issue_form.max_timeout", Local: true, Description: "Longest timeout an issue form may set (default: budget.per_task.time, or 2h)",
+		field: func(c *Config) any { return &c.IssueForm.MaxTimeout }},
+
+	{Name: "checklist.disabled", JSON: "checklist.disabled", Local: true, Description: "Do not ask Claude Code to report on the issue's acceptance criteria",
+		field: func(c *Config) any { return &c.Checklist.Disabled }},
+	{Name: "checklist.read_only", JSON: "checklist.read_only", Local: true, Description: "Report acceptance criteria progress without ticking checkboxes in the issue body",
+		field: func(c *Config) any { return &c.Checklist.ReadOnly }},
+}
+
+func budgetKey(scope, unit, description string) Key {
+	name := "budget." + scope + "." + unit
+	return Key{Name: name, JSON: name, Local: true, Description: description,
+		field: func(c *Config) any {
+			var limit *BudgetLimit
+			switch scope {
+			case "per_task":
+				limit = &c.Budget.PerTask
+			case "daily":
+				limit = &c.Budget.Daily
+			default:
+				limit = &c.Budget.Project
+			}
+			switch unit {
+			case "usd":
+				return &limit.USD
+			case "tokens":
+				return &limit.Tokens
+			default:
+				return &limit.Time
+			}
+		}}
+}
+
+// identityKeys はプロファイルで切り替わるキー
+var identityKeys = map[string]bool{"host": true, "project.owner": true, "project.number": true}
+
+// LookupKey は名前から設定キーを返す
+func LookupKey(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// EnvVar はキーを上書きする環境変数名を返す
+func (k Key) EnvVar() string {
+	return "VIBE_" + strings.ToUpper(strings.ReplaceAll(k.Name, ".", "_"))
+}
+
+// Value は設定値を文字列で返す（未設定なら空）
+func (k Key) Value(c *Config) string {
+	if k.field == nil {
+		return ""
+	}
+	v := reflect.ValueOf(k.field(c)).Elem()
+	if v.IsZero() {
+		return ""
+	}
+	switch x := v.Interface().(type) {
+	case string:
+		return x
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case Duration:
+		return x.String()
+	default:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+		return string(data)
+	}
+}
+
+// Set は文字列（YAMLとして解釈する）から設定値を設定する
+// 空文字列の場合は未設定に戻す
+func (k Key) Set(c *Config, value string) error {
+	if k.set != nil {
+		return k.set(c, value)
+	}
+
+	ptr := k.field(c)
+	if value == "" {
+		v := reflect.ValueOf(ptr).Elem()
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if err := yaml.Unmarshal([]byte(value), ptr); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", k.Name, err)
+	}
+	return nil
+}
+
+// copyValue は src のキーの値を dst に複写する
+func (k Key) copyValue(dst, src *Config) {
+	if k.field == nil {
+		return
+	}
+	reflect.ValueOf(k.field(dst)).Elem().Set(reflect.ValueOf(k.field(src)).Elem())
+}
+
+// flattenKeys はYAML/JSONのマップから値の設定されたキーのパスを返す
+// マッピングは辿り、リストやスカラーは1つのキーとして扱う
+func flattenKeys(m map[string]any, prefix string) []string {
+	var keys []string
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch child := v.(type) {
+		case nil:
+			// 値のないキーは未設定として扱う
+		case map[string]any:
+			keys = append(keys, flattenKeys(child, path)...)
+		default:
+			keys = append(keys, path)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unknownEnvVars は設定キーに対応しない VIBE_ 環境変数を警告として返す
+func unknownEnvVars() []string {
+	known := map[string]bool{
+		// 設定キー以外で使う環境変数
+		"VIBE_PROFILE":         true,
+		"VIBE_GITHUB_TOKEN":    true,
+		"VIBE_OAUTH_CLIENT_ID": true,
+		"VIBE_HOOK":            true,
+	}
+	var names []string
+	for _, k := range Keys {
+		known[k.EnvVar()] = true
+		names = append(names, k.EnvVar())
+	}
+
+	var warnings []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		// VIBE_TASK_* / VIBE_EXEC_* はフックに渡す環境変数（フックから vibe を実行した場合）
+		if !strings.HasPrefix(name, "VIBE_") || known[name] ||
+			strings.HasPrefix(name, "VIBE_TASK_") || strings.HasPrefix(name, "VIBE_EXEC_") {
+			continue
+		}
+		msg := fmt.Sprintf("unknown environment variable %s", name)
+		if s := suggest(name, names); s != "" {
+			msg += fmt.Sprintf(" (did you mean %s?)", s)
+		}
+		warnings = append(warnings, msg)
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// suggest は name に最も近い候補を返す（十分に近いものがなければ空）
+func suggest(name string, candidates []string) string {
+	best, bestDist := "", len(name)/3+2
+	for _, c := range candidates {
+		if d := levenshtein(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Change は移行で書き換えた内容
+type Change struct {
+	Line    int    // 移行前のキーの行（version の追加など行のない変更は0）
+	Key     string // 移行前のキー
+	Message string
+}
+
+// migration はバージョン未指定（0）の .vibe.yaml を version 1 に書き換える1つの手順
+type migration func(root *yaml.Node) []Change
+
+var migrations = []migration{
+	migrateTopLevelProject,
+	migrateGitHubToken,
+	migrateRedundantProject,
+}
+
+// Migrate は古い形式の .vibe.yaml のノードを現在のスキーマに書き換える
+// コメントや他の設定は維持する。既に現在のバージョンなら何もしない
+func Migrate(root *yaml.Node) []Change {
+	if n := mappingValue(root, "version"); n != nil {
+		return nil
+	}
+
+	// ファイル冒頭のコメントは先頭のキーに付くため、キーを移動・削除しても消えないよう先頭に追加する version に移す
+	var head string
+	if len(root.Content) > 0 {
+		head, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+
+	var changes []Change
+	for _, m := range migrations {
+		changes = append(changes, m(root)...)
+	}
+
+	version := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "version", HeadComment: head},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(SchemaVersion)},
+	}
+	root.Content = append(version, root.Content...)
+	changes = append(changes, Change{Key: "version", Message: fmt.Sprintf("is not set; add version: %d", SchemaVersion)})
+	return changes
+}
+
+// MigrateFile は .vibe.yaml を移行する（write が false なら変更内容だけを返す）
+func MigrateFile(path string, write bool) ([]Change, error) {
+	doc, err := readYAMLDoc(path)
+	if err != nil {
+		return nil, err
+	}
+	changes := Migrate(doc.Content[0])
+	if len(changes) == 0 || !write {
+		return changes, nil
+	}
+	return changes, writeYAMLDoc(path, doc)
+}
+
+// migrateTopLevelProject は古いトップレベルの project_owner / project_number を project に移す
+func migrateTopLevelProject(root *yaml.Node) []Change {
+	var changes []Change
+	for _, old := range []struct{ key, field string }{
+		{"project_owner", "owner"},
+		{"project_number", "number"},
+	} {
+		k := mappingKey(root, old.key)
+		if k == nil {
+			continue
+		}
+		value := mappingValue(root, old.key)
+		deleteMappingKey(root, old.key)
+
+		project := mappingValue(root, "project")
+		if project != nil && project.Kind == yaml.MappingNode &&
+			(mappingValue(project, "url") != nil || mappingValue(project, old.field) != nil) {
+			changes = append(changes, Change{Line: k.Line, Key: old.key, Message: "is deprecated and ignored because project is already set"})
+			continue
+		}
+		setNested(root, []string{"project", old.field}, value)
+		changes = append(changes, Change{Line: k.Line, Key: old.key, Message: "is deprecated; use project." + old.field})
+	}
+	return changes
+}
+
+// migrateGitHubToken は .vibe.yaml に書かれたトークン（読み込まれない）を削除する
+func migrateGitHubToken(root *yaml.Node) []Change {
+	k := mappingKey(root, "github_token")
+	if k == nil {
+		return nil
+	}
+	deleteMappingKey(root, "github_token")
+	return []Change{{Line: k.Line, Key: "github_token", Message: removedKeys["github_token"]}}
+}
+
+// migrateRedundantProject は url と一致する owner/number を削除する（食い違う場合は検証でエラーにする）
+func migrateRedundantProject(root *yaml.Node) []Change {
+	project := mappingValue(root, "project")
+	if project == nil || project.Kind != yaml.MappingNode {
+		return nil
+	}
+	url := mappingValue(project, "url")
+	if url == nil {
+		return nil
+	}
+	owner, number, err := ParseProjectURL(url.Value)
+	if err != nil {
+		return nil
+	}
+
+	var changes []Change
+	if n := mappingValue(project, "owner"); n != nil && n.Value == owner {
+		line := mappingKey(project, "owner").Line
+		deleteMappingKey(project, "owner")
+		changes = append(changes, Change{Line: line, Key: "project.owner", Message: "is redundant with project.url"})
+	}
+	if n := mappingValue(project, "number"); n != nil && n.Value == strconv.Itoa(number) {
+		line := mappingKey(project, "number").Line
+		deleteMappingKey(project, "number")
+		changes = append(changes, Change{Line: line, Key: "project.number", Message: "is redundant with project.url"})
+	}
+	return changes
+}
@@ -0,0 +1,21 @@
+package config
+
+// NotifierConfig は通知先の設定
+type NotifierConfig struct {
+	Type   string   `json:"type" yaml:"type"`                           // desktop / webhook / slack / discord / teams / email
+	URL    string   `json:"url,omitempty" yaml:"url,omitempty"`         // webhook / slack / discord / teams のURL
+	URLEnv string   `json:"url_env,omitempty" yaml:"url_env,omitempty"` // URLを読み込む環境変数（URLを設定ファイルに書かない場合）
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`   // 通知するイベント（省略時は succeeded / failed / budget_exceeded / needs_input）
+
+	SMTP *SMTPConfig `json:"smtp,omitempty" yaml:"smtp,omitempty"` // email 用
+}
+
+// SMTPConfig はメール通知の設定
+type SMTPConfig struct {
+	Host        string   `json:"host" yaml:"host"`
+	Port        int      `json:"port,omitempty" yaml:"port,omitempty"` // デフォルト: 587
+	Username    string   `json:"username,omitempty" yaml:"username,omitempty"`
+	PasswordEnv string   `json:"password_env,omitempty" yaml:"password_env,omitempty"` // パスワードを読み込む環境変数
+	From        string   `json:"from" yaml:"from"`
+	To          []string `json:"to" yaml:"to"`
+}
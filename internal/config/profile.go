@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultHost はデフォルトのGitHubホスト
+const DefaultHost = "github.com"
+
+// Profile は名前つきの接続設定（ホスト・認証・Project）
+type Profile struct {
+	Host          string     `json:"host,omitempty"` // 空なら github.com
+	ProjectOwner  string     `json:"project_owner,omitempty"`
+	ProjectNumber int        `json:"project_number,omitempty"`
+	Auth          AuthConfig `json:"auth,omitzero"`
+}
+
+// ProjectRef は .vibe.yaml の projects に列挙されたProject
+type ProjectRef struct {
+	Owner   string
+	Number  int
+	Profile string // 空なら現在のプロファイル
+	WorkDir string // 空ならカレントディレクトリ
+}
+
+// identity はプロファイルで切り替わる設定項目
+type identity struct {
+	Host          string
+	ProjectOwner  string
+	ProjectNumber int
+	GitHubToken   string
+	Auth          AuthConfig
+}
+
+func (c *Config) identity() identity {
+	return identity{
+		Host:          c.Host,
+		ProjectOwner:  c.ProjectOwner,
+		ProjectNumber: c.ProjectNumber,
+		GitHubToken:   c.GitHubToken,
+		Auth:          c.Auth,
+	}
+}
+
+func (c *Config) setIdentity(id identity) {
+	c.Host = id.Host
+	c.ProjectOwner = id.ProjectOwner
+	c.ProjectNumber = id.ProjectNumber
+	c.GitHubToken = id.GitHubToken
+	c.Auth = id.Auth
+}
+
+// Profile は使用中のプロファイル名を返す（プロファイル未使用なら空）
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// ProfileNames はプロファイル名の一覧を名前順に返す
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GitHubHost は接続先のGitHubホストを返す
+func (c *Config) GitHubHost() string {
+	if c.Host == "" {
+		return DefaultHost
+	}
+	return c.Host
+}
+
+// UseProfile はプロファイルの設定をトップレベルに適用する
+// name が空の場合は default_profile を使い、それもなければ何もしない
+func (c *Config) UseProfile(name string) error {
+	// 適用済みのプロファイルを戻してから切り替える
+	c.ClearProfile()
+
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	c.base = c.identity()
+	c.profile = name
+	c.setIdentity(identity{
+		Host:          p.Host,
+		ProjectOwner:  p.ProjectOwner,
+		ProjectNumber: p.ProjectNumber,
+		Auth:          p.Auth,
+	})
+	return nil
+}
+
+// ClearProfile はプロファイルの適用を解除し、トップレベルの設定に戻す
+func (c *Config) ClearProfile() {
+	if c.profile == "" {
+		return
+	}
+	c.setIdentity(c.base)
+	c.profile = ""
+}
+
+// WithProfile はプロファイルを切り替えた設定のコピーを返す
+// name が空の場合は現在のプロファイルのまま、Projectだけを差し替えられるようにコピーする
+func (c *Config) WithProfile(name string) (*Config, error) {
+	copied := *c
+	copied.Auth.Token = ""
+	copied.Auth.TokenSource = ""
+	if name == "" || name == c.profile {
+		copied.Auth.Token = c.Auth.Token
+		copied.Auth.TokenSource = c.Auth.TokenSource
+		return &copied, nil
+	}
+	if err := copied.UseProfile(name); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// SetProfile はプロファイルを追加または更新する
+func (c *Config) SetProfile(name string, p Profile) {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[name] = p
+}
+
+// forSave は保存用の設定を返す
+// .vibe.yaml から読み込んだ値はグローバル設定に書き込まない
+// プロファイル使用中はその変更をプロファイルに書き戻し、トップレベルは元の値に戻す
+func (c *Config) forSave() *Config {
+	var out Config
+	if c.global != nil {
+		out = *c.global
+	} else {
+		out = *c
+	}
+
+	id := c.identity()
+	id.Auth.Token = ""
+	id.Auth.TokenSource = ""
+	// .vibe.yaml・環境変数・--set で上書きされたままの値は上書き前の値を保存する
+	if c.global != nil {
+		if id.Host == c.layeredID.Host {
+			id.Host = c.loadedID.Host
+		}
+		if id.ProjectOwner == c.layeredID.ProjectOwner && id.ProjectNumber == c.layeredID.ProjectNumber {
+			id.ProjectOwner, id.ProjectNumber = c.loadedID.ProjectOwner, c.loadedID.ProjectNumber
+		}
+	}
+
+	profiles := make(map[string]Profile, len(c.Profiles))
+	for k, v := range c.Profiles {
+		profiles[k] = v
+	}
+	if len(profiles) == 0 {
+		profiles = nil
+	}
+	out.Profiles = profiles
+	out.DefaultProfile = c.DefaultProfile
+
+	if c.profile == "" {
+		out.setIdentity(id)
+		return &out
+	}
+
+	profiles[c.profile] = Profile{
+		Host:          id.Host,
+		ProjectOwner:  id.ProjectOwner,
+		ProjectNumber: id.ProjectNumber,
+		Auth:          id.Auth,
+	}
+	out.setIdentity(c.base)
+	return &out
+}
+
+// APIBaseURL はホストに対応するREST APIのベースURLを返す
+func APIBaseURL(host string) string {
+	if host == "" || host == DefaultHost {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// GraphQLURL はホストに対応するGraphQL APIのURLを返す
+func GraphQLURL(host string) string {
+	if host == "" || host == DefaultHost {
+		return "https://api.github.com/graphql"
+	}
+	return "https://" + host + "/api/graphql"
+}
@@ -0,0 +1,36 @@
+package config
+
+// PromptConfig はIssueのスレッドから組み立てるプロンプトの大きさの設定
+// 推定トークン数が予算を超えると、Issue本文と最新のコメントを残して途中のコメントを要約・省略する
+type PromptConfig struct {
+	MaxTokens  int  `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`   // プロンプトの予算（推定トークン数）
+	KeepRecent int  `json:"keep_recent,omitempty" yaml:"keep_recent,omitempty"` // 常にそのまま残す最新のコメント数
+	Summarize  bool `json:"summarize,omitempty" yaml:"summarize,omitempty"`     // 途中のコメントを切り詰める代わりにClaude Codeで要約する
+}
+
+// プロンプトのデフォルト値
+const (
+	DefaultPromptMaxTokens  = 50000
+	DefaultPromptKeepRecent = 5
+)
+
+// IsZero は設定されていないかどうかを返す
+func (p PromptConfig) IsZero() bool {
+	return p.MaxTokens == 0 && p.KeepRecent == 0 && !p.Summarize
+}
+
+// EffectiveMaxTokens はプロンプトの予算を返す（未設定ならデフォルト）
+func (p PromptConfig) EffectiveMaxTokens() int {
+	if p.MaxTokens > 0 {
+		return p.MaxTokens
+	}
+	return DefaultPromptMaxTokens
+}
+
+// EffectiveKeepRecent は常にそのまま残す最新のコメント数を返す（未設定ならデフォルト）
+func (p PromptConfig) EffectiveKeepRecent() int {
+	if p.KeepRecent > 0 {
+		return p.KeepRecent
+	}
+	return DefaultPromptKeepRecent
+}
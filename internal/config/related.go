@@ -0,0 +1,46 @@
+package config
+
+// RelatedConfig はIssue・コメントで参照されているIssue/PRをプロンプトに展開する設定
+// 有効にすると、参照先のタイトル・本文（PRは変更ファイル一覧も）を "Related context" として追加する
+type RelatedConfig struct {
+	Enabled  bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`     // 参照先を展開する
+	Depth    int  `json:"depth,omitempty" yaml:"depth,omitempty"`         // 参照をたどる深さ（1: タスクのIssueからの参照のみ）
+	MaxItems int  `json:"max_items,omitempty" yaml:"max_items,omitempty"` // 展開するIssue/PRの上限
+	MaxChars int  `json:"max_chars,omitempty" yaml:"max_chars,omitempty"` // 1件あたりの本文の上限（文字数）
+}
+
+// 参照先の展開のデフォルト値
+const (
+	DefaultRelatedDepth    = 1
+	DefaultRelatedMaxItems = 5
+	DefaultRelatedMaxChars = 2000
+)
+
+// IsZero は設定されていないかどうかを返す
+func (r RelatedConfig) IsZero() bool {
+	return !r.Enabled && r.Depth == 0 && r.MaxItems == 0 && r.MaxChars == 0
+}
+
+// EffectiveDepth は参照をたどる深さを返す（未設定ならデフォルト）
+func (r RelatedConfig) EffectiveDepth() int {
+	if r.Depth > 0 {
+		return r.Depth
+	}
+	return DefaultRelatedDepth
+}
+
+// EffectiveMaxItems は展開するIssue/PRの上限を返す（未設定ならデフォルト）
+func (r RelatedConfig) EffectiveMaxItems() int {
+	if r.MaxItems > 0 {
+		return r.MaxItems
+	}
+	return DefaultRelatedMaxItems
+}
+
+// EffectiveMaxChars は1件あたりの本文の上限を返す（未設定ならデフォルト）
+func (r RelatedConfig) EffectiveMaxChars() int {
+	if r.MaxChars > 0 {
+		return r.MaxChars
+	}
+	return DefaultRelatedMaxChars
+}
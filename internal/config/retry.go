@@ -0,0 +1,51 @@
+package config
+
+import "time"
+
+// RetryConfig は失敗した実行の自動リトライ設定
+type RetryConfig struct {
+	MaxAttempts int         `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"` // 最大試行回数（初回を含む）
+	Backoff     Duration    `json:"backoff,omitzero" yaml:"backoff,omitempty"`            // 初回リトライまでの待機時間（以降は倍々）
+	MaxBackoff  Duration    `json:"max_backoff,omitzero" yaml:"max_backoff,omitempty"`    // 待機時間の上限
+	Retryable   []RetryRule `json:"retryable,omitempty" yaml:"retryable,omitempty"`       // リトライ対象のエラー分類
+
+	FailureStatus string `json:"failure_status,omitempty" yaml:"failure_status,omitempty"` // 失敗した実行を移すStatusのオプション名
+}
+
+// RetryRule はリトライ対象とするエラーの分類
+// ExitCodes・Pattern・Timeout のいずれかに一致すればリトライ対象となる
+type RetryRule struct {
+	Name      string `json:"name" yaml:"name"`
+	ExitCodes []int  `json:"exit_codes,omitempty" yaml:"exit_codes,omitempty"` // 終了コード
+	Pattern   string `json:"pattern,omitempty" yaml:"pattern,omitempty"`       // stderr / エラーメッセージに対する正規表現
+	Timeout   bool   `json:"timeout,omitempty" yaml:"timeout,omitempty"`       // タイムアウトを対象にする
+}
+
+// デフォルトのリトライ設定
+const (
+	DefaultRetryBackoff    = 30 * time.Second
+	DefaultRetryMaxBackoff = 10 * time.Minute
+)
+
+// DefaultFailureStatus は failure_status 未指定時に失敗した実行を移すStatus
+const DefaultFailureStatus = "Failed"
+
+// DefaultRetryRules は retryable 未指定時に使うエラー分類
+var DefaultRetryRules = []RetryRule{
+	{Name: "rate_limit", Pattern: `(?i)rate.?limit|too many requests|\b429\b|overloaded|\b529\b`},
+	{Name: "network", Pattern: `(?i)ECONNRESET|ECONNREFUSED|ETIMEDOUT|ENOTFOUND|EAI_AGAIN|socket hang up|fetch failed|network error`},
+	{Name: "timeout", Timeout: true},
+}
+
+// IsZero はリトライが設定されていないかどうかを返す
+func (r RetryConfig) IsZero() bool {
+	return r.MaxAttempts == 0 && r.Backoff.Duration == 0 && r.MaxBackoff.Duration == 0 && len(r.Retryable) == 0 && r.FailureStatus == ""
+}
+
+// FailureStatusOrDefault は失敗した実行を移すStatusのオプション名を返す
+func (r RetryConfig) FailureStatusOrDefault() string {
+	if r.FailureStatus == "" {
+		return DefaultFailureStatus
+	}
+	return r.FailureStatus
+}
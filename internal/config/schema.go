@@ -0,0 +1,471 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion は .vibe.yaml の現在のスキーマバージョン
+const SchemaVersion = 1
+
+// Schema は .vibe.yaml のJSON Schema（エディタの補完・検証用）
+//
+//go:embed vibe.schema.json
+var Schema []byte
+
+// Severity は問題の重要度
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // 動作に影響しない（バージョン未指定など）
+	SeverityWarning                 // 無視される設定・非推奨のキー
+	SeverityError                   // 読み込めない設定
+)
+
+// Problem は設定ファイルの問題
+type Problem struct {
+	Path     string // ファイルパス
+	Line     int    // 1始まり（不明なら0）
+	Column   int
+	Key      string // 問題のあるキー（例: budget.daily.usd, projects[1].url）
+	Message  string
+	Severity Severity
+}
+
+// Error は "path:line:column: key: message" 形式で返す
+func (p Problem) Error() string {
+	var b strings.Builder
+	b.WriteString(p.Path)
+	if p.Line > 0 {
+		fmt.Fprintf(&b, ":%d", p.Line)
+	}
+	if p.Column > 0 {
+		fmt.Fprintf(&b, ":%d", p.Column)
+	}
+	b.WriteString(": ")
+	if p.Key != "" {
+		b.WriteString(p.Key + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// Problems は設定ファイルの問題の一覧
+type Problems []Problem
+
+// Err はエラーの問題があればまとめて返す（なければ nil）
+func (ps Problems) Err() error {
+	var errs Problems
+	for _, p := range ps {
+		if p.Severity == SeverityError {
+			errs = append(errs, p)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Error はエラーを1行ずつ返す
+func (ps Problems) Error() string {
+	lines := make([]string, len(ps))
+	for i, p := range ps {
+		lines[i] = p.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Warnings は警告を文字列で返す
+func (ps Problems) Warnings() []string {
+	var warnings []string
+	for _, p := range ps {
+		if p.Severity == SeverityWarning {
+			warnings = append(warnings, p.Error())
+		}
+	}
+	return warnings
+}
+
+// ValidateFile は .vibe.yaml を検証し、問題を行番号つきで返す
+// error はファイルを読めない・YAMLとして解釈できない場合のみ返す
+func ValidateFile(path string) (Problems, error) {
+	doc, err := readYAMLDoc(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	_, problems := checkDocument(path, doc.Content[0])
+	return problems, nil
+}
+
+// checkDocument は .vibe.yaml を検証してデコードする
+// バージョン未指定の古い形式はメモリ上で移行してから検証し、未知のキーは警告に留める
+func checkDocument(path string, root *yaml.Node) (*ProjectConfig, Problems) {
+	v := &validator{path: path, unknown: SeverityError}
+
+	version, ok := v.version(root)
+	if !ok {
+		return nil, v.sorted()
+	}
+	if version == 0 {
+		v.unknown = SeverityWarning
+		for _, c := range Migrate(root) {
+			severity := SeverityWarning
+			if c.Line == 0 {
+				severity = SeverityInfo
+			}
+			v.add(severity, c.Line, 0, c.Key, c.Message+" (run: vibe config migrate)")
+		}
+	}
+
+	v.walk(root, reflect.TypeOf(ProjectConfig{}), "")
+	if v.invalid {
+		return nil, v.sorted()
+	}
+
+	var projectCfg ProjectConfig
+	if err := root.Decode(&projectCfg); err != nil {
+		v.add(SeverityError, root.Line, root.Column, "", err.Error())
+		return nil, v.sorted()
+	}
+	v.checkProjects(root)
+	v.checkLimits(root, &projectCfg)
+	return &projectCfg, v.sorted()
+}
+
+// removedKeys は .vibe.yaml で使えないキーとその理由
+var removedKeys = map[string]string{
+	"github_token": "is not read from .vibe.yaml; use vibe auth login or an environment variable",
+}
+
+type validator struct {
+	path     string
+	unknown  Severity // 未知のキーの重要度
+	invalid  bool     // 型の誤りがありデコードできない
+	problems Problems
+}
+
+func (v *validator) add(severity Severity, line, column int, key, msg string) {
+	v.problems = append(v.problems, Problem{
+		Path: v.path, Line: line, Column: column, Key: key, Message: msg, Severity: severity,
+	})
+}
+
+func (v *validator) errorf(n *yaml.Node, key, format string, args ...any) {
+	v.add(SeverityError, n.Line, n.Column, key, fmt.Sprintf(format, args...))
+}
+
+// sorted は問題を行番号順に返す
+func (v *validator) sorted() Problems {
+	sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].Line < v.problems[j].Line })
+	return v.problems
+}
+
+// version は version キーを検証して返す（未指定なら0）
+func (v *validator) version(root *yaml.Node) (int, bool) {
+	n := mappingValue(root, "version")
+	if n == nil {
+		return 0, true
+	}
+	version, err := strconv.Atoi(n.Value)
+	if n.Kind != yaml.ScalarNode || err != nil || version < 1 {
+		v.errorf(n, "version", "must be a positive integer, got %q", n.Value)
+		return 0, false
+	}
+	if version > SchemaVersion {
+		v.errorf(n, "version", "unsupported version %d (this vibe supports up to %d); upgrade vibe", version, SchemaVersion)
+		return 0, false
+	}
+	return version, true
+}
+
+var unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// typeErrorf は型の誤りを記録する
+func (v *validator) typeErrorf(n *yaml.Node, key, format string, args ...any) {
+	v.invalid = true
+	v.errorf(n, key, format, args...)
+}
+
+// walk はノードが型に合っているかを検証する
+// 未知のキー・型の誤りを全て集める（最初の1つで止めない）
+func (v *validator) walk(n *yaml.Node, t reflect.Type, key string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		u := reflect.New(t).Interface().(yaml.Unmarshaler)
+		if err := u.UnmarshalYAML(n); err != nil {
+			v.typeErrorf(n, key, "%v", err)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			v.typeErrorf(n, key, "expected a mapping")
+			return
+		}
+		fields := yamlFields(t)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, value := n.Content[i], n.Content[i+1]
+			path := joinKey(key, k.Value)
+			field, ok := fields[k.Value]
+			if !ok {
+				v.unknownKey(k, path, names)
+				continue
+			}
+			v.walk(value, field.Type, path)
+		}
+
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			v.typeErrorf(n, key, "expected a list")
+			return
+		}
+		for i, item := range n.Content {
+			v.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))
+		}
+
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			v.typeErrorf(n, key, "expected a mapping")
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v.walk(n.Content[i+1], t.Elem(), joinKey(key, n.Content[i].Value))
+		}
+
+	default:
+		if n.Kind != yaml.ScalarNode {
+			v.typeErrorf(n, key, "expected %s", typeName(t))
+			return
+		}
+		if err := n.Decode(reflect.New(t).Interface()); err != nil {
+			v.typeErrorf(n, key, "expected %s, got %q", typeName(t), n.Value)
+		}
+	}
+}
+
+func (v *validator) unknownKey(k *yaml.Node, path string, names []string) {
+	if reason, ok := removedKeys[path]; ok {
+		v.add(v.unknown, k.Line, k.Column, path, reason)
+		return
+	}
+	msg := "unknown key"
+	if s := suggest(k.Value, names); s != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", s)
+	}
+	v.add(v.unknown, k.Line, k.Column, path, msg)
+}
+
+// checkProjects は project / projects の矛盾を検証する
+func (v *validator) checkProjects(root *yaml.Node) {
+	if n := mappingValue(root, "project"); n != nil && n.Kind == yaml.MappingNode {
+		v.checkProjectEntry(n, "project")
+		for _, name := range []string{"profile", "work_dir"} {
+			if k := mappingKey(n, name); k != nil {
+				v.errorf(k, "project."+name, "only allowed in projects entries")
+			}
+		}
+	}
+
+	if n := mappingValue(root, "projects"); n != nil && n.Kind == yaml.SequenceNode {
+		seen := make(map[ProjectRef]int)
+		for i, item := range n.Content {
+			key := fmt.Sprintf("projects[%d]", i)
+			ref, ok := v.checkProjectEntry(item, key)
+			if !ok {
+				continue
+			}
+			if ref.Owner == "" || ref.Number == 0 {
+				v.errorf(item, key, "url or owner/number is required")
+				continue
+			}
+			id := ProjectRef{Owner: ref.Owner, Number: ref.Number}
+			if j, dup := seen[id]; dup {
+				v.errorf(item, key, "duplicates projects[%d] (%s #%d)", j, ref.Owner, ref.Number)
+				continue
+			}
+			seen[id] = i
+		}
+	}
+}
+
+// checkProjectEntry は url と owner/number が食い違っていないかを検証する
+func (v *validator) checkProjectEntry(n *yaml.Node, key string) (ProjectRef, bool) {
+	var e ProjectEntry
+	if err := n.Decode(&e); err != nil {
+		return ProjectRef{}, false
+	}
+	if e.URL == "" {
+		return ProjectRef{Owner: e.Owner, Number: e.Number}, true
+	}
+
+	urlNode := mappingValue(n, "url")
+	owner, number, err := ParseProjectURL(e.URL)
+	if err != nil {
+		v.errorf(urlNode, key+".url", "%v", err)
+		return ProjectRef{}, false
+	}
+	if e.Owner != "" && e.Owner != owner {
+		v.errorf(mappingValue(n, "owner"), key+".owner", "%q contradicts url (owner %q); remove one of them", e.Owner, owner)
+	}
+	if e.Number != 0 && e.Number != number {
+		v.errorf(mappingValue(n, "number"), key+".number", "%d contradicts url (number %d); remove one of them", e.Number, number)
+	}
+	return ProjectRef{Owner: owner, Number: number}, true
+}
+
+// checkLimits は予算・リトライ・検証・添付ファイル・参照先の展開・プロンプトの値の範囲とテンプレートを検証する
+func (v *validator) checkLimits(root *yaml.Node, c *ProjectConfig) {
+	if c.Budget != nil {
+		limits := map[string]BudgetLimit{"per_task": c.Budget.PerTask, "daily": c.Budget.Daily, "project": c.Budget.Project}
+		for scope, l := range limits {
+			if l.USD < 0 {
+				v.errorAt(root, "budget."+scope+".usd", "must not be negative")
+			}
+			if l.Tokens < 0 {
+				v.errorAt(root, "budget."+scope+".tokens", "must not be negative")
+			}
+			if l.Time.Duration < 0 {
+				v.errorAt(root, "budget."+scope+".time", "must not be negative")
+			}
+		}
+	}
+
+	if c.Retry != nil {
+		if c.Retry.MaxAttempts < 0 {
+			v.errorAt(root, "retry.max_attempts", "must not be negative")
+		}
+		if c.Retry.MaxBackoff.Duration > 0 && c.Retry.Backoff.Duration > c.Retry.MaxBackoff.Duration {
+			v.errorAt(root, "retry.backoff", "%s is longer than max_backoff (%s)", c.Retry.Backoff, c.Retry.MaxBackoff)
+		}
+		// Ready などと同じ名前にすると、失敗したタスクが再び実行される・返信待ちと区別できなくなる
+		switch c.Retry.FailureStatus {
+		case "Ready", "In progress", "Needs input":
+			v.errorAt(root, "retry.failure_status", "%q is already used by vibe", c.Retry.FailureStatus)
+		}
+	}
+
+	if c.Verify != nil {
+		if c.Verify.MaxIterations < 0 {
+			v.errorAt(root, "verify.max_iterations", "must not be negative")
+		}
+		if len(c.Verify.Command) == 0 && !c.Verify.IsZero() {
+			v.errorAt(root, "verify", "command is required")
+		}
+	}
+
+	if c.Attachments != nil {
+		if c.Attachments.MaxSizeMB < 0 {
+			v.errorAt(root, "attachments.max_size_mb", "must not be negative")
+		}
+		if c.Attachments.MaxTotalMB < 0 {
+			v.errorAt(root, "attachments.max_total_mb", "must not be negative")
+		}
+	}
+
+	if c.Related != nil {
+		limits := map[string]int{"depth": c.Related.Depth, "max_items": c.Related.MaxItems, "max_chars": c.Related.MaxChars}
+		for key, n := range limits {
+			if n < 0 {
+				v.errorAt(root, "related."+key, "must not be negative")
+			}
+		}
+	}
+
+	if c.Prompt != nil {
+		if c.Prompt.MaxTokens < 0 {
+			v.errorAt(root, "prompt.max_tokens", "must not be negative")
+		}
+		if c.Prompt.KeepRecent < 0 {
+			v.errorAt(root, "prompt.keep_recent", "must not be negative")
+		}
+	}
+
+	if c.IssueForm != nil {
+		if _, err := c.IssueForm.ParseTemplate(); err != nil {
+			v.errorAt(root, "issue_form.template", "invalid template: %v", err)
+		}
+	}
+}
+
+// errorAt はドット区切りのキーの位置にエラーを記録する
+func (v *validator) errorAt(root *yaml.Node, key, format string, args ...any) {
+	n := root
+	for _, name := range strings.Split(key, ".") {
+		if child := mappingValue(n, name); child != nil {
+			n = child
+		}
+	}
+	v.errorf(n, key, format, args...)
+}
+
+// yamlFields は構造体のYAMLキーとフィールドの対応を返す
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// mappingKey はマッピングノードから key のキーノードを返す
+func mappingKey(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i]
+		}
+	}
+	return nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "true or false"
+	default:
+		return "a string"
+	}
+}
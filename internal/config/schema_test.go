@@ -0,0 +1,235 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeProjectConfig はテスト用の .vibe.yaml を一時ディレクトリに書き込んでパスを返す
+func writeProjectConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".vibe.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateFile(t *testing.T) {
+	// want は "line severity key: message の一部"
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "valid",
+			content: "version: 1\nproject:\n  url: https://github.com/orgs/acme/projects/3\nbudget:\n  daily:\n    usd: 20\n",
+		},
+		{
+			name:    "unknown key",
+			content: "version: 1\nbudgt:\n  daily:\n    usd: 20\n",
+			want:    []string{`2 error budgt: unknown key (did you mean "budget"?)`},
+		},
+		{
+			name:    "unknown nested key",
+			content: "version: 1\nbudget:\n  daly:\n    usd: 20\n",
+			want:    []string{`3 error budget.daly: unknown key (did you mean "daily"?)`},
+		},
+		{
+			name:    "wrong type",
+			content: "version: 1\nretry:\n  max_attempts: three\n",
+			want:    []string{`3 error retry.max_attempts: expected an integer, got "three"`},
+		},
+		{
+			name:    "invalid duration",
+			content: "version: 1\nretry:\n  backoff: soon\n",
+			want:    []string{"3 error retry.backoff: "},
+		},
+		{
+			name:    "every problem is reported",
+			content: "version: 1\nbudgt: {}\nretry:\n  max_attempts: three\n",
+			want:    []string{"2 error budgt: unknown key", "4 error retry.max_attempts: expected"},
+		},
+		{
+			name:    "negative limit",
+			content: "version: 1\nbudget:\n  daily:\n    usd: -1\n",
+			want:    []string{"4 error budget.daily.usd: must not be negative"},
+		},
+		{
+			name:    "backoff longer than max_backoff",
+			content: "version: 1\nretry:\n  backoff: 10m\n  max_backoff: 1m\n",
+			want:    []string{"3 error retry.backoff: 10m0s is longer than max_backoff (1m0s)"},
+		},
+		{
+			name:    "unsupported version",
+			content: "version: 2\n",
+			want:    []string{"1 error version: unsupported version 2"},
+		},
+		{
+			name:    "invalid version",
+			content: "version: latest\n",
+			want:    []string{`1 error version: must be a positive integer, got "latest"`},
+		},
+		{
+			name:    "url contradicts number",
+			content: "version: 1\nproject:\n  url: https://github.com/orgs/acme/projects/3\n  number: 4\n",
+			want:    []string{"4 error project.number: 4 contradicts url (number 3)"},
+		},
+		{
+			name:    "duplicate projects",
+			content: "version: 1\nprojects:\n  - url: https://github.com/orgs/acme/projects/3\n  - owner: acme\n    number: 3\n",
+			want:    []string{"4 error projects[1]: duplicates projects[0] (acme #3)"},
+		},
+		{
+			name:    "work_dir only in projects entries",
+			content: "version: 1\nproject:\n  owner: acme\n  number: 3\n  work_dir: ./api\n",
+			want:    []string{"5 error project.work_dir: only allowed in projects entries"},
+		},
+		{
+			name:    "legacy file is migrated in memory with warnings",
+			content: "project_owner: acme\nproject_number: 3\nbudgt: {}\n",
+			want: []string{
+				"0 info version: is not set; add version: 1",
+				"1 warning project_owner: is deprecated; use project.owner",
+				"2 warning project_number: is deprecated; use project.number",
+				"3 warning budgt: unknown key",
+			},
+		},
+		{
+			name:    "token in the project file",
+			content: "version: 1\ngithub_token: ghp_secret\n",
+			want:    []string{"2 error github_token: is not read from .vibe.yaml"},
+		},
+	}
+
+	severities := map[Severity]string{SeverityInfo: "info", SeverityWarning: "warning", SeverityError: "error"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := ValidateFile(writeProjectConfig(t, tt.content))
+			if err != nil {
+				t.Fatalf("ValidateFile() returned an error: %v", err)
+			}
+			got := make([]string, len(problems))
+			for i, p := range problems {
+				got[i] = fmt.Sprintf("%d %s %s: %s", p.Line, severities[p.Severity], p.Key, p.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateFile() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("problem %d = %q, want prefix %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProblemsErr(t *testing.T) {
+	problems := Problems{
+		{Path: ".vibe.yaml", Line: 2, Column: 1, Key: "budgt", Message: "unknown key", Severity: SeverityWarning},
+	}
+	if err := problems.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil for warnings only", err)
+	}
+	if got, want := problems.Warnings(), []string{".vibe.yaml:2:1: budgt: unknown key"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Warnings() = %q, want %q", got, want)
+	}
+
+	problems = append(problems, Problem{Path: ".vibe.yaml", Line: 4, Key: "retry.max_attempts", Message: "must not be negative", Severity: SeverityError})
+	err := problems.Err()
+	if err == nil || err.Error() != ".vibe.yaml:4: retry.max_attempts: must not be negative" {
+		t.Errorf("Err() = %v, want only the error", err)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	const legacy = `# vibe settings
+project_owner: acme # the organization
+project_number: 3
+github_token: ghp_secret
+budget:
+  daily:
+    usd: 20
+`
+	path := writeProjectConfig(t, legacy)
+
+	// write が false なら変更内容だけを返し、ファイルは書き換えない
+	changes, err := MigrateFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, len(changes))
+	for i, c := range changes {
+		keys[i] = c.Key
+	}
+	if want := []string{"project_owner", "project_number", "github_token", "version"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("changed keys = %q, want %q", keys, want)
+	}
+	if data, _ := os.ReadFile(path); string(data) != legacy {
+		t.Errorf("dry run rewrote the file:\n%s", data)
+	}
+
+	if _, err := MigrateFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated := string(data)
+	for _, want := range []string{"version: 1", "# vibe settings", "owner: acme # the organization", "number: 3", "usd: 20"} {
+		if !strings.Contains(migrated, want) {
+			t.Errorf("migrated file does not contain %q:\n%s", want, migrated)
+		}
+	}
+	for _, removed := range []string{"project_owner", "project_number", "github_token"} {
+		if strings.Contains(migrated, removed) {
+			t.Errorf("migrated file still contains %q:\n%s", removed, migrated)
+		}
+	}
+
+	// 移行後のファイルは問題なく検証でき、もう一度移行しても変わらない
+	problems, err := ValidateFile(path)
+	if err != nil || len(problems) > 0 {
+		t.Errorf("ValidateFile() after migration = %v, %v", problems, err)
+	}
+	if changes, err := MigrateFile(path, true); err != nil || len(changes) > 0 {
+		t.Errorf("second MigrateFile() = %v, %v, want no changes", changes, err)
+	}
+}
+
+func TestMigrateRedundantProject(t *testing.T) {
+	path := writeProjectConfig(t, "project:\n  url: https://github.com/users/alice/projects/7\n  owner: alice\n  number: 7\n")
+	changes, err := MigrateFile(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, len(changes))
+	for i, c := range changes {
+		keys[i] = c.Key
+	}
+	if want := []string{"project.owner", "project.number", "version"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("changed keys = %q, want %q", keys, want)
+	}
+}
+
+func TestSchemaCoversProjectConfig(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("vibe.schema.json is not valid JSON: %v", err)
+	}
+	for name := range yamlFields(reflect.TypeOf(ProjectConfig{})) {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("vibe.schema.json has no property for %q", name)
+		}
+	}
+}
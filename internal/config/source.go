@@ -0,0 +1,54 @@
+package config
+
+// 設定値の出どころ（優先順位の低い順）
+const (
+	LayerDefault = "default"
+	LayerGlobal  = "global"  // ~/.vibe/config.json
+	LayerProfile = "profile" // ~/.vibe/config.json のプロファイル
+	LayerLocal   = "local"   // .vibe.yaml
+	LayerEnv     = "env"     // VIBE_* 環境変数
+	LayerFlag    = "flag"    // --set key=value
+)
+
+// Source は設定値の出どころ
+type Source struct {
+	Layer    string
+	Location string // ファイルパス・環境変数名など
+}
+
+// String は出どころを表示用の文字列で返す
+func (s Source) String() string {
+	if s.Location == "" {
+		return s.Layer
+	}
+	return s.Location
+}
+
+// Origin はあるレイヤーが設定した値
+type Origin struct {
+	Source Source
+	Value  string
+}
+
+// Origins はキーを設定した全てのレイヤーを優先順位の低い順に返す
+// 最後の要素が有効な値
+func (c *Config) Origins(key string) []Origin {
+	return c.origins[key]
+}
+
+// SourceOf はキーの有効な値の出どころを返す（どのレイヤーも設定していなければ空）
+func (c *Config) SourceOf(key string) Source {
+	origins := c.origins[key]
+	if len(origins) == 0 {
+		return Source{}
+	}
+	return origins[len(origins)-1].Source
+}
+
+// record はキーをレイヤーが設定したことを記録する
+func (c *Config) record(k Key, src Source) {
+	if c.origins == nil {
+		c.origins = make(map[string][]Origin)
+	}
+	c.origins[k.Name] = append(c.origins[k.Name], Origin{Source: src, Value: k.Value(c)})
+}
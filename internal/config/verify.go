@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// VerifyConfig はClaude Codeの実行後に変更を検証するコマンド
+// 検証に失敗すると、出力を添えて同じセッションを再開し、成功するか上限に達するまで繰り返す
+type VerifyConfig struct {
+	Command       Commands `json:"command,omitempty" yaml:"command,omitempty"`               // 検証コマンド（例: make test）
+	MaxIterations int      `json:"max_iterations,omitempty" yaml:"max_iterations,omitempty"` // 初回を含むClaude Codeの最大実行回数
+	Timeout       Duration `json:"timeout,omitzero" yaml:"timeout,omitempty"`                // 1コマンドあたりのタイムアウト
+}
+
+// 検証のデフォルト値
+const (
+	DefaultVerifyMaxIterations = 3
+	DefaultVerifyTimeout       = 10 * time.Minute
+)
+
+// IsZero は検証が設定されていないかどうかを返す
+func (v VerifyConfig) IsZero() bool {
+	return len(v.Command) == 0 && v.MaxIterations == 0 && v.Timeout.Duration == 0
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checkboxPattern はMarkdownのタスクリストの項目（- [ ] / - [x]）に一致する
+var checkboxPattern = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.+?)\s*$`)
+
+// ChecklistItem はIssue本文の受け入れ基準（タスクリスト）の1項目
+type ChecklistItem struct {
+	Number  int    // 1から始まる番号（Claude Codeへの指示と報告で使う）
+	Text    string // 項目の本文
+	Checked bool   // Issue本文でチェック済みか
+	Done    bool   // Claude Codeが完了と報告したか
+	Note    string // Claude Codeの報告に添えられた説明
+}
+
+// ParseChecklist はIssue本文のタスクリストの項目を出現順に返す（コードブロック内は除く）
+func ParseChecklist(body string) []ChecklistItem {
+	var items []ChecklistItem
+	forEachCheckbox(body, func(line string, m []string) string {
+		items = append(items, ChecklistItem{
+			Number:  len(items) + 1,
+			Text:    m[4],
+			Checked: m[2] != " ",
+		})
+		return line
+	})
+	return items
+}
+
+// CheckItems はIssue本文のタスクリストのうち、本文が texts のいずれかに一致する未チェックの項目をチェックする
+// Issueが編集されていても項目の本文で対応づける。チェックした項目の数を返す
+func CheckItems(body string, texts []string) (string, int) {
+	want := make(map[string]bool, len(texts))
+	for _, t := range texts {
+		want[t] = true
+	}
+	checked := 0
+	body = forEachCheckbox(body, func(line string, m []string) string {
+		if m[2] != " " || !want[m[4]] {
+			return line
+		}
+		delete(want, m[4])
+		checked++
+		return strings.Replace(line, m[1]+" "+m[3], m[1]+"x"+m[3], 1)
+	})
+	return body, checked
+}
+
+// forEachCheckbox はコードブロック外のタスクリストの各行を fn の戻り値に置き換える
+func forEachCheckbox(body string, fn func(line string, m []string) string) string {
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := checkboxPattern.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			lines[i] = fn(line, m)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ChecklistMarker はClaude Codeが受け入れ基準の達成状況を報告する行
+const ChecklistMarker = "ACCEPTANCE CRITERIA:"
+
+// reportLinePattern は報告の1行（例: "2. not done - テストが未作成"）に一致する
+var reportLinePattern = regexp.MustCompile(`(?i)^[-*\s]*(?:\[[ x]\]\s*)?#?(\d+)[.):]?\s*[:\-]?\s*(done|not done|partial|skipped)\b\s*[:\-–—]?\s*(.*)$`)
+
+// ApplyChecklistReport はClaude Codeの出力の最後の報告を items に反映し、報告を除いた出力を返す
+// 報告がなければ items と output をそのまま返す
+func ApplyChecklistReport(items []ChecklistItem, output string) ([]ChecklistItem, string) {
+	lines := strings.Split(output, "\n")
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimLeft(lines[i], " *_#>")
+		if len(line) >= len(ChecklistMarker) && strings.EqualFold(line[:len(ChecklistMarker)], ChecklistMarker) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return items, output
+	}
+
+	reported := make([]ChecklistItem, len(items))
+	copy(reported, items)
+	rest := lines[start+1:]
+	for len(rest) > 0 {
+		line := strings.TrimSpace(rest[0])
+		m := reportLinePattern.FindStringSubmatch(line)
+		if m == nil && line != "" {
+			// 報告の後に続く文章は出力に残す
+			break
+		}
+		rest = rest[1:]
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 || n > len(reported) {
+			continue
+		}
+		reported[n-1].Done = strings.EqualFold(m[2], "done")
+		reported[n-1].Note = strings.TrimSpace(m[3])
+	}
+	return reported, strings.TrimSpace(strings.Join(append(lines[:start:start], rest...), "\n"))
+}
+
+// ChecklistProgress は完了した項目（チェック済みまたは完了の報告）の数と全体の数を返す
+func ChecklistProgress(items []ChecklistItem) (done, total int) {
+	for _, item := range items {
+		if item.Checked || item.Done {
+			done++
+		}
+	}
+	return done, len(items)
+}
+
+// ChecklistSummary は達成状況を "Acceptance criteria: 3/5" の形式で返す（項目がなければ空）
+func ChecklistSummary(items []ChecklistItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	done, total := ChecklistProgress(items)
+	return fmt.Sprintf("Acceptance criteria: %d/%d", done, total)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Comment はIssueの本文またはコメント
+type Comment struct {
+	ID          string    // Node ID（Issue本文はIssueのNode ID）
+	Author      string    // 投稿者のログイン名（削除されたユーザーは "ghost"）
+	Association string    // 投稿者とリポジトリの関係（OWNER / MEMBER / COLLABORATOR / CONTRIBUTOR / NONE など）
+	Body        string    // Markdownの本文
+	URL         string    // コメントのURL
+	CreatedAt   time.Time // 投稿日時
+	UpdatedAt   time.Time // 最終更新日時
+}
+
+// Attribution は投稿者と投稿日時を表す文字列を返す（例: "@alice (owner) on 2026-01-02 15:04 UTC"）
+func (c Comment) Attribution() string {
+	author := "@" + c.Author
+	if a := strings.ToLower(c.Association); a != "" && a != "none" {
+		author += " (" + strings.ReplaceAll(a, "_", " ") + ")"
+	}
+	if c.CreatedAt.IsZero() {
+		return author
+	}
+	s := fmt.Sprintf("%s on %s", author, c.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	if c.UpdatedAt.Sub(c.CreatedAt) > time.Minute {
+		s += fmt.Sprintf(", updated %s", c.UpdatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	return s
+}
+
+// Attachment はIssueで参照されている画像・添付ファイル
+type Attachment struct {
+	URL     string // 参照されているURL
+	Path    string // ダウンロードしたローカルのパス（スキップした場合は空）
+	Size    int64  // ダウンロードしたサイズ（バイト）
+	Skipped string // ダウンロードしなかった理由
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IssueStateClosed はクローズ済みIssueの状態
+const IssueStateClosed = "CLOSED"
+
+// Dependency はタスクをブロックしている依存先のIssue/PR
+type Dependency struct {
+	URL        string // 依存先のIssue/PR URL
+	Done       bool   // 依存先が完了しているか（クローズ済み、またはチェック済み）
+	Unresolved bool   // 依存先が見つからない・参照できない（未完了として扱う）
+}
+
+var (
+	// タスクリスト項目: - [ ] #12 / - [x] owner/repo#3
+	taskListPattern = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	// Blocked by / Depends on 行
+	blockedByPattern = regexp.MustCompile(`(?i)^\s*(?:[-*+]\s+)?\**(?:blocked by|depends on)\**\s*:?\s*(.*)$`)
+	// Issue参照: URL / owner/repo#N / #N
+	issueRefPattern = regexp.MustCompile(`https://github\.com/([\w.-]+)/([\w.-]+)/(?:issues|pull)/(\d+)|(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+	// Issue URLからowner/repoを抽出する
+	issueURLPattern = regexp.MustCompile(`^https://github\.com/([\w.-]+)/([\w.-]+)/(?:issues|pull)/\d+`)
+)
+
+// ParseDependencies はIssue本文のタスクリストと "Blocked by" 行から依存先を抽出する
+// issueURL は "#12" のような相対参照を解決するために使用する
+func ParseDependencies(body, issueURL string) []Dependency {
+	var owner, repo string
+	if m := issueURLPattern.FindStringSubmatch(issueURL); m != nil {
+		owner, repo = m[1], m[2]
+	}
+
+	var deps []Dependency
+	seen := make(map[string]int)
+	add := func(text string, done bool) {
+		for _, url := range extractIssueRefs(text, owner, repo) {
+			if url == issueURL {
+				continue
+			}
+			if i, ok := seen[url]; ok {
+				deps[i].Done = deps[i].Done || done
+				continue
+			}
+			seen[url] = len(deps)
+			deps = append(deps, Dependency{URL: url, Done: done})
+		}
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if m := taskListPattern.FindStringSubmatch(line); m != nil {
+			add(m[2], m[1] != " ")
+			continue
+		}
+		if m := blockedByPattern.FindStringSubmatch(line); m != nil {
+			add(m[1], false)
+		}
+	}
+
+	return deps
+}
+
+// extractIssueRefs はテキスト中のIssue参照をURLに正規化して返す
+func extractIssueRefs(text, owner, repo string) []string {
+	var urls []string
+	for _, m := range issueRefPattern.FindAllStringSubmatch(text, -1) {
+		switch {
+		case m[1] != "":
+			urls = append(urls, m[0])
+		case m[4] != "":
+			urls = append(urls, IssueURL(m[4], m[5], m[6]))
+		case owner != "":
+			urls = append(urls, IssueURL(owner, repo, m[6]))
+		}
+	}
+	return urls
+}
+
+// IssueURL はowner/repo/番号からIssue URLを組み立てる
+func IssueURL(owner, repo, number string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/issues/%s", owner, repo, number)
+}
+
+// IsDone はタスクのIssueがクローズ済みかどうかを返す
+func (t *Task) IsDone() bool {
+	return t.IssueState == IssueStateClosed
+}
+
+// IsBlocked は未完了の依存先があるかどうかを返す
+func (t *Task) IsBlocked() bool {
+	for _, d := range t.Dependencies {
+		if !d.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule は実行可能なタスクを優先度順に並べて返す
+// 依存先が未完了のタスクは除外する。優先度が未設定のタスクは最後に回す
+func Schedule(tasks []*Task) []*Task {
+	ready := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.IsExecutable() {
+			ready = append(ready, t)
+		}
+	}
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		pi, pj := ready[i].Priority, ready[j].Priority
+		switch {
+		case pi == nil:
+			return false
+		case pj == nil:
+			return true
+		default:
+			return *pi < *pj
+		}
+	})
+
+	return ready
+}
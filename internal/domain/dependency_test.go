@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDependencies(t *testing.T) {
+	const issueURL = "https://github.com/o/r/issues/1"
+
+	tests := []struct {
+		name string
+		body string
+		want []Dependency
+	}{
+		{
+			name: "no references",
+			body: "Implement the feature.\n\nSee the design doc.",
+		},
+		{
+			name: "task list with relative references",
+			body: "- [ ] #2\n- [x] #3\n* [X] #4",
+			want: []Dependency{
+				{URL: "https://github.com/o/r/issues/2"},
+				{URL: "https://github.com/o/r/issues/3", Done: true},
+				{URL: "https://github.com/o/r/issues/4", Done: true},
+			},
+		},
+		{
+			name: "cross-repository reference and URL",
+			body: "- [ ] other/repo#5\n- [ ] https://github.com/o/r/pull/6",
+			want: []Dependency{
+				{URL: "https://github.com/other/repo/issues/5"},
+				{URL: "https://github.com/o/r/pull/6"},
+			},
+		},
+		{
+			name: "blocked by and depends on lines",
+			body: "Blocked by #7, #8\n**Depends on:** other/repo#9",
+			want: []Dependency{
+				{URL: "https://github.com/o/r/issues/7"},
+				{URL: "https://github.com/o/r/issues/8"},
+				{URL: "https://github.com/other/repo/issues/9"},
+			},
+		},
+		{
+			name: "references in prose are ignored",
+			body: "This is related to #10 but does not depend on it.",
+		},
+		{
+			name: "self reference is ignored",
+			body: "- [ ] #1\n- [ ] #2",
+			want: []Dependency{
+				{URL: "https://github.com/o/r/issues/2"},
+			},
+		},
+		{
+			name: "duplicates are merged and done wins",
+			body: "Blocked by #2\n- [x] #2",
+			want: []Dependency{
+				{URL: "https://github.com/o/r/issues/2", Done: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDependencies(tt.body, issueURL)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDependencies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDependenciesWithoutIssueURL(t *testing.T) {
+	// リポジトリが分からなければ "#12" は解決できないが、owner/repo#N は解決できる
+	got := ParseDependencies("- [ ] #2\n- [ ] other/repo#3", "")
+	want := []Dependency{{URL: "https://github.com/other/repo/issues/3"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDependencies() = %+v, want %+v", got, want)
+	}
+}
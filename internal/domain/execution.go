@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
 // Execution はClaude Code実行結果を表す
 type Execution struct {
@@ -12,28 +17,136 @@ type Execution struct {
 	StartedAt time.Time
 	EndedAt   time.Time
 	Duration  time.Duration
+
+	CostUSD      float64 // Claude Codeが報告したコスト（ドル）
+	InputTokens  int     // 入力トークン数（キャッシュ分を含む）
+	OutputTokens int     // 出力トークン数
+	NumTurns     int     // エージェントのターン数
+
+	BudgetExceeded string // 予算超過の理由（超過していなければ空）
+	Question       string // Claude Codeが最後に尋ねた質問（追加の入力が不要なら空）
+
+	ExitCode   int    // claudeプロセスの終了コード
+	TimedOut   bool   // タイムアウトで終了したか
+	Attempts   int    // 試行回数（リトライを含む）
+	ErrorClass string // リトライ判定で一致したエラー分類
+
+	Checklist []ChecklistItem // 受け入れ基準の達成状況（Issueにタスクリストがなければ空）
+
+	Hooks      []HookResult // 実行したフックの結果（実行順）
+	Iterations []Iteration  // 検証ループの各回の結果（検証が設定されていなければ空）
+}
+
+// Iteration は検証ループの1回分（Claude Codeの実行と検証）を表す
+type Iteration struct {
+	Number       int    // 1から始まる回数
+	SessionID    string // Claude Codeのセッション（2回目以降は再開したセッション）
+	Success      bool   // Claude Codeの実行が成功したか
+	Verified     bool   // 検証コマンドが全て成功したか
+	Error        string // Claude Codeの実行エラー
+	Attempts     int    // この回の試行回数（リトライを含む）
+	CostUSD      float64
+	Tokens       int
+	Duration     time.Duration // Claude Codeと検証コマンドの実行時間
+	Verification []HookResult  // 検証コマンドの結果（Claude Codeが失敗した場合は空）
+}
+
+// HookResult はフック（実行前後のシェルコマンド）の実行結果を表す
+type HookResult struct {
+	Stage    string // pre_run / post_run / on_success / on_failure / verify
+	Command  string
+	ExitCode int
+	Output   string // 標準出力と標準エラー出力（長い場合は末尾のみ）
+	Error    string // 起動できない・タイムアウトなど（正常終了なら空）
+	Duration time.Duration
+}
+
+// Failed はフックが失敗したかどうかを返す
+func (h HookResult) Failed() bool {
+	return h.ExitCode != 0 || h.Error != ""
+}
+
+// pullRequestPattern はPull RequestのURLに一致する
+var pullRequestPattern = regexp.MustCompile(`https://[^\s/]+/[^\s/]+/[^\s/]+/pull/\d+`)
+
+// PullRequestURL は実行で作成されたPull RequestのURLを返す（見つからなければ空）
+// フック（後に実行したものを優先）、Claude Codeの出力の順に、最後に現れたURLを探す
+func (e *Execution) PullRequestURL() string {
+	for i := len(e.Hooks) - 1; i >= 0; i-- {
+		if urls := pullRequestPattern.FindAllString(e.Hooks[i].Output, -1); len(urls) > 0 {
+			return urls[len(urls)-1]
+		}
+	}
+	if urls := pullRequestPattern.FindAllString(e.Output, -1); len(urls) > 0 {
+		return urls[len(urls)-1]
+	}
+	return ""
+}
+
+// Tokens は入出力トークン数の合計を返す
+func (e *Execution) Tokens() int {
+	return e.InputTokens + e.OutputTokens
+}
+
+// NeedsInput はClaude Codeが質問で実行を終え、返信を待っているかどうかを返す
+func (e *Execution) NeedsInput() bool {
+	return e.Success && e.Question != ""
 }
 
 // Summary は実行結果の概要を返す（Projectに保存する用）
 func (e *Execution) Summary() string {
+	if e.BudgetExceeded != "" {
+		return "Budget exceeded: " + e.BudgetExceeded
+	}
+	if e.NeedsInput() {
+		return "Needs input: " + truncate(e.Question, 500)
+	}
 	if !e.Success {
-		if len(e.Error) > 200 {
-			return "Error: " + e.Error[:200] + "..."
-		}
-		return "Error: " + e.Error
+		return e.failure() + truncate(e.Error, 200)
 	}
 
-	if len(e.Output) > 500 {
-		return e.Output[:500] + "..."
+	output := truncate(e.Output, 500)
+	if c := ChecklistSummary(e.Checklist); c != "" {
+		return c + "\n\n" + output
 	}
-	return e.Output
+	return output
+}
+
+// truncate は s を先頭の n 文字に切り詰め、切り詰めた場合は "..." を付ける（マルチバイト文字の途中では切らない）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
+
+// failure は失敗した実行の Summary の見出し（例: "Failed (rate_limit, timed out, 3 attempts): "）を返す
+// Status だけでは分からない失敗の分類と試行回数を添える
+func (e *Execution) failure() string {
+	var details []string
+	if e.ErrorClass != "" {
+		details = append(details, e.ErrorClass)
+	}
+	if e.TimedOut {
+		details = append(details, "timed out")
+	}
+	if e.Attempts > 1 {
+		details = append(details, fmt.Sprintf("%d attempts", e.Attempts))
+	}
+	if len(details) == 0 {
+		return "Failed: "
+	}
+	return "Failed (" + strings.Join(details, ", ") + "): "
 }
 
 // NewStatus は実行結果に基づいて新しいStatusを返す
 func (e *Execution) NewStatus() Status {
+	if e.NeedsInput() {
+		return StatusNeedsInput
+	}
 	if e.Success {
 		return StatusInReview
 	}
-	// 失敗してもInReviewにして確認を促す
-	return StatusInReview
+	return StatusFailed
 }
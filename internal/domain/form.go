@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// noResponse はIssueフォームで未入力の項目に表示される値
+const noResponse = "_No response_"
+
+// formHeadingPattern はIssueフォームが生成する見出し（### ラベル）
+var formHeadingPattern = regexp.MustCompile(`^###\s+(.+?)\s*#*\s*$`)
+
+// IssueForm はIssueフォームの見出しごとの値
+type IssueForm map[string]string
+
+// ParseIssueForm はIssueフォームで作成されたIssue本文を見出しごとの値に分解する
+// 未入力（_No response_）の項目は空文字列になる。見出しがなければ空の IssueForm を返す
+func ParseIssueForm(body string) IssueForm {
+	form := make(IssueForm)
+	var name string
+	var value []string
+	flush := func() {
+		if name == "" {
+			return
+		}
+		v := strings.TrimSpace(strings.Join(value, "\n"))
+		if v == noResponse {
+			v = ""
+		}
+		form[name] = v
+	}
+
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := formHeadingPattern.FindStringSubmatch(line); m != nil && !inFence {
+			flush()
+			name, value = m[1], nil
+			continue
+		}
+		if name != "" {
+			value = append(value, line)
+		}
+	}
+	flush()
+	return form
+}
+
+// Get は見出しの値を返す（見出しは大文字小文字を区別しない）
+func (f IssueForm) Get(name string) (string, bool) {
+	if v, ok := f[name]; ok {
+		return v, true
+	}
+	for k, v := range f {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// checkedPattern はチェックボックスの項目（- [x] ラベル）
+var checkedPattern = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.+)$`)
+
+// FormList はフォームの値を項目の一覧にする
+// チェックボックス（- [x] / - [ ]）はチェックした項目のみ、それ以外は行・カンマ区切り（先頭の - は除く）
+func FormList(value string) []string {
+	var items []string
+	for _, line := range strings.Split(value, "\n") {
+		if m := checkedPattern.FindStringSubmatch(line); m != nil {
+			if m[1] != " " {
+				items = append(items, strings.TrimSpace(m[2]))
+			}
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*+"))
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
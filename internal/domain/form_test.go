@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIssueForm(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want IssueForm
+	}{
+		{
+			name: "no headings",
+			body: "Just a plain issue body.",
+			want: IssueForm{},
+		},
+		{
+			name: "form fields",
+			body: "### Model\n\nsonnet\n\n### Timeout\n\n45m\n\n### Prompt\n\nAdd a health check.\nKeep it small.",
+			want: IssueForm{
+				"Model":   "sonnet",
+				"Timeout": "45m",
+				"Prompt":  "Add a health check.\nKeep it small.",
+			},
+		},
+		{
+			name: "no response is empty",
+			body: "### Model\n\n_No response_\n\n### Work dir\n\nservices/api",
+			want: IssueForm{"Model": "", "Work dir": "services/api"},
+		},
+		{
+			name: "text before the first heading is ignored",
+			body: "Intro text\n\n### Model\n\nopus",
+			want: IssueForm{"Model": "opus"},
+		},
+		{
+			name: "CRLF line endings and closing hashes",
+			body: "### Model ###\r\n\r\nhaiku\r\n",
+			want: IssueForm{"Model": "haiku"},
+		},
+		{
+			name: "headings inside code blocks are part of the value",
+			body: "### Prompt\n\n```md\n### Not a field\n```\n\n### Model\n\nopus",
+			want: IssueForm{
+				"Prompt": "```md\n### Not a field\n```",
+				"Model":  "opus",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseIssueForm(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIssueForm() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueFormGet(t *testing.T) {
+	form := IssueForm{"Allowed tools": "Read"}
+	if v, ok := form.Get("allowed tools"); !ok || v != "Read" {
+		t.Errorf("Get() = %q, %v, want %q, true", v, ok, "Read")
+	}
+	if _, ok := form.Get("Model"); ok {
+		t.Error("Get() found a missing field")
+	}
+}
+
+func TestFormList(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "empty", value: ""},
+		{name: "comma separated", value: "Read, Edit ,Bash", want: []string{"Read", "Edit", "Bash"}},
+		{name: "bullet list", value: "- Read\n- Bash(go test:*)", want: []string{"Read", "Bash(go test:*)"}},
+		{name: "checkboxes keep checked items", value: "- [x] Read\n- [ ] Edit\n- [X] Bash", want: []string{"Read", "Bash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormList(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FormList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Graph はタスクの依存関係グラフ（DAG）
+type Graph struct {
+	tasks []*Task
+	byURL map[string]*Task
+}
+
+// NewGraph はタスク一覧から依存関係グラフを作成する
+func NewGraph(tasks []*Task) *Graph {
+	g := &Graph{
+		tasks: tasks,
+		byURL: make(map[string]*Task),
+	}
+	for _, t := range tasks {
+		if t.IssueURL != "" {
+			g.byURL[t.IssueURL] = t
+		}
+	}
+	return g
+}
+
+// Cycles は依存関係の循環を検出して返す
+// 各循環はIssue URLの列で、先頭と末尾が同じ要素になる
+func (g *Graph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(url string)
+	visit = func(url string) {
+		state[url] = visiting
+		stack = append(stack, url)
+
+		if t, ok := g.byURL[url]; ok {
+			for _, d := range t.Dependencies {
+				switch state[d.URL] {
+				case unvisited:
+					visit(d.URL)
+				case visiting:
+					// スタック上の位置から循環を切り出す
+					for i := len(stack) - 1; i >= 0; i-- {
+						if stack[i] == d.URL {
+							cycle := append([]string{}, stack[i:]...)
+							cycles = append(cycles, append(cycle, d.URL))
+							break
+						}
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[url] = visited
+	}
+
+	for _, t := range g.tasks {
+		if t.IssueURL != "" && state[t.IssueURL] == unvisited {
+			visit(t.IssueURL)
+		}
+	}
+
+	return cycles
+}
+
+// WriteText は依存関係をテキストのツリー形式で出力する
+func (g *Graph) WriteText(w io.Writer) error {
+	for _, t := range g.tasks {
+		mark := "○"
+		switch {
+		case t.IsDone():
+			mark = "●"
+		case t.IsBlocked():
+			mark = "⊘"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s  %s\n", mark, t.Title, g.label(t.IssueURL)); err != nil {
+			return err
+		}
+		for i, d := range t.Dependencies {
+			branch := "├─"
+			if i == len(t.Dependencies)-1 {
+				branch = "└─"
+			}
+			state := "open"
+			if d.Done {
+				state = "done"
+			}
+			if _, err := fmt.Fprintf(w, "   %s blocked by %s [%s]\n", branch, g.label(d.URL), state); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteDOT は依存関係をGraphviz DOT形式で出力する
+// エッジは依存先から依存元へ向く（実行順）
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph vibe {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	nodes := make(map[string]bool)
+	node := func(url, label, style string) {
+		if nodes[url] {
+			return
+		}
+		nodes[url] = true
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n", url, label, style)
+	}
+
+	for _, t := range g.tasks {
+		if t.IssueURL == "" {
+			continue
+		}
+		style := ""
+		switch {
+		case t.IsDone():
+			style = ", style=filled, fillcolor=lightgray"
+		case t.IsBlocked():
+			style = ", color=red"
+		}
+		node(t.IssueURL, t.Title, style)
+	}
+	for _, t := range g.tasks {
+		for _, d := range t.Dependencies {
+			style := ", style=dashed"
+			if d.Done {
+				style = ", style=\"dashed,filled\", fillcolor=lightgray"
+			}
+			node(d.URL, g.label(d.URL), style)
+			fmt.Fprintf(&b, "  %q -> %q;\n", d.URL, t.IssueURL)
+		}
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label はIssue URLを owner/repo#N 形式に短縮する
+func (g *Graph) label(url string) string {
+	if url == "" {
+		return "(draft)"
+	}
+	trimmed := strings.TrimPrefix(url, "https://github.com/")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) == 4 {
+		return fmt.Sprintf("%s/%s#%s", parts[0], parts[1], parts[3])
+	}
+	return url
+}
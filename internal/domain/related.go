@@ -0,0 +1,39 @@
+package domain
+
+// Related はタスクのIssue・コメントから参照されているIssue/PR
+type Related struct {
+	URL           string
+	Ref           string // owner/repo#N
+	Title         string
+	Body          string // Markdownの本文
+	State         string // OPEN / CLOSED / MERGED
+	IsPullRequest bool
+	Files         []ChangedFile // PRの変更ファイル
+	TotalFiles    int           // PRの変更ファイルの総数（Files は上限までの一部の場合がある）
+}
+
+// ChangedFile はPRで変更されたファイル
+type ChangedFile struct {
+	Path      string
+	Additions int
+	Deletions int
+}
+
+// ParseIssueRefs はテキスト中の #N・owner/repo#N・Issue/PR URLを出現順に重複なくURLに正規化して返す
+// issueURL は "#12" のような相対参照の解決に使い、issueURL 自身への参照は除く
+func ParseIssueRefs(text, issueURL string) []string {
+	var owner, repo string
+	if m := issueURLPattern.FindStringSubmatch(issueURL); m != nil {
+		owner, repo = m[1], m[2]
+	}
+
+	var urls []string
+	seen := map[string]bool{issueURL: true}
+	for _, url := range extractIssueRefs(text, owner, repo) {
+		if !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Status はタスクの状態を表す
 type Status string
@@ -9,6 +12,8 @@ const (
 	StatusReady      Status = "Ready"
 	StatusInProgress Status = "In progress"
 	StatusInReview   Status = "In review"
+	StatusNeedsInput Status = "Needs input" // Claude Codeが質問し、Issueでの返信を待っている
+	StatusFailed     Status = "Failed"      // 実行が失敗した（オプション名は retry.failure_status で変更できる）
 )
 
 // Task はGitHub Projectのタスクを表す
@@ -22,17 +27,90 @@ type Task struct {
 	SessionID  string     // Claude CodeのセッションID
 	ExecutedAt *time.Time // 最終実行日時
 	IssueURL   string     // 関連Issue/PR URL
+
+	Priority         *float64     // 優先度（値が小さいほど先に実行）
+	Cost             float64      // 累計コスト（ドル）
+	Attempts         int          // 直近の実行の試行回数
+	IssueState       string       // Issueの状態 (OPEN / CLOSED)
+	IssueBody        string       // Issue本文（依存関係・Issueフォームの解析用）
+	IssueAuthor      string       // Issueの作成者（Issueフォームを信頼するかの判定用）
+	IssueAssociation string       // Issueの作成者とリポジトリの関係（OWNER / MEMBER / COLLABORATOR など）
+	Repository       string       // Issueのリポジトリ（owner/name）
+	Labels           []string     // Issueのラベル
+	Dependencies     []Dependency // このタスクをブロックしている依存先
+
+	Timeout      time.Duration // 1タスクのタイムアウト（Issueフォームで指定した場合）
+	Model        string        // Claude Codeのモデル（Issueフォームで指定した場合）
+	AllowedTools []string      // Claude Codeに許可するツール（Issueフォームで指定した場合）
+
+	Resume        bool          // SessionID のセッションを再開し、Prompt（質問への返信・vibe resume の指示）だけを送るか
+	PromptCursor  *PromptCursor // Prompt に含めた最後のコメント（実行後に記録し、次の再開ではこれより後のコメントだけを送る）
+	Comments      []Comment     // Prompt に含めたIssueの本文・コメント（添付ファイルの検出に使う）
+	PromptNote    string        // Prompt を予算に収めるために要約・省略した内容（表示用）
+	Summarization *Execution    // Prompt の組み立てでコメントの要約に使ったClaude Codeの実行（予算に記録する）
+}
+
+// PromptCursor はプロンプトに含めた最後のIssueコメントを表す
+type PromptCursor struct {
+	CommentID string    `json:"comment_id,omitempty"` // コメントのNode ID（コメントがなければ空）
+	CreatedAt time.Time `json:"created_at"`           // コメントの投稿日時（コメントがなければ読み込んだ日時）
+}
+
+// After は c が other より後のコメントを指すかどうかを返す（other が nil なら true）
+func (c *PromptCursor) After(other *PromptCursor) bool {
+	if c == nil {
+		return false
+	}
+	return other == nil || c.CreatedAt.After(other.CreatedAt)
 }
 
 // IsExecutable はタスクが実行可能かどうかを返す
 func (t *Task) IsExecutable() bool {
 	// Promptは実行時にIssueから読み込むため、IssueURLの有無で判定
-	return t.Status == StatusReady && t.IssueURL != ""
+	return t.Status == StatusReady && t.IssueURL != "" && !t.IsBlocked()
 }
 
 // TaskFilter はタスクのフィルタ条件
 type TaskFilter struct {
-	Status  *Status
-	Limit   int
-	OrderBy string
+	Status     *Status
+	Labels     []string // 全てのラベルが付いたタスクのみ（大文字小文字を区別しない）
+	Repository string   // このリポジトリ（owner/name または name）のタスクのみ
+	Limit      int
+	OrderBy    string
+}
+
+// Matches はタスクがフィルタ条件（Limit 以外）を満たすかどうかを返す
+func (f *TaskFilter) Matches(t *Task) bool {
+	if f == nil {
+		return true
+	}
+	if f.Status != nil && t.Status != *f.Status {
+		return false
+	}
+	if f.Repository != "" {
+		repo := t.Repository
+		if !strings.Contains(f.Repository, "/") {
+			// name のみの指定は owner を問わない
+			_, repo, _ = strings.Cut(t.Repository, "/")
+		}
+		if !strings.EqualFold(repo, f.Repository) {
+			return false
+		}
+	}
+	for _, label := range f.Labels {
+		if !t.HasLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasLabel はIssueにラベルが付いているかどうかを返す（大文字小文字を区別しない）
+func (t *Task) HasLabel(name string) bool {
+	for _, l := range t.Labels {
+		if strings.EqualFold(l, name) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+var (
+	// MarkdownのリンクやHTMLの属性に含まれるURL
+	attachmentURLPattern = regexp.MustCompile(`https://[^\s<>"'()\[\]]+`)
+	// GitHubのホストで添付ファイルとして扱うパス（/user-attachments/assets/<id>、/<owner>/<repo>/files/<id>/<name> など）
+	githubAttachmentPath = regexp.MustCompile(`^/(?:user-attachments/(?:assets|files)/|[\w.-]+/[\w.-]+/(?:files|assets)/\d+)`)
+	// ファイル名に使えない文字
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// AttachmentURLs はコメントで参照されている添付ファイルのURLを出現順に重複なく返す
+// opts で許可したホストのURLのみを返す。GitHubのホスト（githubHost・github.com）は添付ファイルのパスのみ
+func AttachmentURLs(comments []domain.Comment, opts config.AttachmentsConfig, githubHost string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, c := range comments {
+		for _, raw := range attachmentURLPattern.FindAllString(c.Body, -1) {
+			raw = strings.TrimRight(raw, ".,;:!?")
+			if seen[raw] {
+				continue
+			}
+			u, err := url.Parse(raw)
+			if err != nil {
+				continue
+			}
+			host := u.Hostname()
+			isGitHub := strings.EqualFold(host, githubHost) || strings.EqualFold(host, config.DefaultHost)
+			if isGitHub && !githubAttachmentPath.MatchString(u.Path) {
+				continue
+			}
+			if !opts.Allows(host) && !strings.EqualFold(host, githubHost) {
+				continue
+			}
+			seen[raw] = true
+			urls = append(urls, raw)
+		}
+	}
+	return urls
+}
+
+// DownloadAttachments はタスクのプロンプトに含めたコメントで参照されている添付ファイルを dir にダウンロードする
+// 上限を超えるファイルやダウンロードに失敗したファイルは Skipped に理由を記録して続ける
+func (s *TaskService) DownloadAttachments(ctx context.Context, task *domain.Task, dir string, opts config.AttachmentsConfig) []domain.Attachment {
+	urls := AttachmentURLs(task.Comments, opts, s.client.host)
+	attachments := make([]domain.Attachment, 0, len(urls))
+	remaining := opts.MaxTotal()
+	for i, raw := range urls {
+		a := domain.Attachment{URL: raw}
+		if remaining <= 0 {
+			a.Skipped = "total size limit reached"
+			attachments = append(attachments, a)
+			continue
+		}
+		limit := min(opts.MaxSize(), remaining)
+		if err := s.download(ctx, &a, dir, i, limit); err != nil {
+			a.Skipped = err.Error()
+			if errors.Is(err, ErrTooLarge) {
+				a.Skipped = "larger than " + formatSize(limit)
+				if limit < opts.MaxSize() {
+					a.Skipped = "total size limit reached"
+				}
+			}
+		}
+		remaining -= a.Size
+		attachments = append(attachments, a)
+	}
+	return attachments
+}
+
+// formatSize はバイト数を 1 MB 以上なら MB、1 KB 以上なら KB（小数第1位まで）、それ未満ならバイトで表す
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%g MB", math.Round(float64(n)/(1<<20)*10)/10)
+	case n >= 1<<10:
+		return fmt.Sprintf("%g KB", math.Round(float64(n)/(1<<10)*10)/10)
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
+
+// download は添付ファイルを dir に "<番号>-<ファイル名>" で保存する
+// URLに拡張子がなければContent-Typeから付ける
+func (s *TaskService) download(ctx context.Context, a *domain.Attachment, dir string, index int, limit int64) error {
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return err
+	}
+	name := unsafeFileChars.ReplaceAllString(path.Base(u.Path), "_")
+	if name == "" || name == "." || name == "_" {
+		name = "attachment"
+	}
+	p := filepath.Join(dir, fmt.Sprintf("%02d-%s", index+1, name))
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	size, contentType, err := s.client.Download(ctx, a.URL, f, limit)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(p)
+		return err
+	}
+
+	if path.Ext(name) == "" {
+		if mediaType, _, perr := mime.ParseMediaType(contentType); perr == nil {
+			if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+				if err := os.Rename(p, p+exts[0]); err == nil {
+					p += exts[0]
+				}
+			}
+		}
+	}
+	a.Path = p
+	a.Size = size
+	return nil
+}
+
+// AttachmentSection は添付ファイルのローカルのパスをプロンプトに追加するMarkdownで返す（添付ファイルがなければ空）
+func AttachmentSection(attachments []domain.Attachment) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("### Attachments\n\n")
+	b.WriteString("The issue references these files. They were downloaded so you can read them at the local paths:\n\n")
+	for _, a := range attachments {
+		if a.Path != "" {
+			fmt.Fprintf(&b, "- %s (from %s)\n", a.Path, a.URL)
+		} else {
+			fmt.Fprintf(&b, "- %s (not downloaded: %s)\n", a.URL, a.Skipped)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+func TestAttachmentURLs(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		opts       config.AttachmentsConfig
+		githubHost string
+		want       []string
+	}{
+		{
+			name: "attachment paths on github.com",
+			body: "![screenshot](https://github.com/user-attachments/assets/0f1e) and [log](https://github.com/o/r/files/123/log.txt)",
+			want: []string{"https://github.com/user-attachments/assets/0f1e", "https://github.com/o/r/files/123/log.txt"},
+		},
+		{
+			name: "other github.com paths are filtered out",
+			body: "See https://github.com/o/r/issues/12, https://github.com/o/r/pull/3 and https://github.com/o/r/blob/main/README.md",
+		},
+		{
+			name: "image hosts are allowed by default",
+			body: `<img src="https://private-user-images.githubusercontent.com/1/2.png?jwt=x">`,
+			want: []string{"https://private-user-images.githubusercontent.com/1/2.png?jwt=x"},
+		},
+		{
+			name: "other hosts need allowed_hosts",
+			body: "https://example.com/report.pdf",
+		},
+		{
+			name: "allowed_hosts adds a host",
+			body: "https://example.com/report.pdf",
+			opts: config.AttachmentsConfig{AllowedHosts: []string{"Example.com"}},
+			want: []string{"https://example.com/report.pdf"},
+		},
+		{
+			name: "trailing punctuation and duplicates",
+			body: "Logs: https://github.com/user-attachments/files/9/a.log. Again: https://github.com/user-attachments/files/9/a.log!",
+			want: []string{"https://github.com/user-attachments/files/9/a.log"},
+		},
+		{
+			name:       "GitHub Enterprise Server host",
+			body:       "https://ghe.example.com/user-attachments/assets/1 https://ghe.example.com/o/r/issues/2",
+			githubHost: "ghe.example.com",
+			want:       []string{"https://ghe.example.com/user-attachments/assets/1"},
+		},
+		{
+			name: "plain http is not an attachment",
+			body: "http://github.com/user-attachments/assets/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := tt.githubHost
+			if host == "" {
+				host = config.DefaultHost
+			}
+			got := AttachmentURLs([]domain.Comment{{Body: tt.body}}, tt.opts, host)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AttachmentURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
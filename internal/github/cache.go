@@ -0,0 +1,83 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DefaultMetadataTTL はProjectのメタデータのキャッシュの有効期間
+const DefaultMetadataTTL = time.Hour
+
+// MetadataCache はProjectのメタデータ（Project IDとフィールドの定義）をディスクにキャッシュする
+// コマンドごとの Initialize の問い合わせ（Projectとフィールドの取得）を省く
+type MetadataCache struct {
+	Dir     string        // キャッシュを置くディレクトリ（~/.vibe/cache）
+	TTL     time.Duration // 有効期間（0 なら DefaultMetadataTTL）
+	Refresh bool          // キャッシュを読まずに取得し直す（取得した値は保存する）
+}
+
+// projectMetadata はキャッシュするProjectのメタデータ
+type projectMetadata struct {
+	ProjectID string                  `json:"project_id"`
+	Fields    map[string]ProjectField `json:"fields"`
+	CachedAt  time.Time               `json:"cached_at"`
+}
+
+// cacheUnsafeChars はファイル名に使えない文字
+var cacheUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// path はProjectのキャッシュファイルのパスを返す（ホスト・オーナー・番号ごと）
+func (c *MetadataCache) path(host, owner string, number int) string {
+	name := cacheUnsafeChars.ReplaceAllString(fmt.Sprintf("%s_%s_%d", host, owner, number), "_")
+	return filepath.Join(c.Dir, name+".json")
+}
+
+// load は有効期間内のキャッシュを返す（なければ nil）
+func (c *MetadataCache) load(host, owner string, number int) *projectMetadata {
+	if c.Refresh {
+		return nil
+	}
+	data, err := os.ReadFile(c.path(host, owner, number))
+	if err != nil {
+		return nil
+	}
+	var m projectMetadata
+	if err := json.Unmarshal(data, &m); err != nil || m.ProjectID == "" {
+		return nil
+	}
+	if m.Fields == nil {
+		m.Fields = make(map[string]ProjectField)
+	}
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultMetadataTTL
+	}
+	if time.Since(m.CachedAt) > ttl {
+		return nil
+	}
+	return &m
+}
+
+// remove はキャッシュを捨てる（取得し直したメタデータで上書きされるまでの間も古い値を読まないように）
+func (c *MetadataCache) remove(host, owner string, number int) {
+	_ = os.Remove(c.path(host, owner, number))
+}
+
+// save はメタデータを保存する
+func (c *MetadataCache) save(host, owner string, number int, m *projectMetadata) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project metadata: %w", err)
+	}
+	if err := os.WriteFile(c.path(host, owner, number), data, 0600); err != nil {
+		return fmt.Errorf("failed to write project metadata: %w", err)
+	}
+	return nil
+}
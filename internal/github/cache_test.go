@@ -0,0 +1,206 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+func TestMetadataCacheLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		cache    MetadataCache
+		cachedAt time.Duration // 保存した時刻（現在からの経過時間）
+		data     string        // 空でなければメタデータの代わりに書き込む
+		want     bool
+	}{
+		{name: "within ttl", cache: MetadataCache{TTL: time.Minute}, cachedAt: 30 * time.Second, want: true},
+		{name: "expired", cache: MetadataCache{TTL: time.Minute}, cachedAt: 2 * time.Minute},
+		{name: "default ttl", cachedAt: DefaultMetadataTTL - time.Minute, want: true},
+		{name: "expired default ttl", cachedAt: DefaultMetadataTTL + time.Minute},
+		{name: "refresh skips the cache", cache: MetadataCache{Refresh: true}, cachedAt: time.Second},
+		{name: "invalid file", data: "{"},
+		{name: "no project id", data: `{"fields":{}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cache
+			c.Dir = t.TempDir()
+			m := &projectMetadata{
+				ProjectID: "P1",
+				Fields:    map[string]ProjectField{FieldStatus: {ID: "F1", Name: FieldStatus}},
+				CachedAt:  time.Now().Add(-tt.cachedAt),
+			}
+			if err := c.save("github.com", "acme", 3, m); err != nil {
+				t.Fatal(err)
+			}
+			if tt.data != "" {
+				if err := os.WriteFile(c.path("github.com", "acme", 3), []byte(tt.data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := c.load("github.com", "acme", 3)
+			if (got != nil) != tt.want {
+				t.Fatalf("load() = %+v, want cached: %v", got, tt.want)
+			}
+			if got != nil && (got.ProjectID != "P1" || got.Fields[FieldStatus].ID != "F1") {
+				t.Errorf("load() = %+v, want the saved metadata", got)
+			}
+		})
+	}
+
+	// ホスト・オーナー・番号ごとに別のファイルになる
+	c := MetadataCache{Dir: t.TempDir()}
+	if err := c.save("github.com", "acme", 3, &projectMetadata{ProjectID: "P1", CachedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if m := c.load("github.com", "acme", 4); m != nil {
+		t.Errorf("load() for another project = %+v, want nil", m)
+	}
+	if m := c.load("ghe.example.com", "acme", 3); m != nil {
+		t.Errorf("load() for another host = %+v, want nil", m)
+	}
+}
+
+// projectServer はProjectのメタデータの問い合わせに fields を返し、
+// 古いフィールドIDへのミューテーションを失敗させるGraphQLサーバーを起動する
+// 受け取ったミューテーションのフィールドIDを返す関数を返す
+func projectServer(t *testing.T, fields []map[string]any) (*Client, func() []string) {
+	t.Helper()
+	var mutations []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input struct {
+					FieldID string `json:"fieldId"`
+				} `json:"input"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		var resp map[string]any
+		switch {
+		case strings.Contains(req.Query, "user(login: $owner)"):
+			resp = map[string]any{"data": map[string]any{"user": map[string]any{
+				"projectV2": map[string]any{"id": "P1", "number": 3},
+			}}}
+		case strings.Contains(req.Query, "fields(first: 30)"):
+			resp = map[string]any{"data": map[string]any{"node": map[string]any{
+				"fields": map[string]any{"nodes": fields},
+			}}}
+		case strings.Contains(req.Query, "updateProjectV2ItemFieldValue"):
+			mutations = append(mutations, req.Variables.Input.FieldID)
+			if strings.HasPrefix(req.Variables.Input.FieldID, "old") {
+				resp = map[string]any{"data": nil, "errors": []map[string]any{{"message": "Could not resolve to a node"}}}
+			} else {
+				resp = map[string]any{"data": map[string]any{"updateProjectV2ItemFieldValue": map[string]any{
+					"projectV2Item": map[string]any{"id": "item"},
+				}}}
+			}
+		default:
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	client := &Client{gql: githubv4.NewEnterpriseClient(srv.URL, srv.Client()), host: "github.com", owner: "acme"}
+	return client, func() []string { return mutations }
+}
+
+func TestStaleMetadataRefresh(t *testing.T) {
+	// Project側のStatusフィールドは作り直されていて、IDが変わり Failed が追加されている
+	fresh := []map[string]any{{
+		"__typename": "ProjectV2SingleSelectField",
+		"id":         "new-status",
+		"name":       FieldStatus,
+		"dataType":   "SINGLE_SELECT",
+		"options": []map[string]any{
+			{"id": "new-ready", "name": "Ready"},
+			{"id": "new-failed", "name": "Failed"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		cached    map[string]ProjectField // nil ならキャッシュを使わない
+		status    domain.Status
+		mutations []string
+		wantErr   bool
+	}{
+		{
+			name:      "mutation fails with a stale field id",
+			cached:    map[string]ProjectField{FieldStatus: {ID: "old-status", Name: FieldStatus, Options: []FieldOption{{ID: "old-ready", Name: "Ready"}}}},
+			status:    domain.StatusReady,
+			mutations: []string{"old-status", "new-status"},
+		},
+		{
+			name:      "option missing from the cache",
+			cached:    map[string]ProjectField{FieldStatus: {ID: "old-status", Name: FieldStatus, Options: []FieldOption{{ID: "old-ready", Name: "Ready"}}}},
+			status:    domain.StatusFailed,
+			mutations: []string{"new-status"},
+		},
+		{
+			name:      "field missing from the cache",
+			cached:    map[string]ProjectField{},
+			status:    domain.StatusReady,
+			mutations: []string{"new-status"},
+		},
+		{
+			name:    "option missing after refreshing",
+			cached:  map[string]ProjectField{FieldStatus: {ID: "old-status", Name: FieldStatus}},
+			status:  domain.StatusInReview,
+			wantErr: true,
+		},
+		{
+			name:      "fresh metadata is not fetched again",
+			status:    domain.StatusReady,
+			mutations: []string{"new-status"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mutations := projectServer(t, fresh)
+			cache := &MetadataCache{Dir: t.TempDir()}
+			if tt.cached != nil {
+				if err := cache.save(client.host, client.owner, 3, &projectMetadata{ProjectID: "P1", Fields: tt.cached, CachedAt: time.Now()}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := NewTaskService(client, 3)
+			s.SetCache(cache)
+			ctx := context.Background()
+			if err := s.Initialize(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			err := s.SetTaskStatus(ctx, "item", tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetTaskStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := mutations(); strings.Join(got, ",") != strings.Join(tt.mutations, ",") {
+				t.Errorf("mutations = %q, want %q", got, tt.mutations)
+			}
+
+			// 取得し直したメタデータがキャッシュに保存されている
+			m := cache.load(client.host, client.owner, 3)
+			if m == nil || m.Fields[FieldStatus].ID != "new-status" {
+				t.Errorf("cached metadata = %+v, want the refreshed fields", m)
+			}
+		})
+	}
+}
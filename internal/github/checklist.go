@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// EditIssueBody はIssueの本文を取得し、edit が変更を返した場合のみ更新する
+// edit は最新の本文を受け取るため、取得から更新までの間の編集のみが失われうる
+func (c *Client) EditIssueBody(ctx context.Context, issueURL string, edit func(body string) (string, bool)) error {
+	parts := strings.Split(issueURL, "/")
+	if len(parts) < 7 {
+		return fmt.Errorf("invalid issue URL: %s", issueURL)
+	}
+	var number int
+	fmt.Sscanf(parts[6], "%d", &number)
+
+	var query struct {
+		Repository struct {
+			Issue struct {
+				ID   string
+				Body string
+			} `graphql:"issue(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(parts[3]),
+		"repo":   githubv4.String(parts[4]),
+		"number": githubv4.Int(number),
+	}
+	if err := c.gql.Query(ctx, &query, variables); err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	issue := query.Repository.Issue
+	body, changed := edit(issue.Body)
+	if !changed {
+		return nil
+	}
+
+	var mutation struct {
+		UpdateIssue struct {
+			Issue struct {
+				ID string
+			}
+		} `graphql:"updateIssue(input: $input)"`
+	}
+	input := githubv4.UpdateIssueInput{
+		ID:   githubv4.ID(issue.ID),
+		Body: githubv4.NewString(githubv4.String(body)),
+	}
+	if err := c.gql.Mutate(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+	return nil
+}
+
+// CheckIssueItems はClaude Codeが完了と報告した受け入れ基準をIssue本文でチェックし、チェックした数を返す
+func (s *TaskService) CheckIssueItems(ctx context.Context, task *domain.Task, items []domain.ChecklistItem) (int, error) {
+	if task.IssueURL == "" {
+		return 0, fmt.Errorf("task has no associated issue")
+	}
+	var texts []string
+	for _, item := range items {
+		if item.Done && !item.Checked {
+			texts = append(texts, item.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return 0, nil
+	}
+
+	checked := 0
+	err := s.client.EditIssueBody(ctx, task.IssueURL, func(body string) (string, bool) {
+		body, checked = domain.CheckItems(body, texts)
+		return body, checked > 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	return checked, nil
+}
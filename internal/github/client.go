@@ -2,28 +2,43 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
 	"golang.org/x/oauth2"
 )
 
 // Client はGitHub GraphQL APIクライアント
 type Client struct {
-	gql   *githubv4.Client
-	owner string
+	gql     *githubv4.Client
+	http    *http.Client
+	host    string // GitHubのホスト（github.com または GitHub Enterprise Server）
+	restURL string // REST APIのベースURL
+	owner   string
 }
 
 // NewClient は新しいClientを作成する
-func NewClient(token, owner string) *Client {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
+// src はPAT・OAuth・GitHub Appなど認証方式に応じたTokenSourceを渡す
+// host が空の場合は github.com に接続する
+func NewClient(src oauth2.TokenSource, host, owner string) *Client {
+	if host == "" {
+		host = config.DefaultHost
+	}
 	httpClient := oauth2.NewClient(context.Background(), src)
 	return &Client{
-		gql:   githubv4.NewClient(httpClient),
-		owner: owner,
+		gql:     githubv4.NewEnterpriseClient(config.GraphQLURL(host), httpClient),
+		http:    httpClient,
+		host:    host,
+		restURL: config.APIBaseURL(host),
+		owner:   owner,
 	}
 }
 
@@ -37,9 +52,10 @@ type Project struct {
 
 // ProjectField はProjectのカスタムフィールド
 type ProjectField struct {
-	ID      string
-	Name    string
-	Options []FieldOption // Single Select用
+	ID       string
+	Name     string
+	DataType string        // TEXT / NUMBER / DATE / SINGLE_SELECT など
+	Options  []FieldOption // Single Select用
 }
 
 // FieldOption はSingle Selectのオプション
@@ -142,21 +158,60 @@ func (c *Client) getOrgProjects(ctx context.Context) ([]Project, error) {
 }
 
 // GetProjectByNumber は指定番号のProjectを取得する
+// 一覧を走査せず、ユーザー・組織の projectV2(number:) で直接取得する
 func (c *Client) GetProjectByNumber(ctx context.Context, number int) (*Project, error) {
-	projects, err := c.GetProjects(ctx)
-	if err != nil {
-		return nil, err
+	type projectNode struct {
+		ID     string
+		Number int
+		Title  string
+		URL    string `graphql:"url"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(c.owner),
+		"number": githubv4.Int(number),
 	}
 
-	for _, p := range projects {
-		if p.Number == number {
-			return &p, nil
-		}
+	// まずユーザーのプロジェクトを試す
+	var userQuery struct {
+		User struct {
+			ProjectV2 *projectNode `graphql:"projectV2(number: $number)"`
+		} `graphql:"user(login: $owner)"`
+	}
+	userErr := c.gql.Query(ctx, &userQuery, variables)
+	if userErr == nil && userQuery.User.ProjectV2 != nil {
+		return (*Project)(userQuery.User.ProjectV2), nil
 	}
 
+	// ユーザーで見つからなければ組織のプロジェクトを試す
+	var orgQuery struct {
+		Organization struct {
+			ProjectV2 *projectNode `graphql:"projectV2(number: $number)"`
+		} `graphql:"organization(login: $owner)"`
+	}
+	orgErr := c.gql.Query(ctx, &orgQuery, variables)
+	if orgErr == nil && orgQuery.Organization.ProjectV2 != nil {
+		return (*Project)(orgQuery.Organization.ProjectV2), nil
+	}
+
+	if userErr != nil && orgErr != nil {
+		// 権限エラーの場合は明確なメッセージを返す
+		if strings.Contains(userErr.Error(), "not accessible by personal access token") {
+			return nil, fmt.Errorf("token lacks 'project' scope. Please regenerate your token with the 'project' permission at https://github.com/settings/tokens")
+		}
+		// owner がユーザー・組織の一方でしかない場合、もう一方は解決できないエラーになる
+		if !isNotFound(userErr) || !isNotFound(orgErr) {
+			return nil, fmt.Errorf("user: %v, org: %v", userErr, orgErr)
+		}
+	}
 	return nil, fmt.Errorf("project #%d not found", number)
 }
 
+// isNotFound はGraphQLのエラーが対象を解決できなかったことを示すかどうかを返す
+func isNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "Could not resolve to") || strings.Contains(msg, "NOT_FOUND")
+}
+
 // AddIssueComment はIssueにコメントを追加する
 func (c *Client) AddIssueComment(ctx context.Context, issueURL, body string) error {
 	// Issue URLからIssue IDを取得
@@ -224,9 +279,14 @@ func (c *Client) getIssueID(ctx context.Context, issueURL string) (string, error
 	return query.Repository.Issue.ID, nil
 }
 
-// GetIssueComments はIssueのコメント一覧を取得する
-func (c *Client) GetIssueComments(ctx context.Context, issueURL string) ([]string, error) {
-	// URLからowner, repo, numberを抽出
+// IssueThread はIssueの本文とコメント
+type IssueThread struct {
+	Issue    domain.Comment   // Issue本文
+	Comments []domain.Comment // コメント（投稿順）
+}
+
+// GetIssueThread はIssueの本文と最新100件のコメントを投稿順に取得する
+func (c *Client) GetIssueThread(ctx context.Context, issueURL string) (*IssueThread, error) {
 	parts := strings.Split(issueURL, "/")
 	if len(parts) < 7 {
 		return nil, fmt.Errorf("invalid issue URL: %s", issueURL)
@@ -239,18 +299,30 @@ func (c *Client) GetIssueComments(ctx context.Context, issueURL string) ([]strin
 	var number int
 	fmt.Sscanf(numberStr, "%d", &number)
 
+	type author struct {
+		Login string
+	}
 	var query struct {
 		Repository struct {
 			Issue struct {
-				BodyText string
-				Comments struct {
+				ID                string
+				Body              string
+				URL               string `graphql:"url"`
+				AuthorAssociation string
+				CreatedAt         time.Time
+				UpdatedAt         time.Time
+				Author            *author
+				Comments          struct {
 					Nodes []struct {
-						BodyText string
-						Author   struct {
-							Login string
-						}
+						ID                string
+						Body              string
+						URL               string `graphql:"url"`
+						AuthorAssociation string
+						CreatedAt         time.Time
+						UpdatedAt         time.Time
+						Author            *author
 					}
-				} `graphql:"comments(first: 100)"`
+				} `graphql:"comments(last: 100)"`
 			} `graphql:"issue(number: $number)"`
 		} `graphql:"repository(owner: $owner, name: $repo)"`
 	}
@@ -265,19 +337,219 @@ func (c *Client) GetIssueComments(ctx context.Context, issueURL string) ([]strin
 		return nil, err
 	}
 
-	// Issue本文 + 全コメントを結合（vibe project commentは除外）
-	var comments []string
-	if query.Repository.Issue.BodyText != "" {
-		comments = append(comments, query.Repository.Issue.BodyText)
+	// 削除されたユーザーの author は null になる
+	login := func(a *author) string {
+		if a == nil || a.Login == "" {
+			return "ghost"
+		}
+		return a.Login
+	}
+
+	issue := query.Repository.Issue
+	thread := &IssueThread{
+		Issue: domain.Comment{
+			ID:          issue.ID,
+			Author:      login(issue.Author),
+			Association: issue.AuthorAssociation,
+			Body:        issue.Body,
+			URL:         issue.URL,
+			CreatedAt:   issue.CreatedAt,
+			UpdatedAt:   issue.UpdatedAt,
+		},
+		Comments: make([]domain.Comment, 0, len(issue.Comments.Nodes)),
+	}
+	for _, n := range issue.Comments.Nodes {
+		thread.Comments = append(thread.Comments, domain.Comment{
+			ID:          n.ID,
+			Author:      login(n.Author),
+			Association: n.AuthorAssociation,
+			Body:        n.Body,
+			URL:         n.URL,
+			CreatedAt:   n.CreatedAt,
+			UpdatedAt:   n.UpdatedAt,
+		})
+	}
+	return thread, nil
+}
+
+// maxRelatedFiles は参照先のPRから取得する変更ファイルの上限
+const maxRelatedFiles = 50
+
+// GetRelated はIssue/PR URLからタイトル・本文・状態と、PRの場合は変更ファイルを取得する
+// URLが /issues/N でも /pull/N でも、番号に対応するIssueまたはPRを返す
+func (c *Client) GetRelated(ctx context.Context, issueURL string) (*domain.Related, error) {
+	parts := strings.Split(issueURL, "/")
+	if len(parts) < 7 {
+		return nil, fmt.Errorf("invalid issue URL: %s", issueURL)
+	}
+
+	owner := parts[3]
+	repo := parts[4]
+	numberStr := parts[6]
+
+	var number int
+	fmt.Sscanf(numberStr, "%d", &number)
+
+	var query struct {
+		Repository struct {
+			IssueOrPullRequest struct {
+				Issue struct {
+					URL   string `graphql:"url"`
+					Title string
+					Body  string
+					State string
+				} `graphql:"... on Issue"`
+				PullRequest struct {
+					URL   string `graphql:"url"`
+					Title string
+					Body  string
+					State string
+					Files struct {
+						TotalCount int
+						Nodes      []struct {
+							Path      string
+							Additions int
+							Deletions int
+						}
+					} `graphql:"files(first: $files)"`
+				} `graphql:"... on PullRequest"`
+			} `graphql:"issueOrPullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"repo":   githubv4.String(repo),
+		"number": githubv4.Int(number),
+		"files":  githubv4.Int(maxRelatedFiles),
+	}
+
+	if err := c.gql.Query(ctx, &query, variables); err != nil {
+		return nil, err
+	}
+
+	ref := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	item := query.Repository.IssueOrPullRequest
+	if pr := item.PullRequest; pr.URL != "" {
+		related := &domain.Related{
+			URL:           pr.URL,
+			Ref:           ref,
+			Title:         pr.Title,
+			Body:          pr.Body,
+			State:         pr.State,
+			IsPullRequest: true,
+			TotalFiles:    pr.Files.TotalCount,
+		}
+		for _, f := range pr.Files.Nodes {
+			related.Files = append(related.Files, domain.ChangedFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+		}
+		return related, nil
+	}
+	if item.Issue.URL == "" {
+		return nil, fmt.Errorf("issue or pull request not found: %s", ref)
 	}
-	for _, comment := range query.Repository.Issue.Comments.Nodes {
-		if comment.BodyText != "" {
-			// "vibe project comment" で始まるコメントは除外
-			if !strings.HasPrefix(comment.BodyText, "vibe project comment") {
-				comments = append(comments, comment.BodyText)
+	return &domain.Related{
+		URL:   item.Issue.URL,
+		Ref:   ref,
+		Title: item.Issue.Title,
+		Body:  item.Issue.Body,
+		State: item.Issue.State,
+	}, nil
+}
+
+// issueStateBatch は1回のクエリで状態を問い合わせるIssue/PRの数
+const issueStateBatch = 50
+
+// issueStateNode は resource の Issue / PR の状態
+type issueStateNode struct {
+	Issue struct {
+		State string
+	} `graphql:"... on Issue"`
+	PullRequest struct {
+		State string
+	} `graphql:"... on PullRequest"`
+}
+
+// GetIssueStates はIssue/PR URLの状態 (OPEN / CLOSED / MERGED) をまとめて取得する
+// URLごとに別名をつけた resource を1つのクエリに並べ、issueStateBatch 件ずつ問い合わせる
+// 見つからない・参照できないURLは結果に含めない
+func (c *Client) GetIssueStates(ctx context.Context, issueURLs []string) (map[string]string, error) {
+	states := make(map[string]string, len(issueURLs))
+	for start := 0; start < len(issueURLs); start += issueStateBatch {
+		batch := issueURLs[start:min(start+issueStateBatch, len(issueURLs))]
+
+		fields := make([]reflect.StructField, len(batch))
+		variables := make(map[string]interface{}, len(batch))
+		for i, issueURL := range batch {
+			u, err := url.Parse(issueURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid issue URL: %s", issueURL)
 			}
+			fields[i] = reflect.StructField{
+				Name: fmt.Sprintf("R%d", i),
+				Type: reflect.TypeOf(issueStateNode{}),
+				Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"r%d: resource(url: $url%d)"`, i, i)),
+			}
+			variables[fmt.Sprintf("url%d", i)] = githubv4.URI{URL: u}
+		}
+
+		query := reflect.New(reflect.StructOf(fields))
+		if err := c.gql.Query(ctx, query.Interface(), variables); err != nil {
+			return nil, err
 		}
+		for i, issueURL := range batch {
+			node := query.Elem().Field(i).Interface().(issueStateNode)
+			switch {
+			case node.Issue.State != "":
+				states[issueURL] = node.Issue.State
+			case node.PullRequest.State != "":
+				states[issueURL] = node.PullRequest.State
+			}
+		}
+	}
+	return states, nil
+}
+
+// TokenInfo はトークンの情報
+type TokenInfo struct {
+	Login   string   // トークンの所有者
+	Scopes  []string // Classic トークンのスコープ
+	Classic bool     // X-OAuth-Scopes ヘッダーが返された（Classic トークン）か
+}
+
+// GetTokenInfo はREST APIでトークンの所有者とスコープを取得する
+func (c *Client) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.restURL+"/user", nil)
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("Accept", "application/vnd.github+json")
 
-	return comments, nil
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %w", err)
+	}
+
+	info := &TokenInfo{Login: user.Login}
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.Classic = true
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+	}
+	return info, nil
 }
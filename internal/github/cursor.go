@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/prompt"
+)
+
+// cursorMarkerPattern はvibeのコメントに埋め込むプロンプトカーソルのマーカー
+// <!-- vibe-prompt-cursor: <comment-id> <RFC3339> -->（コメントがなければIDは "-"）
+var cursorMarkerPattern = regexp.MustCompile(`<!-- vibe-prompt-cursor: (\S+) (\S+) -->`)
+
+// deltaPrompt はセッションを再開するとき、前回の実行後に追加されたコメントだけを送るプロンプト
+const deltaPrompt = `New feedback was posted on the issue since your last run:
+
+%s
+
+Address this feedback and continue the task.`
+
+// CursorMarker はプロンプトカーソルを表す隠しマーカーを返す（cursor が nil なら空）
+func CursorMarker(cursor *domain.PromptCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	id := cursor.CommentID
+	if id == "" {
+		id = "-"
+	}
+	return fmt.Sprintf("<!-- vibe-prompt-cursor: %s %s -->", id, cursor.CreatedAt.UTC().Format(time.RFC3339))
+}
+
+// parseCursorMarker はコメント本文からプロンプトカーソルを読み取る（なければ nil）
+func parseCursorMarker(body string) *domain.PromptCursor {
+	m := cursorMarkerPattern.FindStringSubmatch(body)
+	if m == nil {
+		return nil
+	}
+	createdAt, err := time.Parse(time.RFC3339, m[2])
+	if err != nil {
+		return nil
+	}
+	cursor := &domain.PromptCursor{CreatedAt: createdAt}
+	if m[1] != "-" {
+		cursor.CommentID = m[1]
+	}
+	return cursor
+}
+
+// latestCursor はvibeのコメントに埋め込まれた最新のプロンプトカーソルを返す（なければ nil）
+func latestCursor(comments []domain.Comment) *domain.PromptCursor {
+	var latest *domain.PromptCursor
+	for _, c := range comments {
+		if !isVibeComment(c.Body) {
+			continue
+		}
+		if cursor := parseCursorMarker(c.Body); cursor.After(latest) {
+			latest = cursor
+		}
+	}
+	return latest
+}
+
+// commentsAfter はカーソルより後のユーザーのコメントを返す
+// カーソルのコメントが見つかればその後のコメント、見つからなければ（削除された・古すぎて取得範囲外など）
+// カーソルの日時より後に投稿されたコメントを返す
+func commentsAfter(comments []domain.Comment, cursor *domain.PromptCursor) []domain.Comment {
+	start := -1
+	if cursor.CommentID != "" {
+		for i, c := range comments {
+			if c.ID == cursor.CommentID {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	var after []domain.Comment
+	for i, c := range comments {
+		if start >= 0 && i < start || start < 0 && !c.CreatedAt.After(cursor.CreatedAt) {
+			continue
+		}
+		if strings.TrimSpace(c.Body) == "" || isVibeComment(c.Body) {
+			continue
+		}
+		after = append(after, c)
+	}
+	return after
+}
+
+// LoadDeltaPrompt は前回の実行でプロンプトに含めたコメントより後のコメントだけをプロンプトにする
+// カーソルはvibeのコメントの隠しマーカーと local（ローカルに記録したカーソル）のうち新しい方を使う
+// 新しいコメントがあれば task.Prompt・task.Resume・task.PromptCursor を設定して true を返す
+func (s *TaskService) LoadDeltaPrompt(ctx context.Context, task *domain.Task, local *domain.PromptCursor) (bool, error) {
+	if task.IssueURL == "" || task.SessionID == "" {
+		return false, nil
+	}
+
+	thread, err := s.client.GetIssueThread(ctx, task.IssueURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to get issue comments: %w", err)
+	}
+
+	cursor := latestCursor(thread.Comments)
+	if local.After(cursor) {
+		cursor = local
+	}
+	if cursor == nil {
+		return false, nil
+	}
+
+	comments := commentsAfter(thread.Comments, cursor)
+	if len(comments) == 0 {
+		return false, nil
+	}
+
+	parts := make([]string, 0, len(comments))
+	for _, c := range comments {
+		parts = append(parts, prompt.Comment("Comment", c))
+	}
+	task.Prompt = fmt.Sprintf(deltaPrompt, strings.Join(parts, prompt.Separator))
+	task.Resume = true
+	task.PromptCursor = commentCursor(comments[len(comments)-1])
+	task.Comments = comments
+	return true, nil
+}
+
+// commentCursor はコメントを指すプロンプトカーソルを返す
+func commentCursor(c domain.Comment) *domain.PromptCursor {
+	return &domain.PromptCursor{CommentID: c.ID, CreatedAt: c.CreatedAt}
+}
@@ -0,0 +1,255 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// at はテスト用の時刻（基準から min 分後）を返す
+func at(min int) time.Time {
+	return time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC).Add(time.Duration(min) * time.Minute)
+}
+
+// vibeComment はプロンプトカーソルのマーカーを埋め込んだvibeのコメントを作成する
+func vibeComment(id string, min int, cursor *domain.PromptCursor) domain.Comment {
+	return domain.Comment{ID: id, Body: "Done.\n" + VibeCommentMarker + "\n" + CursorMarker(cursor), CreatedAt: at(min)}
+}
+
+// commentIDs はコメントのIDを返す
+func commentIDs(comments []domain.Comment) []string {
+	var ids []string
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestCursorMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor *domain.PromptCursor
+	}{
+		{name: "comment", cursor: &domain.PromptCursor{CommentID: "IC_1", CreatedAt: at(1)}},
+		{name: "issue body only", cursor: &domain.PromptCursor{CreatedAt: at(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCursorMarker("text\n" + CursorMarker(tt.cursor))
+			if got == nil || got.CommentID != tt.cursor.CommentID || !got.CreatedAt.Equal(tt.cursor.CreatedAt) {
+				t.Errorf("parseCursorMarker() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+
+	if got := CursorMarker(nil); got != "" {
+		t.Errorf("CursorMarker(nil) = %q, want empty", got)
+	}
+	if got := parseCursorMarker("<!-- vibe-prompt-cursor: IC_1 yesterday -->"); got != nil {
+		t.Errorf("parseCursorMarker() with an invalid time = %+v, want nil", got)
+	}
+}
+
+func TestCommentsAfter(t *testing.T) {
+	comments := []domain.Comment{
+		{ID: "c1", Body: "first", CreatedAt: at(1)},
+		{ID: "c2", Body: "second", CreatedAt: at(2)},
+		{ID: "c3", Body: "same minute as c2", CreatedAt: at(2)},
+		vibeComment("v1", 3, &domain.PromptCursor{CommentID: "c3", CreatedAt: at(2)}),
+		{ID: "c4", Body: "  \n", CreatedAt: at(4)},
+		{ID: "c5", Body: "fifth", CreatedAt: at(5)},
+	}
+
+	tests := []struct {
+		name   string
+		cursor *domain.PromptCursor
+		want   []string
+	}{
+		{
+			name:   "comments after the cursor comment",
+			cursor: &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)},
+			want:   []string{"c2", "c3", "c5"},
+		},
+		{
+			name:   "equal timestamps after the cursor comment are kept",
+			cursor: &domain.PromptCursor{CommentID: "c2", CreatedAt: at(2)},
+			want:   []string{"c3", "c5"},
+		},
+		{
+			name:   "missing comment falls back to the time",
+			cursor: &domain.PromptCursor{CommentID: "deleted", CreatedAt: at(1)},
+			want:   []string{"c2", "c3", "c5"},
+		},
+		{
+			name:   "equal timestamps are covered by the time fallback",
+			cursor: &domain.PromptCursor{CommentID: "deleted", CreatedAt: at(2)},
+			want:   []string{"c5"},
+		},
+		{
+			name:   "issue body cursor uses the time",
+			cursor: &domain.PromptCursor{CreatedAt: at(0)},
+			want:   []string{"c1", "c2", "c3", "c5"},
+		},
+		{
+			name:   "last comment",
+			cursor: &domain.PromptCursor{CommentID: "c5", CreatedAt: at(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commentIDs(commentsAfter(comments, tt.cursor)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commentsAfter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// threadServer は issue の問い合わせに comments を返すGraphQLサーバーを起動する
+// 受け取ったクエリの数を返す関数を返す
+func threadServer(t *testing.T, comments []domain.Comment) (*Client, func() int) {
+	t.Helper()
+	var queries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		nodes := make([]map[string]any, 0, len(comments))
+		for _, c := range comments {
+			nodes = append(nodes, map[string]any{
+				"id":        c.ID,
+				"body":      c.Body,
+				"createdAt": c.CreatedAt,
+				"updatedAt": c.CreatedAt,
+				"author":    map[string]string{"login": "alice"},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"repository": map[string]any{
+			"issue": map[string]any{
+				"id":        "I_1",
+				"body":      "Implement the feature.",
+				"createdAt": at(0),
+				"updatedAt": at(0),
+				"comments":  map[string]any{"nodes": nodes},
+			},
+		}}})
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{gql: githubv4.NewEnterpriseClient(srv.URL, srv.Client())}, func() int { return queries }
+}
+
+func TestLoadDeltaPrompt(t *testing.T) {
+	tests := []struct {
+		name      string
+		comments  []domain.Comment
+		local     *domain.PromptCursor
+		noSession bool
+		want      []string // プロンプトに含まれるコメント（nil なら新しいコメントはない）
+		queries   int
+	}{
+		{
+			name: "marker is newer than the local cursor",
+			comments: []domain.Comment{
+				{ID: "c1", Body: "first", CreatedAt: at(1)},
+				vibeComment("v1", 2, &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)}),
+				{ID: "c2", Body: "second", CreatedAt: at(3)},
+			},
+			local:   &domain.PromptCursor{CreatedAt: at(0)},
+			want:    []string{"c2"},
+			queries: 1,
+		},
+		{
+			name: "local cursor is newer than the marker",
+			comments: []domain.Comment{
+				{ID: "c1", Body: "first", CreatedAt: at(1)},
+				vibeComment("v1", 2, &domain.PromptCursor{CreatedAt: at(0)}),
+				{ID: "c2", Body: "second", CreatedAt: at(3)},
+			},
+			local:   &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)},
+			want:    []string{"c2"},
+			queries: 1,
+		},
+		{
+			name: "marker wins when the times are equal",
+			comments: []domain.Comment{
+				{ID: "c1", Body: "first", CreatedAt: at(1)},
+				{ID: "c2", Body: "second", CreatedAt: at(1)},
+				vibeComment("v1", 2, &domain.PromptCursor{CommentID: "c2", CreatedAt: at(1)}),
+				{ID: "c3", Body: "third", CreatedAt: at(3)},
+			},
+			local:   &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)},
+			want:    []string{"c3"},
+			queries: 1,
+		},
+		{
+			name: "no comments after the cursor",
+			comments: []domain.Comment{
+				{ID: "c1", Body: "first", CreatedAt: at(1)},
+				vibeComment("v1", 2, &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)}),
+			},
+			queries: 1,
+		},
+		{
+			name: "only blank and vibe comments after the cursor",
+			comments: []domain.Comment{
+				{ID: "c1", Body: "first", CreatedAt: at(1)},
+				vibeComment("v1", 2, &domain.PromptCursor{CommentID: "c1", CreatedAt: at(1)}),
+				{ID: "c2", Body: " ", CreatedAt: at(3)},
+			},
+			queries: 1,
+		},
+		{
+			name:     "no cursor",
+			comments: []domain.Comment{{ID: "c1", Body: "first", CreatedAt: at(1)}},
+			queries:  1,
+		},
+		{
+			name:      "no session",
+			comments:  []domain.Comment{{ID: "c1", Body: "first", CreatedAt: at(1)}},
+			local:     &domain.PromptCursor{CreatedAt: at(0)},
+			noSession: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, queries := threadServer(t, tt.comments)
+			s := NewTaskService(client, 1)
+			task := &domain.Task{IssueURL: "https://github.com/o/r/issues/1", SessionID: "s1", Prompt: "original"}
+			if tt.noSession {
+				task.SessionID = ""
+			}
+
+			found, err := s.LoadDeltaPrompt(context.Background(), task, tt.local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := queries(); got != tt.queries {
+				t.Errorf("queries = %d, want %d", got, tt.queries)
+			}
+			if found != (tt.want != nil) {
+				t.Fatalf("LoadDeltaPrompt() = %v, want %v", found, tt.want != nil)
+			}
+			if !found {
+				// 呼び出し側が既定のプロンプトで再開できるよう、タスクは変更しない
+				if task.Prompt != "original" || task.Resume || task.PromptCursor != nil {
+					t.Errorf("task was changed without new comments: %+v", task)
+				}
+				return
+			}
+			if got := commentIDs(task.Comments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("comments = %q, want %q", got, tt.want)
+			}
+			last := task.Comments[len(task.Comments)-1]
+			if !task.Resume || task.PromptCursor == nil || task.PromptCursor.CommentID != last.ID {
+				t.Errorf("task = %+v, want resume with the cursor at %s", task, last.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// maxDownloadRedirects はダウンロードでたどるリダイレクトの上限
+const maxDownloadRedirects = 5
+
+// ErrTooLarge はダウンロードするファイルが上限を超えた場合のエラー
+var ErrTooLarge = errors.New("file is larger than the limit")
+
+// Download は rawURL のファイルを w に書き込み、サイズとContent-Typeを返す
+// limit バイトを超えるファイルは ErrTooLarge を返す（w には途中まで書き込まれる）
+// 認証はGitHubのホストへのリクエストにだけ付け、リダイレクト先（署名付きURLのストレージなど）には付けない
+func (c *Client) Download(ctx context.Context, rawURL string, w io.Writer, limit int64) (int64, string, error) {
+	plain := &http.Client{Transport: c.baseTransport(), CheckRedirect: noRedirect}
+	authed := &http.Client{Transport: c.http.Transport, CheckRedirect: noRedirect}
+
+	for i := 0; ; i++ {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid URL: %w", err)
+		}
+		if u.Scheme != "https" {
+			return 0, "", fmt.Errorf("refusing to download over %s: %s", u.Scheme, rawURL)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return 0, "", err
+		}
+		client := plain
+		if strings.EqualFold(u.Hostname(), c.host) {
+			client = authed
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+			resp.Body.Close()
+			location, err := resp.Location()
+			if err != nil {
+				return 0, "", fmt.Errorf("redirect without location: %s", resp.Status)
+			}
+			if i >= maxDownloadRedirects {
+				return 0, "", fmt.Errorf("too many redirects")
+			}
+			rawURL = location.String()
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, "", fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+		if resp.ContentLength > limit {
+			return 0, "", ErrTooLarge
+		}
+		n, err := io.Copy(w, io.LimitReader(resp.Body, limit+1))
+		if err != nil {
+			return n, "", err
+		}
+		if n > limit {
+			return n, "", ErrTooLarge
+		}
+		return n, resp.Header.Get("Content-Type"), nil
+	}
+}
+
+// baseTransport は認証を付けない通信に使うTransportを返す（認証用のTransportの下層）
+func (c *Client) baseTransport() http.RoundTripper {
+	if t, ok := c.http.Transport.(*oauth2.Transport); ok && t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// noRedirect はリダイレクトをたどらずにレスポンスを返す（リダイレクト先ごとに認証の有無を決めるため）
+func noRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
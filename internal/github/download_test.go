@@ -0,0 +1,190 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// downloadHosts は名前ごとのテスト用HTTPSサーバーと、受け取った Authorization ヘッダーの記録
+type downloadHosts struct {
+	mu      sync.Mutex
+	servers map[string]*httptest.Server
+	auth    map[string][]string // ホスト -> 受け取った Authorization
+}
+
+// newDownloadClient は handlers のホスト名で到達できるテスト用サーバーを起動し、
+// github.test をGitHubのホストとする Client を返す
+func newDownloadClient(t *testing.T, handlers map[string]http.HandlerFunc) (*Client, *downloadHosts) {
+	t.Helper()
+	hosts := &downloadHosts{servers: make(map[string]*httptest.Server), auth: make(map[string][]string)}
+	for name, h := range handlers {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hosts.mu.Lock()
+			hosts.auth[name] = append(hosts.auth[name], r.Header.Get("Authorization"))
+			hosts.mu.Unlock()
+			h(w, r)
+		}))
+		t.Cleanup(srv.Close)
+		hosts.servers[name] = srv
+	}
+
+	// ホスト名をテスト用サーバーのアドレスに振り向ける
+	dialer := &net.Dialer{}
+	base := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, _ := net.SplitHostPort(addr)
+			srv, ok := hosts.servers[host]
+			if !ok {
+				return nil, errors.New("unknown test host: " + host)
+			}
+			return dialer.DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}
+	t.Cleanup(base.CloseIdleConnections)
+
+	authed := &http.Client{Transport: &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"}),
+		Base:   base,
+	}}
+	return &Client{http: authed, host: "github.test"}, hosts
+}
+
+func TestDownload(t *testing.T) {
+	const limit = 16
+
+	tests := []struct {
+		name     string
+		url      string
+		handlers map[string]http.HandlerFunc
+		want     string
+		wantErr  string
+		wantAuth map[string][]string // ホスト -> 受け取った Authorization
+	}{
+		{
+			name: "GitHub host is authenticated",
+			url:  "https://github.test/user-attachments/files/1/log.txt",
+			handlers: map[string]http.HandlerFunc{
+				"github.test": func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("log")) },
+			},
+			want:     "log",
+			wantAuth: map[string][]string{"github.test": {"Bearer secret"}},
+		},
+		{
+			name: "redirect to another host drops the Authorization header",
+			url:  "https://github.test/user-attachments/assets/1",
+			handlers: map[string]http.HandlerFunc{
+				"github.test": func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "https://storage.test/signed?sig=abc", http.StatusFound)
+				},
+				"storage.test": func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("image")) },
+			},
+			want:     "image",
+			wantAuth: map[string][]string{"github.test": {"Bearer secret"}, "storage.test": {""}},
+		},
+		{
+			name:    "http URL is refused",
+			url:     "http://github.test/user-attachments/assets/1",
+			wantErr: "refusing to download over http",
+		},
+		{
+			name: "redirect to http is refused",
+			url:  "https://github.test/user-attachments/assets/1",
+			handlers: map[string]http.HandlerFunc{
+				"github.test": func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "http://storage.test/file", http.StatusFound)
+				},
+			},
+			wantErr: "refusing to download over http",
+		},
+		{
+			name: "redirects are capped",
+			url:  "https://storage.test/loop",
+			handlers: map[string]http.HandlerFunc{
+				"storage.test": func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "/loop", http.StatusFound)
+				},
+			},
+			wantErr:  "too many redirects",
+			wantAuth: map[string][]string{"storage.test": make([]string, maxDownloadRedirects+1)},
+		},
+		{
+			name: "Content-Length over the limit",
+			url:  "https://storage.test/big",
+			handlers: map[string]http.HandlerFunc{
+				"storage.test": func(w http.ResponseWriter, r *http.Request) {
+					w.Write(bytes.Repeat([]byte("x"), limit+1))
+				},
+			},
+			wantErr: ErrTooLarge.Error(),
+		},
+		{
+			name: "streamed body over the limit",
+			url:  "https://storage.test/stream",
+			handlers: map[string]http.HandlerFunc{
+				"storage.test": func(w http.ResponseWriter, r *http.Request) {
+					// Flush でContent-Lengthなしのチャンク転送にする
+					w.Write([]byte("x"))
+					w.(http.Flusher).Flush()
+					w.Write(bytes.Repeat([]byte("x"), limit))
+				},
+			},
+			wantErr: ErrTooLarge.Error(),
+		},
+		{
+			name: "exactly the limit",
+			url:  "https://storage.test/fits",
+			handlers: map[string]http.HandlerFunc{
+				"storage.test": func(w http.ResponseWriter, r *http.Request) {
+					w.Write(bytes.Repeat([]byte("x"), limit))
+				},
+			},
+			want: strings.Repeat("x", limit),
+		},
+		{
+			name: "error status",
+			url:  "https://storage.test/missing",
+			handlers: map[string]http.HandlerFunc{
+				"storage.test": http.NotFound,
+			},
+			wantErr: "unexpected status: 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, hosts := newDownloadClient(t, tt.handlers)
+			var buf bytes.Buffer
+			n, _, err := client.Download(context.Background(), tt.url, &buf, limit)
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Download() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Download() error = %v", err)
+			case buf.String() != tt.want || n != int64(len(tt.want)):
+				t.Errorf("Download() = %q (%d bytes), want %q", buf.String(), n, tt.want)
+			}
+
+			for host, want := range tt.wantAuth {
+				got := hosts.auth[host]
+				if !slices.Equal(got, want) {
+					t.Errorf("Authorization sent to %s = %q, want %q", host, got, want)
+				}
+			}
+		})
+	}
+}
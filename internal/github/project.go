@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// GetOwnerID はユーザー/組織のNode IDを取得する
+func (c *Client) GetOwnerID(ctx context.Context) (string, error) {
+	var query struct {
+		RepositoryOwner struct {
+			ID string
+		} `graphql:"repositoryOwner(login: $owner)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner": githubv4.String(c.owner),
+	}
+
+	if err := c.gql.Query(ctx, &query, variables); err != nil {
+		return "", err
+	}
+	if query.RepositoryOwner.ID == "" {
+		return "", fmt.Errorf("owner not found: %s", c.owner)
+	}
+	return query.RepositoryOwner.ID, nil
+}
+
+// CreateProject はユーザー/組織にProjectを作成する
+func (c *Client) CreateProject(ctx context.Context, title string) (*Project, error) {
+	ownerID, err := c.GetOwnerID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner ID: %w", err)
+	}
+
+	var mutation struct {
+		CreateProjectV2 struct {
+			ProjectV2 struct {
+				ID     string
+				Number int
+				Title  string
+				URL    string `graphql:"url"`
+			}
+		} `graphql:"createProjectV2(input: $input)"`
+	}
+
+	input := githubv4.CreateProjectV2Input{
+		OwnerID: githubv4.ID(ownerID),
+		Title:   githubv4.String(title),
+	}
+
+	if err := c.gql.Mutate(ctx, &mutation, input, nil); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	p := mutation.CreateProjectV2.ProjectV2
+	return &Project{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}, nil
+}
+
+// GetLinkedRepositories はProjectにリンクされたリポジトリ (owner/name) の一覧を取得する
+func (c *Client) GetLinkedRepositories(ctx context.Context, projectID string) ([]string, error) {
+	var query struct {
+		Node struct {
+			ProjectV2 struct {
+				Repositories struct {
+					Nodes []struct {
+						NameWithOwner string
+					}
+				} `graphql:"repositories(first: 100)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectId)"`
+	}
+
+	variables := map[string]interface{}{
+		"projectId": githubv4.ID(projectID),
+	}
+
+	if err := c.gql.Query(ctx, &query, variables); err != nil {
+		return nil, err
+	}
+
+	repos := make([]string, 0, len(query.Node.ProjectV2.Repositories.Nodes))
+	for _, r := range query.Node.ProjectV2.Repositories.Nodes {
+		repos = append(repos, r.NameWithOwner)
+	}
+	return repos, nil
+}
+
+// LinkRepository はリポジトリ (owner/name) をProjectにリンクする
+func (c *Client) LinkRepository(ctx context.Context, projectID, nameWithOwner string) error {
+	owner, name, ok := strings.Cut(nameWithOwner, "/")
+	if !ok {
+		return fmt.Errorf("invalid repository: %s (expected owner/name)", nameWithOwner)
+	}
+
+	var query struct {
+		Repository struct {
+			ID string
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+	}
+
+	if err := c.gql.Query(ctx, &query, variables); err != nil {
+		return fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	var mutation struct {
+		LinkProjectV2ToRepository struct {
+			ClientMutationID string
+		} `graphql:"linkProjectV2ToRepository(input: $input)"`
+	}
+
+	input := githubv4.LinkProjectV2ToRepositoryInput{
+		ProjectID:    githubv4.ID(projectID),
+		RepositoryID: githubv4.ID(query.Repository.ID),
+	}
+
+	if err := c.gql.Mutate(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to link repository: %w", err)
+	}
+	return nil
+}
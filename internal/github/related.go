@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// LoadRelated はタスクのプロンプトに含めたコメントで参照されているIssue/PRを取得する
+// 参照先の本文の参照を opts の深さまで幅優先でたどり、上限の件数で打ち切る
+// 取得できなかった参照は失敗の一覧に理由付きで返す
+func (s *TaskService) LoadRelated(ctx context.Context, task *domain.Task, opts config.RelatedConfig) ([]domain.Related, []string) {
+	seen := map[string]bool{task.IssueURL: true}
+	var level []string
+	for _, c := range task.Comments {
+		level = append(level, domain.ParseIssueRefs(c.Body, task.IssueURL)...)
+	}
+
+	var items []domain.Related
+	var failed []string
+	maxItems := opts.EffectiveMaxItems()
+	for depth := 0; depth < opts.EffectiveDepth() && len(level) > 0; depth++ {
+		var next []string
+		for _, url := range level {
+			if len(items) >= maxItems {
+				return items, failed
+			}
+			if seen[url] {
+				continue
+			}
+			seen[url] = true
+
+			item, err := s.client.GetRelated(ctx, url)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
+			// #N（/issues/N）とPRのURLなど、同じIssue/PRへの別の表記を除く
+			if seen[item.URL] && item.URL != url {
+				continue
+			}
+			seen[item.URL] = true
+			items = append(items, *item)
+			next = append(next, domain.ParseIssueRefs(item.Body, item.URL)...)
+		}
+		level = next
+	}
+	return items, failed
+}
+
+// RelatedSection は参照先のIssue/PRをプロンプトに追加するMarkdownで返す（参照先がなければ空）
+// 本文は maxChars 文字で切り詰める
+func RelatedSection(items []domain.Related, maxChars int) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("### Related context\n\n")
+	b.WriteString("Issues and pull requests referenced in the task, for background. Work on the task above, not on these.\n")
+	for _, item := range items {
+		kind := "issue"
+		if item.IsPullRequest {
+			kind = "pull request"
+		}
+		fmt.Fprintf(&b, "\n#### %s: %s (%s, %s)\n%s\n", item.Ref, item.Title, kind, strings.ToLower(item.State), item.URL)
+		if body := strings.TrimSpace(item.Body); body != "" {
+			b.WriteString("\n" + truncateChars(body, maxChars) + "\n")
+		}
+		if item.IsPullRequest && item.TotalFiles > 0 {
+			if len(item.Files) < item.TotalFiles {
+				fmt.Fprintf(&b, "\nChanged files (%d of %d):\n", len(item.Files), item.TotalFiles)
+			} else {
+				fmt.Fprintf(&b, "\nChanged files (%d):\n", item.TotalFiles)
+			}
+			for _, f := range item.Files {
+				fmt.Fprintf(&b, "- `%s` (+%d -%d)\n", f.Path, f.Additions, f.Deletions)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncateChars は s を最大 n 文字に切り詰める（切り詰めた場合は末尾に印を付ける）
+func truncateChars(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimRight(string(runes[:n]), " \n") + "\n\n… (truncated)"
+}
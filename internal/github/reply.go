@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/prompt"
+)
+
+// NeedsInputMarker はClaude Codeの質問を投稿したvibeのコメントを識別するマーカー
+const NeedsInputMarker = "<!-- vibe-needs-input -->"
+
+// replyPrompt は質問への返信でセッションを再開するときのプロンプト
+const replyPrompt = `You asked for input on this task. The following replies were posted on the issue:
+
+%s
+
+Continue the task with this information.`
+
+// LoadReplyPrompt はClaude Codeの質問に信頼するユーザーが返信していれば、返信のみをプロンプトにする
+// 最後のvibeのコメントが質問（NeedsInputMarker）で、その後に input.Trusts を満たす返信がある場合に
+// task.Prompt と task.Resume を設定して true を返す
+func (s *TaskService) LoadReplyPrompt(ctx context.Context, task *domain.Task, input config.InputConfig) (bool, error) {
+	if task.IssueURL == "" || task.SessionID == "" {
+		return false, nil
+	}
+
+	thread, err := s.client.GetIssueThread(ctx, task.IssueURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to get issue comments: %w", err)
+	}
+	comments := thread.Comments
+
+	// 最後のvibeのコメントより後のコメントが返信
+	last := -1
+	for i, c := range comments {
+		if isVibeComment(c.Body) {
+			last = i
+		}
+	}
+	if last < 0 || !strings.Contains(comments[last].Body, NeedsInputMarker) {
+		return false, nil
+	}
+
+	var replies []string
+	var included []domain.Comment
+	var cursor *domain.PromptCursor
+	for _, c := range comments[last+1:] {
+		if strings.TrimSpace(c.Body) == "" || isVibeComment(c.Body) || !input.Trusts(c.Author, c.Association) {
+			continue
+		}
+		replies = append(replies, prompt.Comment("Reply", c))
+		included = append(included, c)
+		cursor = commentCursor(c)
+	}
+	if len(replies) == 0 {
+		return false, nil
+	}
+
+	task.Prompt = fmt.Sprintf(replyPrompt, strings.Join(replies, prompt.Separator))
+	task.Resume = true
+	task.PromptCursor = cursor
+	task.Comments = included
+	return true, nil
+}
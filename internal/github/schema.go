@@ -0,0 +1,255 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// FieldSpec はvibeが使用するProjectフィールドの定義
+type FieldSpec struct {
+	Name      string
+	DataTypes []string // 許可される型（先頭が作成時の型）
+	Options   []string // Single Selectの必須オプション
+	Required  bool     // 必須フィールドか（falseなら任意機能用）
+}
+
+// DataType は作成時に使用する型を返す
+func (f FieldSpec) DataType() string {
+	return f.DataTypes[0]
+}
+
+// FieldSpecs はvibeが使用するフィールドの一覧
+// Status の Failed は SetFailureStatus で設定したオプション名に置き換えて照合する
+var FieldSpecs = []FieldSpec{
+	{
+		Name:      FieldStatus,
+		DataTypes: []string{"SINGLE_SELECT"},
+		Options:   []string{string(domain.StatusReady), string(domain.StatusInProgress), string(domain.StatusInReview), string(domain.StatusNeedsInput), string(domain.StatusFailed)},
+		Required:  true,
+	},
+	{Name: FieldResult, DataTypes: []string{"TEXT"}, Required: true},
+	{Name: FieldSessionID, DataTypes: []string{"TEXT"}, Required: true},
+	{Name: FieldExecutedAt, DataTypes: []string{"DATE"}, Required: true},
+	{Name: FieldPriority, DataTypes: []string{"NUMBER", "SINGLE_SELECT"}},
+	{Name: FieldCost, DataTypes: []string{"NUMBER"}},
+	{Name: FieldAttempts, DataTypes: []string{"NUMBER"}},
+}
+
+// SchemaProblem はProjectのフィールド構成の問題
+type SchemaProblem struct {
+	Spec           FieldSpec
+	Missing        bool     // フィールドが存在しない
+	WrongType      string   // 型が異なる場合の実際の型
+	MissingOptions []string // 不足しているSingle Selectオプション
+}
+
+// String は問題の説明を返す
+func (p SchemaProblem) String() string {
+	switch {
+	case p.Missing:
+		return fmt.Sprintf("field %q is missing (expected %s)", p.Spec.Name, p.Spec.DataType())
+	case p.WrongType != "":
+		return fmt.Sprintf("field %q has type %s (expected %s)", p.Spec.Name, p.WrongType, strings.Join(p.Spec.DataTypes, " or "))
+	default:
+		return fmt.Sprintf("field %q is missing options: %s", p.Spec.Name, strings.Join(p.MissingOptions, ", "))
+	}
+}
+
+// Fixable は自動で修正できる問題かどうかを返す（型の不一致は修正できない）
+func (p SchemaProblem) Fixable() bool {
+	return p.WrongType == ""
+}
+
+// CheckSchema はProjectのフィールドを FieldSpecs と照合する
+func (s *TaskService) CheckSchema() []SchemaProblem {
+	var problems []SchemaProblem
+	for _, spec := range s.fieldSpecs() {
+		field, ok := s.fields[spec.Name]
+		if !ok {
+			problems = append(problems, SchemaProblem{Spec: spec, Missing: true})
+			continue
+		}
+
+		if !containsString(spec.DataTypes, field.DataType) {
+			problems = append(problems, SchemaProblem{Spec: spec, WrongType: field.DataType})
+			continue
+		}
+
+		var missing []string
+		for _, name := range spec.Options {
+			if !hasOption(field, name) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, SchemaProblem{Spec: spec, MissingOptions: missing})
+		}
+	}
+	return problems
+}
+
+// fieldSpecs は Status のオプション名を置き換えた FieldSpecs を返す
+func (s *TaskService) fieldSpecs() []FieldSpec {
+	specs := make([]FieldSpec, len(FieldSpecs))
+	copy(specs, FieldSpecs)
+	for i, spec := range specs {
+		if spec.Name != FieldStatus {
+			continue
+		}
+		options := make([]string, len(spec.Options))
+		for j, o := range spec.Options {
+			options[j] = s.statusOption(domain.Status(o))
+		}
+		specs[i].Options = options
+	}
+	return specs
+}
+
+// FixSchema は自動で修正できる問題を修正し、フィールド情報を再読み込みする
+func (s *TaskService) FixSchema(ctx context.Context, problems []SchemaProblem) error {
+	for _, p := range problems {
+		switch {
+		case !p.Fixable():
+			continue
+		case p.Missing:
+			if err := s.CreateField(ctx, p.Spec.Name, p.Spec.DataType(), p.Spec.Options); err != nil {
+				return fmt.Errorf("failed to create field %s: %w", p.Spec.Name, err)
+			}
+		case len(p.MissingOptions) > 0:
+			if err := s.AddFieldOptions(ctx, p.Spec.Name, p.MissingOptions); err != nil {
+				return fmt.Errorf("failed to add options to %s: %w", p.Spec.Name, err)
+			}
+		}
+	}
+	fields, err := s.loadFields(ctx, s.ProjectID())
+	if err != nil {
+		return err
+	}
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	s.fields = fields
+	s.saveCache()
+	return nil
+}
+
+// CreateField はProjectにカスタムフィールドを作成する
+func (s *TaskService) CreateField(ctx context.Context, name, dataType string, options []string) error {
+	var mutation struct {
+		CreateProjectV2Field struct {
+			ClientMutationID string
+		} `graphql:"createProjectV2Field(input: $input)"`
+	}
+
+	input := githubv4.CreateProjectV2FieldInput{
+		ProjectID: githubv4.ID(s.projectID),
+		DataType:  githubv4.ProjectV2CustomFieldType(dataType),
+		Name:      githubv4.String(name),
+	}
+	if len(options) > 0 {
+		opts := make([]githubv4.ProjectV2SingleSelectFieldOptionInput, 0, len(options))
+		for _, o := range options {
+			opts = append(opts, githubv4.ProjectV2SingleSelectFieldOptionInput{
+				Name:        githubv4.String(o),
+				Color:       githubv4.ProjectV2SingleSelectFieldOptionColorGray,
+				Description: githubv4.String(""),
+			})
+		}
+		input.SingleSelectOptions = &opts
+	}
+
+	return s.client.gql.Mutate(ctx, &mutation, input, nil)
+}
+
+// UpdateProjectV2FieldInput は updateProjectV2Field の入力
+// githubv4 に未定義のため、GraphQLの型名と一致する名前で定義する
+type UpdateProjectV2FieldInput struct {
+	FieldID             githubv4.ID                              `json:"fieldId"`
+	SingleSelectOptions *[]ProjectV2SingleSelectFieldOptionInput `json:"singleSelectOptions,omitempty"`
+}
+
+// ProjectV2SingleSelectFieldOptionInput は既存オプションのIDを指定できるオプション入力
+// IDを指定したオプションは維持され、アイテムの値も失われない
+type ProjectV2SingleSelectFieldOptionInput struct {
+	ID          *githubv4.ID                                   `json:"id,omitempty"`
+	Name        githubv4.String                                `json:"name"`
+	Color       githubv4.ProjectV2SingleSelectFieldOptionColor `json:"color"`
+	Description githubv4.String                                `json:"description"`
+}
+
+// AddFieldOptions はSingle Selectフィールドにオプションを追加する
+func (s *TaskService) AddFieldOptions(ctx context.Context, fieldName string, names []string) error {
+	return s.withFreshMetadata(ctx, func() error {
+		_, field, err := s.lookupField(fieldName)
+		if err != nil {
+			return err
+		}
+
+		// 既存オプションをIDつきで維持したまま末尾に追加する
+		var query struct {
+			Node struct {
+				SingleSelect struct {
+					Options []struct {
+						ID          string
+						Name        string
+						Color       githubv4.ProjectV2SingleSelectFieldOptionColor
+						Description string
+					}
+				} `graphql:"... on ProjectV2SingleSelectField"`
+			} `graphql:"node(id: $fieldId)"`
+		}
+		if err := s.client.gql.Query(ctx, &query, map[string]interface{}{"fieldId": githubv4.ID(field.ID)}); err != nil {
+			return err
+		}
+
+		opts := make([]ProjectV2SingleSelectFieldOptionInput, 0, len(query.Node.SingleSelect.Options)+len(names))
+		for _, o := range query.Node.SingleSelect.Options {
+			id := githubv4.ID(o.ID)
+			opts = append(opts, ProjectV2SingleSelectFieldOptionInput{
+				ID:          &id,
+				Name:        githubv4.String(o.Name),
+				Color:       o.Color,
+				Description: githubv4.String(o.Description),
+			})
+		}
+		for _, name := range names {
+			opts = append(opts, ProjectV2SingleSelectFieldOptionInput{
+				Name:        githubv4.String(name),
+				Color:       githubv4.ProjectV2SingleSelectFieldOptionColorGray,
+				Description: githubv4.String(""),
+			})
+		}
+
+		var mutation struct {
+			UpdateProjectV2Field struct {
+				ClientMutationID string
+			} `graphql:"updateProjectV2Field(input: $input)"`
+		}
+		input := UpdateProjectV2FieldInput{
+			FieldID:             githubv4.ID(field.ID),
+			SingleSelectOptions: &opts,
+		}
+		return s.client.gql.Mutate(ctx, &mutation, input, nil)
+	})
+}
+
+func hasOption(field ProjectField, name string) bool {
+	for _, o := range field.Options {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
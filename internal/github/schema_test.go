@@ -0,0 +1,78 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// statusService は指定したStatusのオプションを持つTaskServiceを作成する
+func statusService(failureStatus string, options ...string) *TaskService {
+	s := NewTaskService(nil, 1)
+	s.SetFailureStatus(failureStatus)
+	status := ProjectField{Name: FieldStatus, DataType: "SINGLE_SELECT"}
+	for _, o := range options {
+		status.Options = append(status.Options, FieldOption{Name: o})
+	}
+	s.fields[FieldStatus] = status
+	for _, spec := range FieldSpecs[1:] {
+		s.fields[spec.Name] = ProjectField{Name: spec.Name, DataType: spec.DataType()}
+	}
+	return s
+}
+
+func TestCheckSchemaFailureStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		failureStatus string
+		options       []string
+		want          []string // 不足しているStatusのオプション
+	}{
+		{
+			name:    "failed option is required",
+			options: []string{"Ready", "In progress", "In review", "Needs input"},
+			want:    []string{"Failed"},
+		},
+		{
+			name:          "configured name replaces Failed",
+			failureStatus: "Errored",
+			options:       []string{"Ready", "In progress", "In review", "Needs input", "Failed"},
+			want:          []string{"Errored"},
+		},
+		{
+			name:          "all options exist",
+			failureStatus: "Errored",
+			options:       []string{"Ready", "In progress", "In review", "Needs input", "Errored"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range statusService(tt.failureStatus, tt.options...).CheckSchema() {
+				if p.Spec.Name != FieldStatus {
+					t.Errorf("unexpected problem: %s", p)
+					continue
+				}
+				got = append(got, p.MissingOptions...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missing Status options = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureStatusRoundTrip(t *testing.T) {
+	s := statusService("Errored")
+	if got := s.statusOption(domain.StatusFailed); got != "Errored" {
+		t.Errorf("statusOption(Failed) = %q, want %q", got, "Errored")
+	}
+	if got := s.ParseStatus("Errored"); got != domain.StatusFailed {
+		t.Errorf("ParseStatus(Errored) = %q, want %q", got, domain.StatusFailed)
+	}
+	if got := s.ParseStatus("In review"); got != domain.StatusInReview {
+		t.Errorf("ParseStatus(In review) = %q, want %q", got, domain.StatusInReview)
+	}
+}
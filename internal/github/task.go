@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 	"github.com/tkc/vibe-project/internal/domain"
+	"github.com/tkc/vibe-project/internal/prompt"
 )
 
 // フィールド名定数
@@ -17,6 +19,9 @@ const (
 	FieldResult     = "Result"
 	FieldSessionID  = "SessionID"
 	FieldExecutedAt = "ExecutedAt"
+	FieldPriority   = "Priority"
+	FieldCost       = "Cost"
+	FieldAttempts   = "Attempts"
 )
 
 // TaskService はタスク操作を提供する
@@ -25,6 +30,16 @@ type TaskService struct {
 	projectID     string
 	projectNumber int
 	fields        map[string]ProjectField // フィールド名 -> フィールド情報
+	assembler     *prompt.Assembler       // Issueのスレッドからプロンプトを組み立てる（nil なら全てのコメントをそのまま使う）
+	cache         *MetadataCache          // Project IDとフィールドの定義のキャッシュ（nil なら毎回取得する）
+	failureStatus string                  // 失敗した実行を移すStatusのオプション名（空なら "Failed"）
+
+	depMu     sync.Mutex
+	depStates map[string]string // 依存先のIssue/PR URL -> 状態（見つからなければ空）
+
+	metaMu    sync.RWMutex // projectID と fields を守る（実行中に取得し直すことがある）
+	metaGen   int          // メタデータを取得し直した回数
+	fromCache bool         // メタデータをキャッシュから読んだか（古くなっている可能性がある）
 }
 
 // NewTaskService は新しいTaskServiceを作成する
@@ -36,24 +51,148 @@ func NewTaskService(client *Client, projectNumber int) *TaskService {
 	}
 }
 
+// SetCache はProjectのメタデータのキャッシュを設定する
+func (s *TaskService) SetCache(c *MetadataCache) {
+	s.cache = c
+}
+
+// SetFailureStatus は失敗した実行を移すStatusのオプション名を設定する
+func (s *TaskService) SetFailureStatus(name string) {
+	s.failureStatus = name
+}
+
+// statusOption はStatusに対応するオプション名を返す
+func (s *TaskService) statusOption(status domain.Status) string {
+	if status == domain.StatusFailed && s.failureStatus != "" {
+		return s.failureStatus
+	}
+	return string(status)
+}
+
+// ParseStatus はStatusのオプション名をStatusに変換する（statusOption の逆）
+func (s *TaskService) ParseStatus(name string) domain.Status {
+	if s.failureStatus != "" && name == s.failureStatus {
+		return domain.StatusFailed
+	}
+	return domain.Status(name)
+}
+
 // Initialize はProjectの情報を取得してサービスを初期化する
+// キャッシュが有効期間内であれば、Project IDとフィールドの定義を問い合わせずに使う
 func (s *TaskService) Initialize(ctx context.Context) error {
+	if s.cache != nil {
+		if m := s.cache.load(s.client.host, s.client.owner, s.projectNumber); m != nil {
+			s.metaMu.Lock()
+			s.projectID = m.ProjectID
+			s.fields = m.Fields
+			s.fromCache = true
+			s.metaMu.Unlock()
+			return nil
+		}
+	}
+
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	return s.fetchMetadata(ctx)
+}
+
+// fetchMetadata はProject IDとフィールドの定義を問い合わせてキャッシュに保存する（metaMu を持って呼ぶ）
+func (s *TaskService) fetchMetadata(ctx context.Context) error {
 	// Project IDを取得
 	project, err := s.client.GetProjectByNumber(ctx, s.projectNumber)
 	if err != nil {
 		return fmt.Errorf("failed to get project: %w", err)
 	}
-	s.projectID = project.ID
 
 	// フィールド情報を取得
-	if err := s.loadFields(ctx); err != nil {
+	fields, err := s.loadFields(ctx, project.ID)
+	if err != nil {
 		return fmt.Errorf("failed to load fields: %w", err)
 	}
+	s.projectID = project.ID
+	s.fields = fields
+	s.fromCache = false
 
+	// キャッシュに書けなくても問い合わせた値で続ける
+	s.saveCache()
 	return nil
 }
 
-func (s *TaskService) loadFields(ctx context.Context) error {
+// refreshMetadata はキャッシュを捨ててメタデータを取得し直し、やり直す価値があるかを返す
+// gen は失敗した操作を始めたときの metaGen。その後に他の操作が取得し直していればそのままやり直す。
+// キャッシュから読んでいないメタデータは新しいので取得し直さない
+func (s *TaskService) refreshMetadata(ctx context.Context, gen int) (bool, error) {
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	if s.metaGen != gen {
+		return true, nil
+	}
+	if !s.fromCache {
+		return false, nil
+	}
+	s.cache.remove(s.client.host, s.client.owner, s.projectNumber)
+	if err := s.fetchMetadata(ctx); err != nil {
+		return false, err
+	}
+	s.metaGen++
+	return true, nil
+}
+
+// withFreshMetadata はフィールドを更新する fn を実行し、失敗したらメタデータを取得し直して一度だけやり直す
+// キャッシュしたフィールドやオプションのIDは、Project側で作り直されると古くなる
+// （"field not found" / "option not found" やミューテーションの失敗になる）
+func (s *TaskService) withFreshMetadata(ctx context.Context, fn func() error) error {
+	s.metaMu.RLock()
+	gen := s.metaGen
+	s.metaMu.RUnlock()
+
+	err := fn()
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	retry, rerr := s.refreshMetadata(ctx, gen)
+	if rerr != nil {
+		return fmt.Errorf("%w (failed to refresh project metadata: %v)", err, rerr)
+	}
+	if !retry {
+		return err
+	}
+	return fn()
+}
+
+// lookupField はProject IDとフィールドの定義を返す
+func (s *TaskService) lookupField(fieldName string) (string, ProjectField, error) {
+	s.metaMu.RLock()
+	defer s.metaMu.RUnlock()
+	field, ok := s.fields[fieldName]
+	if !ok {
+		return "", ProjectField{}, fmt.Errorf("field not found: %s", fieldName)
+	}
+	return s.projectID, field, nil
+}
+
+// hasField はフィールドがProjectにあるかを返す
+func (s *TaskService) hasField(fieldName string) bool {
+	s.metaMu.RLock()
+	defer s.metaMu.RUnlock()
+	_, ok := s.fields[fieldName]
+	return ok
+}
+
+// saveCache は現在のProject IDとフィールドの定義をキャッシュに保存する
+func (s *TaskService) saveCache() {
+	if s.cache == nil {
+		return
+	}
+	_ = s.cache.save(s.client.host, s.client.owner, s.projectNumber, &projectMetadata{
+		ProjectID: s.projectID,
+		Fields:    s.fields,
+		CachedAt:  time.Now(),
+	})
+}
+
+// loadFields はProjectのフィールドの定義を問い合わせる
+func (s *TaskService) loadFields(ctx context.Context, projectID string) (map[string]ProjectField, error) {
 	var query struct {
 		Node struct {
 			ProjectV2 struct {
@@ -61,8 +200,9 @@ func (s *TaskService) loadFields(ctx context.Context) error {
 					Nodes []struct {
 						TypeName    string `graphql:"__typename"`
 						FieldCommon struct {
-							ID   string
-							Name string
+							ID       string
+							Name     string
+							DataType string
 						} `graphql:"... on ProjectV2FieldCommon"`
 						SingleSelect struct {
 							Options []struct {
@@ -77,17 +217,19 @@ func (s *TaskService) loadFields(ctx context.Context) error {
 	}
 
 	variables := map[string]interface{}{
-		"projectId": githubv4.ID(s.projectID),
+		"projectId": githubv4.ID(projectID),
 	}
 
 	if err := s.client.gql.Query(ctx, &query, variables); err != nil {
-		return err
+		return nil, err
 	}
 
+	fields := make(map[string]ProjectField)
 	for _, f := range query.Node.ProjectV2.Fields.Nodes {
 		field := ProjectField{
-			ID:   f.FieldCommon.ID,
-			Name: f.FieldCommon.Name,
+			ID:       f.FieldCommon.ID,
+			Name:     f.FieldCommon.Name,
+			DataType: f.FieldCommon.DataType,
 		}
 		if f.TypeName == "ProjectV2SingleSelectField" {
 			for _, opt := range f.SingleSelect.Options {
@@ -97,10 +239,81 @@ func (s *TaskService) loadFields(ctx context.Context) error {
 				})
 			}
 		}
-		s.fields[f.FieldCommon.Name] = field
+		fields[f.FieldCommon.Name] = field
 	}
 
-	return nil
+	return fields, nil
+}
+
+// projectItem はProjectのアイテム（タスク）として取得する値
+type projectItem struct {
+	ID      string
+	Content struct {
+		Issue struct {
+			Title  string
+			URL    string
+			Body   string
+			State  string
+			Author *struct {
+				Login string
+			}
+			AuthorAssociation string
+			Repository        struct {
+				NameWithOwner string
+			}
+			Labels struct {
+				Nodes []struct {
+					Name string
+				}
+			} `graphql:"labels(first: 20)"`
+			TrackedIssues struct {
+				Nodes []struct {
+					URL   string
+					State string
+				}
+			} `graphql:"trackedIssues(first: 20)"`
+		} `graphql:"... on Issue"`
+		DraftIssue struct {
+			Title string
+		} `graphql:"... on DraftIssue"`
+	}
+	FieldValues struct {
+		Nodes []struct {
+			TypeName  string `graphql:"__typename"`
+			TextField struct {
+				Text  string
+				Field struct {
+					FieldCommon struct {
+						Name string
+					} `graphql:"... on ProjectV2FieldCommon"`
+				} `graphql:"field"`
+			} `graphql:"... on ProjectV2ItemFieldTextValue"`
+			SingleSelect struct {
+				Name  string
+				Field struct {
+					FieldCommon struct {
+						Name string
+					} `graphql:"... on ProjectV2FieldCommon"`
+				} `graphql:"field"`
+			} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+			DateField struct {
+				Date  string
+				Field struct {
+					FieldCommon struct {
+						Name string
+					} `graphql:"... on ProjectV2FieldCommon"`
+				} `graphql:"field"`
+			} `graphql:"... on ProjectV2ItemFieldDateValue"`
+			NumberField struct {
+				Number float64
+				Field  struct {
+					FieldCommon struct {
+						Name string
+					} `graphql:"... on ProjectV2FieldCommon"`
+				} `graphql:"field"`
+			} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+		}
+	} `graphql:"fieldValues(first: 20)"`
 }
 
 // GetTasks はProjectのタスク一覧を取得する
@@ -109,47 +322,7 @@ func (s *TaskService) GetTasks(ctx context.Context, filter *domain.TaskFilter) (
 		Node struct {
 			ProjectV2 struct {
 				Items struct {
-					Nodes []struct {
-						ID      string
-						Content struct {
-							Issue struct {
-								Title string
-								URL   string
-							} `graphql:"... on Issue"`
-							DraftIssue struct {
-								Title string
-							} `graphql:"... on DraftIssue"`
-						}
-						FieldValues struct {
-							Nodes []struct {
-								TypeName  string `graphql:"__typename"`
-								TextField struct {
-									Text  string
-									Field struct {
-										FieldCommon struct {
-											Name string
-										} `graphql:"... on ProjectV2FieldCommon"`
-									} `graphql:"field"`
-								} `graphql:"... on ProjectV2ItemFieldTextValue"`
-								SingleSelect struct {
-									Name  string
-									Field struct {
-										FieldCommon struct {
-											Name string
-										} `graphql:"... on ProjectV2FieldCommon"`
-									} `graphql:"field"`
-								} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
-								DateField struct {
-									Date  string
-									Field struct {
-										FieldCommon struct {
-											Name string
-										} `graphql:"... on ProjectV2FieldCommon"`
-									} `graphql:"field"`
-								} `graphql:"... on ProjectV2ItemFieldDateValue"`
-							}
-						} `graphql:"fieldValues(first: 20)"`
-					}
+					Nodes []projectItem
 				} `graphql:"items(first: 100)"`
 			} `graphql:"... on ProjectV2"`
 		} `graphql:"node(id: $projectId)"`
@@ -163,82 +336,233 @@ func (s *TaskService) GetTasks(ctx context.Context, filter *domain.TaskFilter) (
 		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
 
-	tasks := make([]*domain.Task, 0)
-	for _, item := range query.Node.ProjectV2.Items.Nodes {
-		task := &domain.Task{
-			ID: item.ID,
+	tasks := make([]*domain.Task, 0, len(query.Node.ProjectV2.Items.Nodes))
+	for i := range query.Node.ProjectV2.Items.Nodes {
+		tasks = append(tasks, s.newTask(&query.Node.ProjectV2.Items.Nodes[i]))
+	}
+
+	// 依存先の完了状態を解決
+	if err := s.resolveDependencies(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	// フィルタ適用
+	if filter != nil {
+		filtered := make([]*domain.Task, 0, len(tasks))
+		for _, t := range tasks {
+			if filter.Matches(t) {
+				filtered = append(filtered, t)
+			}
+		}
+		tasks = filtered
+		if filter.Limit > 0 && len(tasks) > filter.Limit {
+			tasks = tasks[:filter.Limit]
+		}
+	}
+
+	return tasks, nil
+}
+
+// newTask はProjectのアイテムをタスクに変換する（依存先の完了状態は未解決）
+func (s *TaskService) newTask(item *projectItem) *domain.Task {
+	task := &domain.Task{
+		ID: item.ID,
+	}
+
+	// タイトルを取得
+	if item.Content.Issue.Title != "" {
+		task.Title = item.Content.Issue.Title
+		task.IssueURL = item.Content.Issue.URL
+		task.IssueState = item.Content.Issue.State
+		task.IssueBody = item.Content.Issue.Body
+		task.IssueAssociation = item.Content.Issue.AuthorAssociation
+		if item.Content.Issue.Author != nil {
+			task.IssueAuthor = item.Content.Issue.Author.Login
+		}
+		task.Repository = item.Content.Issue.Repository.NameWithOwner
+		for _, label := range item.Content.Issue.Labels.Nodes {
+			task.Labels = append(task.Labels, label.Name)
+		}
+		task.Dependencies = domain.ParseDependencies(task.IssueBody, task.IssueURL)
+		// 追跡している子Issue（tracks）も依存先として扱う（親は子の完了を待つ）
+		// 子から親への依存にすると、親のタスクリストの依存と合わせて循環になる
+		for _, tracked := range item.Content.Issue.TrackedIssues.Nodes {
+			task.Dependencies = appendDependency(task.Dependencies, domain.Dependency{
+				URL:  tracked.URL,
+				Done: tracked.State == domain.IssueStateClosed,
+			})
+		}
+	} else {
+		task.Title = item.Content.DraftIssue.Title
+	}
+
+	// フィールド値を取得
+	for _, fv := range item.FieldValues.Nodes {
+		var fieldName string
+		switch fv.TypeName {
+		case "ProjectV2ItemFieldTextValue":
+			fieldName = fv.TextField.Field.FieldCommon.Name
+		case "ProjectV2ItemFieldSingleSelectValue":
+			fieldName = fv.SingleSelect.Field.FieldCommon.Name
+		case "ProjectV2ItemFieldDateValue":
+			fieldName = fv.DateField.Field.FieldCommon.Name
+		case "ProjectV2ItemFieldNumberValue":
+			fieldName = fv.NumberField.Field.FieldCommon.Name
 		}
 
-		// タイトルを取得
-		if item.Content.Issue.Title != "" {
-			task.Title = item.Content.Issue.Title
-			task.IssueURL = item.Content.Issue.URL
-		} else {
-			task.Title = item.Content.DraftIssue.Title
+		switch fieldName {
+		case FieldStatus:
+			task.Status = s.ParseStatus(fv.SingleSelect.Name)
+		case FieldPrompt:
+			task.Prompt = fv.TextField.Text
+		case FieldResult:
+			task.Result = fv.TextField.Text
+		case FieldSessionID:
+			task.SessionID = fv.TextField.Text
+		case FieldExecutedAt:
+			if fv.DateField.Date != "" {
+				t, _ := time.Parse("2006-01-02", fv.DateField.Date)
+				task.ExecutedAt = &t
+			}
+		case FieldCost:
+			task.Cost = fv.NumberField.Number
+		case FieldAttempts:
+			task.Attempts = int(fv.NumberField.Number)
+		case FieldPriority:
+			task.Priority = s.priorityValue(fv.TypeName, fv.NumberField.Number, fv.SingleSelect.Name)
 		}
+	}
 
-		// フィールド値を取得
-		for _, fv := range item.FieldValues.Nodes {
-			var fieldName string
-			switch fv.TypeName {
-			case "ProjectV2ItemFieldTextValue":
-				fieldName = fv.TextField.Field.FieldCommon.Name
-			case "ProjectV2ItemFieldSingleSelectValue":
-				fieldName = fv.SingleSelect.Field.FieldCommon.Name
-			case "ProjectV2ItemFieldDateValue":
-				fieldName = fv.DateField.Field.FieldCommon.Name
+	return task
+}
+
+// priorityValue はPriorityフィールドの値を数値に変換する
+// Number型はその値を、Single Select型は選択肢の並び順（先頭ほど高優先度）を使用する
+func (s *TaskService) priorityValue(typeName string, number float64, optionName string) *float64 {
+	switch typeName {
+	case "ProjectV2ItemFieldNumberValue":
+		return &number
+	case "ProjectV2ItemFieldSingleSelectValue":
+		s.metaMu.RLock()
+		defer s.metaMu.RUnlock()
+		for i, opt := range s.fields[FieldPriority].Options {
+			if opt.Name == optionName {
+				v := float64(i)
+				return &v
 			}
+		}
+	}
+	return nil
+}
 
-			switch fieldName {
-			case FieldStatus:
-				task.Status = domain.Status(fv.SingleSelect.Name)
-			case FieldPrompt:
-				task.Prompt = fv.TextField.Text
-			case FieldResult:
-				task.Result = fv.TextField.Text
-			case FieldSessionID:
-				task.SessionID = fv.TextField.Text
-			case FieldExecutedAt:
-				if fv.DateField.Date != "" {
-					t, _ := time.Parse("2006-01-02", fv.DateField.Date)
-					task.ExecutedAt = &t
-				}
+// resolveDependencies は依存先の完了状態を解決する
+// Project上のタスクはそのIssue状態を使い、それ以外はまとめてAPIで問い合わせる。
+// 状態は ResetDependencyCache まで使い回す（vibe watch はポーリングごとに捨てる）
+func (s *TaskService) resolveDependencies(ctx context.Context, tasks []*domain.Task) error {
+	s.depMu.Lock()
+	defer s.depMu.Unlock()
+
+	if s.depStates == nil {
+		s.depStates = make(map[string]string)
+	}
+	for _, t := range tasks {
+		if t.IssueURL != "" {
+			s.depStates[t.IssueURL] = t.IssueState
+		}
+	}
+
+	var lookup []string
+	for _, t := range tasks {
+		for _, d := range t.Dependencies {
+			if _, ok := s.depStates[d.URL]; !ok && !d.Done && !containsString(lookup, d.URL) {
+				lookup = append(lookup, d.URL)
 			}
 		}
+	}
+	if len(lookup) > 0 {
+		states, err := s.client.GetIssueStates(ctx, lookup)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency states: %w", err)
+		}
+		for _, u := range lookup {
+			// 見つからない依存先は空の状態として記録し、問い合わせを繰り返さない
+			s.depStates[u] = states[u]
+		}
+	}
 
-		// フィルタ適用
-		if filter != nil && filter.Status != nil {
-			if task.Status != *filter.Status {
+	for _, t := range tasks {
+		for i, d := range t.Dependencies {
+			if d.Done {
 				continue
 			}
+			state := s.depStates[d.URL]
+			t.Dependencies[i].Done = state == domain.IssueStateClosed || state == "MERGED"
+			t.Dependencies[i].Unresolved = state == ""
 		}
-
-		tasks = append(tasks, task)
 	}
+	return nil
+}
 
-	return tasks, nil
+// ResetDependencyCache は依存先のIssue状態のキャッシュを捨てる
+func (s *TaskService) ResetDependencyCache() {
+	s.depMu.Lock()
+	defer s.depMu.Unlock()
+	s.depStates = nil
+}
+
+// appendDependency は重複を除いて依存先を追加する
+func appendDependency(deps []domain.Dependency, dep domain.Dependency) []domain.Dependency {
+	for i, d := range deps {
+		if d.URL == dep.URL {
+			deps[i].Done = d.Done || dep.Done
+			return deps
+		}
+	}
+	return append(deps, dep)
 }
 
 // GetTask は指定IDのタスクを取得する
+// Project全体を取得せず、アイテムのNode IDで直接取得する
 func (s *TaskService) GetTask(ctx context.Context, taskID string) (*domain.Task, error) {
-	tasks, err := s.GetTasks(ctx, nil)
-	if err != nil {
-		return nil, err
+	var query struct {
+		Node struct {
+			Item struct {
+				projectItem
+				Project struct {
+					ID string
+				}
+			} `graphql:"... on ProjectV2Item"`
+		} `graphql:"node(id: $itemId)"`
 	}
 
-	for _, t := range tasks {
-		if t.ID == taskID {
-			return t, nil
+	variables := map[string]interface{}{
+		"itemId": githubv4.ID(taskID),
+	}
+
+	if err := s.client.gql.Query(ctx, &query, variables); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("task not found: %s", taskID)
 		}
+		return nil, fmt.Errorf("failed to query task: %w", err)
+	}
+
+	// 他のProjectのアイテム・アイテム以外のNode IDは見つからないものとして扱う
+	item := &query.Node.Item
+	if item.ID == "" || item.Project.ID != s.projectID {
+		return nil, fmt.Errorf("task not found: %s", taskID)
 	}
 
-	return nil, fmt.Errorf("task not found: %s", taskID)
+	task := s.newTask(&item.projectItem)
+	if err := s.resolveDependencies(ctx, []*domain.Task{task}); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 // UpdateTask はタスクのフィールドを更新する
 func (s *TaskService) UpdateTask(ctx context.Context, task *domain.Task, exec *domain.Execution) error {
 	// Statusを更新
-	if err := s.updateSingleSelectField(ctx, task.ID, FieldStatus, string(exec.NewStatus())); err != nil {
+	if err := s.updateSingleSelectField(ctx, task.ID, FieldStatus, s.statusOption(exec.NewStatus())); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
@@ -259,105 +583,158 @@ func (s *TaskService) UpdateTask(ctx context.Context, task *domain.Task, exec *d
 		return fmt.Errorf("failed to update executed at: %w", err)
 	}
 
+	// Costを累計で更新（フィールドがない場合はスキップ）
+	if s.hasField(FieldCost) && exec.CostUSD > 0 {
+		task.Cost += exec.CostUSD
+		if err := s.updateNumberField(ctx, task.ID, FieldCost, task.Cost); err != nil {
+			return fmt.Errorf("failed to update cost: %w", err)
+		}
+	}
+
+	// Attemptsを更新（フィールドがない場合はスキップ）
+	if s.hasField(FieldAttempts) && exec.Attempts > 0 {
+		task.Attempts = exec.Attempts
+		if err := s.updateNumberField(ctx, task.ID, FieldAttempts, float64(exec.Attempts)); err != nil {
+			return fmt.Errorf("failed to update attempts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RejectTask はタスクを実行せずに In review にし、理由を Result に記録する
+func (s *TaskService) RejectTask(ctx context.Context, task *domain.Task, reason string) error {
+	if err := s.updateSingleSelectField(ctx, task.ID, FieldStatus, string(domain.StatusInReview)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	if err := s.updateTextField(ctx, task.ID, FieldResult, reason); err != nil {
+		return fmt.Errorf("failed to update result: %w", err)
+	}
 	return nil
 }
 
 // SetTaskInProgress はタスクをInProgressに設定する
 func (s *TaskService) SetTaskInProgress(ctx context.Context, taskID string) error {
-	return s.updateSingleSelectField(ctx, taskID, FieldStatus, string(domain.StatusInProgress))
+	return s.SetTaskStatus(ctx, taskID, domain.StatusInProgress)
 }
 
-func (s *TaskService) updateTextField(ctx context.Context, itemID, fieldName, value string) error {
-	field, ok := s.fields[fieldName]
-	if !ok {
-		return fmt.Errorf("field not found: %s", fieldName)
-	}
+// SetTaskStatus はタスクのStatusを変更する
+func (s *TaskService) SetTaskStatus(ctx context.Context, taskID string, status domain.Status) error {
+	return s.updateSingleSelectField(ctx, taskID, FieldStatus, s.statusOption(status))
+}
 
-	var mutation struct {
-		UpdateProjectV2ItemFieldValue struct {
-			ProjectV2Item struct {
-				ID string
-			} `graphql:"projectV2Item"`
-		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+// ClearTaskSession はタスクのSessionIDとResultを消去する（次の実行を新しいセッションで始める）
+func (s *TaskService) ClearTaskSession(ctx context.Context, task *domain.Task) error {
+	if err := s.clearField(ctx, task.ID, FieldSessionID); err != nil {
+		return fmt.Errorf("failed to clear session id: %w", err)
 	}
-
-	input := githubv4.UpdateProjectV2ItemFieldValueInput{
-		ProjectID: githubv4.ID(s.projectID),
-		ItemID:    githubv4.ID(itemID),
-		FieldID:   githubv4.ID(field.ID),
-		Value: githubv4.ProjectV2FieldValue{
-			Text: githubv4.NewString(githubv4.String(value)),
-		},
+	if err := s.clearField(ctx, task.ID, FieldResult); err != nil {
+		return fmt.Errorf("failed to clear result: %w", err)
 	}
-
-	return s.client.gql.Mutate(ctx, &mutation, input, nil)
+	task.SessionID = ""
+	task.Result = ""
+	return nil
 }
 
-func (s *TaskService) updateSingleSelectField(ctx context.Context, itemID, fieldName, optionName string) error {
-	field, ok := s.fields[fieldName]
-	if !ok {
-		return fmt.Errorf("field not found: %s", fieldName)
-	}
+// clearField はフィールドの値を消去する
+func (s *TaskService) clearField(ctx context.Context, itemID, fieldName string) error {
+	return s.withFreshMetadata(ctx, func() error {
+		projectID, field, err := s.lookupField(fieldName)
+		if err != nil {
+			return err
+		}
 
-	// オプションIDを検索
-	var optionID string
-	for _, opt := range field.Options {
-		if opt.Name == optionName {
-			optionID = opt.ID
-			break
+		var mutation struct {
+			ClearProjectV2ItemFieldValue struct {
+				ProjectV2Item struct {
+					ID string
+				} `graphql:"projectV2Item"`
+			} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
 		}
-	}
-	if optionID == "" {
-		return fmt.Errorf("option not found: %s in field %s", optionName, fieldName)
-	}
 
-	var mutation struct {
-		UpdateProjectV2ItemFieldValue struct {
-			ProjectV2Item struct {
-				ID string
-			} `graphql:"projectV2Item"`
-		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
-	}
+		input := githubv4.ClearProjectV2ItemFieldValueInput{
+			ProjectID: githubv4.ID(projectID),
+			ItemID:    githubv4.ID(itemID),
+			FieldID:   githubv4.ID(field.ID),
+		}
 
-	input := githubv4.UpdateProjectV2ItemFieldValueInput{
-		ProjectID: githubv4.ID(s.projectID),
-		ItemID:    githubv4.ID(itemID),
-		FieldID:   githubv4.ID(field.ID),
-		Value: githubv4.ProjectV2FieldValue{
-			SingleSelectOptionID: githubv4.NewString(githubv4.String(optionID)),
-		},
-	}
+		return s.client.gql.Mutate(ctx, &mutation, input, nil)
+	})
+}
 
-	return s.client.gql.Mutate(ctx, &mutation, input, nil)
+func (s *TaskService) updateTextField(ctx context.Context, itemID, fieldName, value string) error {
+	return s.updateField(ctx, itemID, fieldName, func(ProjectField) (githubv4.ProjectV2FieldValue, error) {
+		return githubv4.ProjectV2FieldValue{
+			Text: githubv4.NewString(githubv4.String(value)),
+		}, nil
+	})
+}
+
+func (s *TaskService) updateSingleSelectField(ctx context.Context, itemID, fieldName, optionName string) error {
+	return s.updateField(ctx, itemID, fieldName, func(field ProjectField) (githubv4.ProjectV2FieldValue, error) {
+		// オプションIDを検索
+		var optionID string
+		for _, opt := range field.Options {
+			if opt.Name == optionName {
+				optionID = opt.ID
+				break
+			}
+		}
+		if optionID == "" {
+			return githubv4.ProjectV2FieldValue{}, fmt.Errorf("option not found: %s in field %s", optionName, fieldName)
+		}
+		return githubv4.ProjectV2FieldValue{
+			SingleSelectOptionID: githubv4.NewString(githubv4.String(optionID)),
+		}, nil
+	})
 }
 
 func (s *TaskService) updateDateField(ctx context.Context, itemID, fieldName string, date time.Time) error {
-	field, ok := s.fields[fieldName]
-	if !ok {
-		return fmt.Errorf("field not found: %s", fieldName)
-	}
+	return s.updateField(ctx, itemID, fieldName, func(ProjectField) (githubv4.ProjectV2FieldValue, error) {
+		return githubv4.ProjectV2FieldValue{
+			Date: &githubv4.Date{Time: date},
+		}, nil
+	})
+}
 
-	var mutation struct {
-		UpdateProjectV2ItemFieldValue struct {
-			ProjectV2Item struct {
-				ID string
-			} `graphql:"projectV2Item"`
-		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
-	}
+func (s *TaskService) updateNumberField(ctx context.Context, itemID, fieldName string, value float64) error {
+	return s.updateField(ctx, itemID, fieldName, func(ProjectField) (githubv4.ProjectV2FieldValue, error) {
+		return githubv4.ProjectV2FieldValue{
+			Number: githubv4.NewFloat(githubv4.Float(value)),
+		}, nil
+	})
+}
 
-	dateStr := date.Format("2006-01-02")
-	dateValue := githubv4.Date{Time: date}
-	input := githubv4.UpdateProjectV2ItemFieldValueInput{
-		ProjectID: githubv4.ID(s.projectID),
-		ItemID:    githubv4.ID(itemID),
-		FieldID:   githubv4.ID(field.ID),
-		Value: githubv4.ProjectV2FieldValue{
-			Date: &dateValue,
-		},
-	}
-	_ = dateStr // suppress unused variable warning
+// updateField はフィールドの値を value が返す値に更新する
+// value はフィールドの定義を受け取るので、メタデータを取得し直したときは新しいIDで作り直される
+func (s *TaskService) updateField(ctx context.Context, itemID, fieldName string, value func(ProjectField) (githubv4.ProjectV2FieldValue, error)) error {
+	return s.withFreshMetadata(ctx, func() error {
+		projectID, field, err := s.lookupField(fieldName)
+		if err != nil {
+			return err
+		}
+		v, err := value(field)
+		if err != nil {
+			return err
+		}
 
-	return s.client.gql.Mutate(ctx, &mutation, input, nil)
+		var mutation struct {
+			UpdateProjectV2ItemFieldValue struct {
+				ProjectV2Item struct {
+					ID string
+				} `graphql:"projectV2Item"`
+			} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+		}
+
+		input := githubv4.UpdateProjectV2ItemFieldValueInput{
+			ProjectID: githubv4.ID(projectID),
+			ItemID:    githubv4.ID(itemID),
+			FieldID:   githubv4.ID(field.ID),
+			Value:     v,
+		}
+
+		return s.client.gql.Mutate(ctx, &mutation, input, nil)
+	})
 }
 
 // AddIssueComment はタスクに紐づくIssueにコメントを追加する
@@ -368,22 +745,37 @@ func (s *TaskService) AddIssueComment(ctx context.Context, task *domain.Task, bo
 	return s.client.AddIssueComment(ctx, task.IssueURL, body)
 }
 
-// GetFirstReadyTask はReadyステータスの最初のタスクを取得する
-func (s *TaskService) GetFirstReadyTask(ctx context.Context) (*domain.Task, error) {
+// GetReadyTasks は依存関係を満たした実行可能なタスクを優先度順に取得する
+// filter のラベル・リポジトリで絞り込み、Limit は優先度順に並べた後に適用する（Status は常にReady）
+func (s *TaskService) GetReadyTasks(ctx context.Context, filter *domain.TaskFilter) ([]*domain.Task, error) {
 	status := domain.StatusReady
-	filter := &domain.TaskFilter{Status: &status}
-	tasks, err := s.GetTasks(ctx, filter)
+	ready := &domain.TaskFilter{Status: &status}
+	if filter != nil {
+		ready.Labels = filter.Labels
+		ready.Repository = filter.Repository
+	}
+	tasks, err := s.GetTasks(ctx, ready)
 	if err != nil {
 		return nil, err
 	}
+	tasks = domain.Schedule(tasks)
+	if filter != nil && filter.Limit > 0 && len(tasks) > filter.Limit {
+		tasks = tasks[:filter.Limit]
+	}
+	return tasks, nil
+}
 
-	for _, t := range tasks {
-		if t.IsExecutable() {
-			return t, nil
-		}
+// GetFirstReadyTask はReadyステータスで最も優先度の高いタスクを取得する
+func (s *TaskService) GetFirstReadyTask(ctx context.Context) (*domain.Task, error) {
+	tasks, err := s.GetReadyTasks(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil // 実行可能なタスクがない
+	if len(tasks) == 0 {
+		return nil, nil // 実行可能なタスクがない
+	}
+	return tasks[0], nil
 }
 
 // VibeCommentMarker はvibeが追加したコメントを識別するマーカー
@@ -406,46 +798,86 @@ func isVibeComment(comment string) bool {
 	return false
 }
 
-// LoadTaskPrompt はIssueのコメントからプロンプトを読み込む
+// SetAssembler はIssueのスレッドからプロンプトを組み立てる方法（トークン数の予算など）を設定する
+func (s *TaskService) SetAssembler(a *prompt.Assembler) {
+	s.assembler = a
+}
+
+// LoadTaskPrompt はIssueの本文とコメントのMarkdownからプロンプトを読み込む
+// プロンプトに含めた最後のコメントを task.PromptCursor に設定する
+// 予算を超える長いスレッドは途中のコメントを要約・省略し、その内容を task.PromptNote に設定する
 func (s *TaskService) LoadTaskPrompt(ctx context.Context, task *domain.Task) error {
 	if task.IssueURL == "" {
 		return fmt.Errorf("task has no associated issue")
 	}
 
-	comments, err := s.client.GetIssueComments(ctx, task.IssueURL)
+	thread, err := s.client.GetIssueThread(ctx, task.IssueURL)
 	if err != nil {
 		return fmt.Errorf("failed to get issue comments: %w", err)
 	}
 
-	if len(comments) == 0 {
-		return fmt.Errorf("no comments found in issue")
+	// vibeが追加したコメントを除外する
+	var issue *domain.Comment
+	var included, comments []domain.Comment
+	if strings.TrimSpace(thread.Issue.Body) != "" {
+		issue = &thread.Issue
+		included = append(included, thread.Issue)
 	}
-
-	// vibeが追加したコメントを除外
-	var promptParts []string
-	for _, comment := range comments {
-		if !isVibeComment(comment) {
-			promptParts = append(promptParts, comment)
+	cursor := &domain.PromptCursor{CreatedAt: time.Now()}
+	for _, comment := range thread.Comments {
+		if strings.TrimSpace(comment.Body) == "" || isVibeComment(comment.Body) {
+			continue
 		}
+		comments = append(comments, comment)
+		cursor = commentCursor(comment)
 	}
+	included = append(included, comments...)
 
-	if len(promptParts) == 0 {
+	if len(included) == 0 {
+		if len(thread.Comments) == 0 {
+			return fmt.Errorf("no comments found in issue")
+		}
 		return fmt.Errorf("no user comments found in issue (only vibe comments)")
 	}
 
-	// 全コメントを改行で結合してプロンプトとする
-	task.Prompt = strings.Join(promptParts, "\n\n---\n\n")
+	// 投稿者を付けたMarkdownを予算内でプロンプトにする
+	text, stats, err := s.assembler.Thread(ctx, issue, comments)
+	task.Prompt = text
+	task.PromptCursor = cursor
+	task.Comments = included
+	task.PromptNote = ""
+	task.Summarization = stats.Summarization
+	if stats.Condensed > 0 || stats.Omitted > 0 {
+		how := "truncated"
+		if stats.Summarized {
+			how = "summarized"
+		}
+		task.PromptNote = fmt.Sprintf("%d earlier comment(s) %s, %d omitted to fit the prompt budget (~%d → ~%d tokens)",
+			stats.Condensed, how, stats.Omitted, stats.Full, stats.Tokens)
+		if err != nil {
+			task.PromptNote += fmt.Sprintf("; summarization failed: %v", err)
+		}
+	}
 	return nil
 }
 
 // GetStatusOptions はStatusフィールドの選択肢一覧を返す
 func (s *TaskService) GetStatusOptions() []FieldOption {
+	s.metaMu.RLock()
+	defer s.metaMu.RUnlock()
 	if field, ok := s.fields[FieldStatus]; ok {
 		return field.Options
 	}
 	return nil
 }
 
+// ProjectID はProjectのNode IDを返す（Initialize後に有効）
+func (s *TaskService) ProjectID() string {
+	s.metaMu.RLock()
+	defer s.metaMu.RUnlock()
+	return s.projectID
+}
+
 // GetFields は全フィールド情報を返す
 func (s *TaskService) GetFields() map[string]ProjectField {
 	return s.fields
@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// issueItem はテスト用のIssueのProjectアイテムを作成する
+func issueItem(id, url, body string, tracks ...string) *projectItem {
+	item := &projectItem{ID: id}
+	item.Content.Issue.Title = id
+	item.Content.Issue.URL = url
+	item.Content.Issue.Body = body
+	item.Content.Issue.State = "OPEN"
+	for _, u := range tracks {
+		item.Content.Issue.TrackedIssues.Nodes = append(item.Content.Issue.TrackedIssues.Nodes, struct {
+			URL   string
+			State string
+		}{URL: u, State: "OPEN"})
+	}
+	return item
+}
+
+func TestNewTaskTrackedIssues(t *testing.T) {
+	const (
+		parentURL = "https://github.com/o/r/issues/1"
+		childURL  = "https://github.com/o/r/issues/2"
+	)
+
+	tests := []struct {
+		name       string
+		parentBody string
+		tracks     []string
+	}{
+		{name: "task list only", parentBody: "- [ ] #2"},
+		{name: "tracked only", tracks: []string{childURL}},
+		{name: "task list and tracked", parentBody: "- [ ] #2", tracks: []string{childURL}},
+	}
+
+	s := NewTaskService(nil, 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := s.newTask(issueItem("parent", parentURL, tt.parentBody, tt.tracks...))
+			child := s.newTask(issueItem("child", childURL, ""))
+
+			if len(parent.Dependencies) != 1 || parent.Dependencies[0].URL != childURL {
+				t.Errorf("parent dependencies = %+v, want only %s", parent.Dependencies, childURL)
+			}
+			if len(child.Dependencies) != 0 {
+				t.Errorf("child dependencies = %+v, want none", child.Dependencies)
+			}
+			if cycles := domain.NewGraph([]*domain.Task{parent, child}).Cycles(); len(cycles) != 0 {
+				t.Errorf("parent and child form a cycle: %v", cycles)
+			}
+		})
+	}
+}
+
+// graphQLServer は resource の問い合わせに states の状態を返すGraphQLサーバーを起動する
+// 受け取ったクエリの数を返す関数を返す
+func graphQLServer(t *testing.T, states map[string]string) (*Client, func() int) {
+	t.Helper()
+	var queries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		queries++
+		data := make(map[string]any)
+		for name, u := range req.Variables {
+			alias := "r" + strings.TrimPrefix(name, "url")
+			if !strings.Contains(req.Query, alias+": resource(url: $"+name+")") {
+				t.Errorf("query has no alias %s for $%s: %s", alias, name, req.Query)
+			}
+			if state, ok := states[u]; ok {
+				data[alias] = map[string]string{"state": state}
+			} else {
+				data[alias] = nil
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{gql: githubv4.NewEnterpriseClient(srv.URL, srv.Client())}, func() int { return queries }
+}
+
+func TestResolveDependencies(t *testing.T) {
+	const (
+		openURL   = "https://github.com/o/other/issues/1"
+		closedURL = "https://github.com/o/other/issues/2"
+		mergedURL = "https://github.com/o/other/pull/3"
+		goneURL   = "https://github.com/o/other/issues/4"
+	)
+	client, queries := graphQLServer(t, map[string]string{openURL: "OPEN", closedURL: "CLOSED", mergedURL: "MERGED"})
+	s := NewTaskService(client, 1)
+
+	body := "- [ ] " + openURL + "\n- [ ] " + closedURL + "\n- [ ] " + mergedURL + "\n- [ ] " + goneURL
+	a := s.newTask(issueItem("a", "https://github.com/o/r/issues/10", body))
+	b := s.newTask(issueItem("b", "https://github.com/o/r/issues/11", "Blocked by "+openURL+", "+closedURL))
+	if err := s.resolveDependencies(context.Background(), []*domain.Task{a, b}); err != nil {
+		t.Fatal(err)
+	}
+	if got := queries(); got != 1 {
+		t.Errorf("queries = %d, want 1 batched query", got)
+	}
+
+	want := []domain.Dependency{
+		{URL: openURL},
+		{URL: closedURL, Done: true},
+		{URL: mergedURL, Done: true},
+		{URL: goneURL, Unresolved: true},
+	}
+	if !reflect.DeepEqual(a.Dependencies, want) {
+		t.Errorf("dependencies = %+v, want %+v", a.Dependencies, want)
+	}
+
+	// キャッシュがある間は問い合わせず、ResetDependencyCache の後は問い合わせ直す
+	c := s.newTask(issueItem("c", "https://github.com/o/r/issues/12", "Blocked by "+goneURL))
+	if err := s.resolveDependencies(context.Background(), []*domain.Task{c}); err != nil {
+		t.Fatal(err)
+	}
+	if got := queries(); got != 1 {
+		t.Errorf("queries after a cached lookup = %d, want 1", got)
+	}
+	s.ResetDependencyCache()
+	if err := s.resolveDependencies(context.Background(), []*domain.Task{c}); err != nil {
+		t.Fatal(err)
+	}
+	if got := queries(); got != 2 {
+		t.Errorf("queries after reset = %d, want 2", got)
+	}
+}
+
+func TestResolveDependenciesError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	s := NewTaskService(&Client{gql: githubv4.NewEnterpriseClient(srv.URL, srv.Client())}, 1)
+
+	task := s.newTask(issueItem("a", "https://github.com/o/r/issues/1", "Blocked by o/other#2"))
+	if err := s.resolveDependencies(context.Background(), []*domain.Task{task}); err == nil {
+		t.Fatal("resolveDependencies() = nil, want the lookup error")
+	}
+	if task.Dependencies[0].Done {
+		t.Error("dependency is done after a failed lookup")
+	}
+}
@@ -0,0 +1,283 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// フックを実行するタイミング
+const (
+	PreRun    = "pre_run"    // Claude Codeの実行前
+	PostRun   = "post_run"   // Claude Codeの実行後（成否に関わらず）
+	OnSuccess = "on_success" // post_run の後、タスクが成功した場合
+	OnFailure = "on_failure" // post_run の後、タスクが失敗した場合
+	Verify    = "verify"     // Claude Codeの実行後の検証（post_run の前）
+)
+
+// maxOutput は結果に残す出力の長さ（超えた分は先頭を切り詰める）
+const maxOutput = 16 * 1024
+
+// waitDelay はタイムアウト後に出力の読み取りを打ち切るまでの時間
+const waitDelay = 5 * time.Second
+
+// Runner は設定されたフックを実行する
+type Runner struct {
+	commands map[string][]string
+	timeout  time.Duration
+}
+
+// New は設定からRunnerを作成する（フックが1つもなければ nil）
+func New(cfg config.HooksConfig) *Runner {
+	r := &Runner{
+		commands: map[string][]string{
+			PreRun:    cfg.PreRun,
+			PostRun:   cfg.PostRun,
+			OnSuccess: cfg.OnSuccess,
+			OnFailure: cfg.OnFailure,
+		},
+		timeout: cfg.Timeout.Duration,
+	}
+	if r.timeout == 0 {
+		r.timeout = config.DefaultHookTimeout
+	}
+	for _, cmds := range r.commands {
+		if len(cmds) > 0 {
+			return r
+		}
+	}
+	return nil
+}
+
+// NewVerifier は検証コマンドを実行するRunnerを作成する（コマンドがなければ nil）
+// 検証コマンドは Verify ステージとして、フックと同じ環境変数・標準入力で実行する
+func NewVerifier(cfg config.VerifyConfig) *Runner {
+	if len(cfg.Command) == 0 {
+		return nil
+	}
+	r := &Runner{
+		commands: map[string][]string{Verify: cfg.Command},
+		timeout:  cfg.Timeout.Duration,
+	}
+	if r.timeout == 0 {
+		r.timeout = config.DefaultVerifyTimeout
+	}
+	return r
+}
+
+// Commands は stage に設定されたコマンドを返す
+func (r *Runner) Commands(stage string) []string {
+	if r == nil {
+		return nil
+	}
+	return r.commands[stage]
+}
+
+// Run は stage のコマンドを順に実行する
+// 失敗したコマンドがあればそこで止め、ok を false で返す
+// exec は実行前（pre_run）なら nil。実行したコマンドと結果は log に書き込む（nil なら書き込まない）
+func (r *Runner) Run(ctx context.Context, stage string, task *domain.Task, exec *domain.Execution, log io.Writer) (results []domain.HookResult, ok bool) {
+	if r == nil {
+		return nil, true
+	}
+	payload, err := json.Marshal(newPayload(stage, task, exec))
+	if err != nil {
+		payload = []byte("{}")
+	}
+	env := append(os.Environ(), environ(stage, task, exec)...)
+	if log == nil {
+		log = io.Discard
+	}
+
+	for _, command := range r.commands[stage] {
+		icon := "🪝"
+		if stage == Verify {
+			icon = "🧪"
+		}
+		fmt.Fprintf(log, "%s %s: %s\n", icon, stage, command)
+		result := r.run(ctx, stage, command, task.WorkDir, env, payload)
+		results = append(results, result)
+
+		if !result.Failed() {
+			fmt.Fprintf(log, "   ✓ (%.1fs)\n", result.Duration.Seconds())
+			continue
+		}
+		fmt.Fprintf(log, "   ✗ %s (%.1fs)\n", Describe(result), result.Duration.Seconds())
+		for _, line := range tail(result.Output, 10) {
+			fmt.Fprintf(log, "   │ %s\n", line)
+		}
+		return results, false
+	}
+	return results, true
+}
+
+func (r *Runner) run(ctx context.Context, stage, command, dir string, env []string, payload []byte) domain.HookResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := shell(ctx, command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(payload)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// タイムアウト時にシェルの子プロセスが出力を開いたままでも待ち続けない
+	cmd.WaitDelay = waitDelay
+
+	start := time.Now()
+	err := cmd.Run()
+	result := domain.HookResult{
+		Stage:    stage,
+		Command:  command,
+		Output:   truncateHead(out.String(), maxOutput),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", r.timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Describe はフックの失敗理由を返す
+func Describe(h domain.HookResult) string {
+	if h.Error != "" {
+		return h.Error
+	}
+	return fmt.Sprintf("exit status %d", h.ExitCode)
+}
+
+// shell はOSのシェルでコマンドを実行する
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// payload はフックの標準入力に渡すJSON
+type payload struct {
+	Hook      string            `json:"hook"`
+	Task      taskPayload       `json:"task"`
+	Execution *executionPayload `json:"execution,omitempty"`
+}
+
+type taskPayload struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	IssueURL string `json:"issue_url,omitempty"`
+	WorkDir  string `json:"work_dir"`
+	Prompt   string `json:"prompt"`
+}
+
+type executionPayload struct {
+	Success         bool    `json:"success"`
+	Output          string  `json:"output"`
+	Error           string  `json:"error,omitempty"`
+	ExitCode        int     `json:"exit_code"`
+	TimedOut        bool    `json:"timed_out"`
+	SessionID       string  `json:"session_id,omitempty"`
+	CostUSD         float64 `json:"cost_usd"`
+	Tokens          int     `json:"tokens"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Attempts        int     `json:"attempts"`
+}
+
+func newPayload(stage string, task *domain.Task, exec *domain.Execution) payload {
+	p := payload{
+		Hook: stage,
+		Task: taskPayload{
+			ID:       task.ID,
+			Title:    task.Title,
+			IssueURL: task.IssueURL,
+			WorkDir:  task.WorkDir,
+			Prompt:   task.Prompt,
+		},
+	}
+	if exec != nil {
+		p.Execution = &executionPayload{
+			Success:         exec.Success,
+			Output:          exec.Output,
+			Error:           exec.Error,
+			ExitCode:        exec.ExitCode,
+			TimedOut:        exec.TimedOut,
+			SessionID:       exec.SessionID,
+			CostUSD:         exec.CostUSD,
+			Tokens:          exec.Tokens(),
+			DurationSeconds: exec.Duration.Seconds(),
+			Attempts:        exec.Attempts,
+		}
+	}
+	return p
+}
+
+// environ はフックに渡す環境変数を返す
+func environ(stage string, task *domain.Task, exec *domain.Execution) []string {
+	env := []string{
+		"VIBE_HOOK=" + stage,
+		"VIBE_TASK_ID=" + task.ID,
+		"VIBE_TASK_TITLE=" + task.Title,
+		"VIBE_TASK_URL=" + task.IssueURL,
+		"VIBE_TASK_WORK_DIR=" + task.WorkDir,
+	}
+	if exec != nil {
+		env = append(env,
+			"VIBE_EXEC_SUCCESS="+strconv.FormatBool(exec.Success),
+			"VIBE_EXEC_EXIT_CODE="+strconv.Itoa(exec.ExitCode),
+			"VIBE_EXEC_SESSION_ID="+exec.SessionID,
+			"VIBE_EXEC_COST_USD="+strconv.FormatFloat(exec.CostUSD, 'f', -1, 64),
+			"VIBE_EXEC_DURATION="+strconv.FormatFloat(exec.Duration.Seconds(), 'f', 1, 64),
+			"VIBE_EXEC_ERROR="+exec.Error,
+		)
+	}
+	return env
+}
+
+// truncateHead は長い出力の先頭を切り詰める（エラーは末尾に出ることが多いため）
+func truncateHead(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	} else {
+		// マルチバイト文字の途中から始めない
+		s = strings.TrimLeftFunc(s, func(r rune) bool { return r == utf8.RuneError })
+	}
+	return "...\n" + s
+}
+
+// tail は出力の末尾 n 行を返す
+func tail(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,11 @@
+package notify
+
+import "context"
+
+// Desktop はOSのデスクトップ通知
+type Desktop struct{}
+
+// Notify はデスクトップ通知を表示する
+func (d *Desktop) Notify(ctx context.Context, msg Message) error {
+	return sendDesktop(ctx, msg.Title(), msg.Text())
+}
@@ -0,0 +1,20 @@
+//go:build darwin
+
+package notify
+
+import (
+	"context"
+	"os/exec"
+)
+
+// notificationScript はタイトルと本文を引数で受け取るAppleScript
+// 文字列をスクリプトに埋め込まないため、引用符などのエスケープが不要になる
+const notificationScript = `on run argv
+	display notification (item 2 of argv) with title (item 1 of argv) sound name "Glass"
+end run`
+
+// sendDesktop sends a macOS notification using osascript
+func sendDesktop(ctx context.Context, title, message string) error {
+	cmd := exec.CommandContext(ctx, "osascript", "-e", notificationScript, title, message)
+	return cmd.Run()
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// sendDesktop sends a Linux notification using notify-send, falling back to D-Bus via gdbus
+func sendDesktop(ctx context.Context, title, message string) error {
+	// デスクトップセッションがない環境（サーバー・CIなど）では何もしない
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return nil
+	}
+
+	if path, err := exec.LookPath("notify-send"); err == nil {
+		return exec.CommandContext(ctx, path, "--app-name=vibe", title, message).Run()
+	}
+
+	if path, err := exec.LookPath("gdbus"); err == nil {
+		cmd := exec.CommandContext(ctx, path, "call", "--session",
+			"--dest", "org.freedesktop.Notifications",
+			"--object-path", "/org/freedesktop/Notifications",
+			"--method", "org.freedesktop.Notifications.Notify",
+			gvariantString("vibe"), "0", gvariantString(""),
+			gvariantString(title), gvariantString(message),
+			"[]", "{}", "5000")
+		return cmd.Run()
+	}
+
+	return fmt.Errorf("neither notify-send nor gdbus found")
+}
+
+// gvariantString は文字列をGVariantのテキスト形式にエスケープする
+func gvariantString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package notify
+
+import "context"
+
+// sendDesktop is a no-op on platforms without desktop notification support
+func sendDesktop(ctx context.Context, title, message string) error {
+	return nil
+}
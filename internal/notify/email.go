@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+// defaultSMTPPort はSMTPのデフォルトポート（STARTTLS）
+const defaultSMTPPort = 587
+
+// Email はSMTPによるメール通知
+type Email struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func newEmail(c config.SMTPConfig) (*Email, error) {
+	if c.Host == "" || c.From == "" || len(c.To) == 0 {
+		return nil, fmt.Errorf("smtp requires host, from, and to")
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	e := &Email{
+		addr: net.JoinHostPort(c.Host, strconv.Itoa(port)),
+		from: c.From,
+		to:   c.To,
+	}
+	if c.Username != "" {
+		e.auth = smtp.PlainAuth("", c.Username, os.Getenv(c.PasswordEnv), c.Host)
+	}
+	return e, nil
+}
+
+// Notify はメッセージをメールで送信する
+func (e *Email) Notify(ctx context.Context, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", sanitizeHeader(msg.Title()+": "+msg.TaskTitle)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Text())
+	if msg.TaskURL != "" {
+		b.WriteString("\r\n\r\n" + msg.TaskURL)
+	}
+	b.WriteString("\r\n")
+
+	// net/smtp は context に対応していないため、別ゴルーチンで送信してキャンセルを待つ
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(e.addr, e.auth, e.from, e.to, []byte(b.String()))
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sanitizeHeader はヘッダーインジェクションを防ぐため改行を除去する
+func sanitizeHeader(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
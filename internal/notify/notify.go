@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+)
+
+// Event は通知イベントの種類
+type Event string
+
+const (
+	EventStarted        Event = "started"
+	EventSucceeded      Event = "succeeded"
+	EventFailed         Event = "failed"
+	EventBudgetExceeded Event = "budget_exceeded"
+	EventNeedsInput     Event = "needs_input"
+
+	// EventTest は vibe doctor が送るテスト通知（購読設定に関係なく全通知先に送る）
+	EventTest Event = "test"
+)
+
+// defaultEvents はイベント未指定時に通知するイベント
+var defaultEvents = []Event{EventSucceeded, EventFailed, EventBudgetExceeded, EventNeedsInput}
+
+// Message は通知内容
+type Message struct {
+	Event     Event
+	TaskTitle string
+	TaskURL   string // 関連Issue URL
+	Duration  time.Duration
+	CostUSD   float64
+	Error     string
+	Detail    string // 予算超過の理由・Claude Codeの質問など
+}
+
+// Title は通知のタイトルを返す
+func (m Message) Title() string {
+	switch m.Event {
+	case EventStarted:
+		return "▶️ vibe: Task Started"
+	case EventSucceeded:
+		return "✅ vibe: Task Completed"
+	case EventFailed:
+		return "❌ vibe: Task Failed"
+	case EventBudgetExceeded:
+		return "⏸ vibe: Budget Exceeded"
+	case EventNeedsInput:
+		return "❓ vibe: Input Needed"
+	case EventTest:
+		return "🔔 vibe: Test Notification"
+	default:
+		return "vibe"
+	}
+}
+
+// Text は通知の本文を返す
+func (m Message) Text() string {
+	switch m.Event {
+	case EventSucceeded:
+		return fmt.Sprintf("%s (%.1fs, $%.2f)", m.TaskTitle, m.Duration.Seconds(), m.CostUSD)
+	case EventFailed:
+		return fmt.Sprintf("%s: %s", m.TaskTitle, truncate(m.Error, 200))
+	case EventNeedsInput:
+		return fmt.Sprintf("%s: %s", m.TaskTitle, truncate(m.Detail, 200))
+	case EventBudgetExceeded:
+		if m.TaskTitle != "" {
+			return fmt.Sprintf("%s: %s", m.TaskTitle, m.Detail)
+		}
+		return m.Detail
+	default:
+		return m.TaskTitle
+	}
+}
+
+// Notifier は通知の送信先
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// sink はイベントで絞り込まれた通知先
+type sink struct {
+	name     string
+	notifier Notifier
+	events   map[Event]bool
+}
+
+// Dispatcher は設定された全ての通知先にメッセージを配送する
+type Dispatcher struct {
+	sinks []sink
+}
+
+// New は設定から Dispatcher を作成する
+// 通知先が未設定の場合はデスクトップ通知のみを使用する
+func New(cfgs []config.NotifierConfig) (*Dispatcher, error) {
+	if len(cfgs) == 0 {
+		cfgs = []config.NotifierConfig{{Type: "desktop"}}
+	}
+
+	d := &Dispatcher{}
+	for i, c := range cfgs {
+		n, err := newNotifier(c)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers[%d]: %w", i, err)
+		}
+
+		events := make(map[Event]bool)
+		if len(c.Events) == 0 {
+			for _, e := range defaultEvents {
+				events[e] = true
+			}
+		}
+		for _, e := range c.Events {
+			switch ev := Event(e); ev {
+			case EventStarted, EventSucceeded, EventFailed, EventBudgetExceeded, EventNeedsInput:
+				events[ev] = true
+			default:
+				return nil, fmt.Errorf("notifiers[%d]: unknown event: %s", i, e)
+			}
+		}
+
+		d.sinks = append(d.sinks, sink{name: c.Type, notifier: n, events: events})
+	}
+	return d, nil
+}
+
+// Notify はイベントを購読している通知先にメッセージを送信する
+func (d *Dispatcher) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, s := range d.sinks {
+		if !s.events[msg.Event] {
+			continue
+		}
+		if err := s.notifier.Notify(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TestResult は通知先ごとのテスト結果
+type TestResult struct {
+	Name string
+	Err  error
+}
+
+// Test は全ての通知先にテスト通知を送信し、通知先ごとの結果を返す
+func (d *Dispatcher) Test(ctx context.Context) []TestResult {
+	results := make([]TestResult, 0, len(d.sinks))
+	for _, s := range d.sinks {
+		results = append(results, TestResult{
+			Name: s.name,
+			Err: s.notifier.Notify(ctx, Message{
+				Event:     EventTest,
+				TaskTitle: "Test notification from vibe doctor",
+			}),
+		})
+	}
+	return results
+}
+
+func newNotifier(c config.NotifierConfig) (Notifier, error) {
+	url := c.URL
+	if c.URLEnv != "" {
+		url = os.Getenv(c.URLEnv)
+	}
+
+	switch c.Type {
+	case "desktop":
+		return &Desktop{}, nil
+	case "webhook":
+		if url == "" {
+			return nil, fmt.Errorf("webhook requires url")
+		}
+		return &Webhook{URL: url, client: httpClient()}, nil
+	case "slack", "discord", "teams":
+		if url == "" {
+			return nil, fmt.Errorf("%s requires url", c.Type)
+		}
+		return &Chat{Kind: c.Type, URL: url, client: httpClient()}, nil
+	case "email":
+		if c.SMTP == nil {
+			return nil, fmt.Errorf("email requires smtp settings")
+		}
+		return newEmail(*c.SMTP)
+	default:
+		return nil, fmt.Errorf("unknown notifier type: %s", c.Type)
+	}
+}
+
+func httpClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// truncate は s を最大 max 文字に切り詰める（マルチバイト文字の途中では切らない）
+func truncate(s string, max int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max-3]) + "..."
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook は汎用JSON Webhookへの通知
+type Webhook struct {
+	URL    string
+	client *http.Client
+}
+
+// webhookPayload は汎用Webhookに送信するJSON
+type webhookPayload struct {
+	Event     Event     `json:"event"`
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	TaskTitle string    `json:"task_title,omitempty"`
+	TaskURL   string    `json:"task_url,omitempty"`
+	Duration  float64   `json:"duration_seconds,omitempty"`
+	CostUSD   float64   `json:"cost_usd,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notify はメッセージをJSONでPOSTする
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, w.client, w.URL, webhookPayload{
+		Event:     msg.Event,
+		Title:     msg.Title(),
+		Text:      msg.Text(),
+		TaskTitle: msg.TaskTitle,
+		TaskURL:   msg.TaskURL,
+		Duration:  msg.Duration.Seconds(),
+		CostUSD:   msg.CostUSD,
+		Error:     msg.Error,
+		Detail:    msg.Detail,
+		Timestamp: time.Now(),
+	})
+}
+
+// Chat は Slack / Discord / Teams の Incoming Webhook への通知
+type Chat struct {
+	Kind   string // slack / discord / teams
+	URL    string
+	client *http.Client
+}
+
+// Notify はチャットサービスの形式でメッセージを送信する
+func (c *Chat) Notify(ctx context.Context, msg Message) error {
+	text := fmt.Sprintf("*%s*\n%s", msg.Title(), msg.Text())
+	if msg.TaskURL != "" {
+		text += "\n" + msg.TaskURL
+	}
+
+	var payload interface{}
+	switch c.Kind {
+	case "slack":
+		payload = map[string]string{"text": text}
+	case "discord":
+		// Discordは太字が ** で、本文は2000文字まで
+		text = fmt.Sprintf("**%s**\n%s", msg.Title(), msg.Text())
+		if msg.TaskURL != "" {
+			text += "\n" + msg.TaskURL
+		}
+		payload = map[string]string{"content": truncate(text, 2000)}
+	case "teams":
+		payload = map[string]string{
+			"title": msg.Title(),
+			"text":  msg.Text(),
+		}
+	default:
+		return fmt.Errorf("unknown chat kind: %s", c.Kind)
+	}
+
+	return postJSON(ctx, c.client, c.URL, payload)
+}
+
+// postJSON は payload をJSONでPOSTし、2xx以外をエラーとして返す
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
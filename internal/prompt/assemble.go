@@ -0,0 +1,110 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// excerptChars は要約しない場合に途中のコメントから残す文字数
+const excerptChars = 280
+
+// Assembler はIssueの本文とコメントからトークン数の予算内に収まるプロンプトを組み立てる
+// Issue本文と最新の KeepRecent 件のコメントは常にそのまま残し、予算を超える場合は途中の古いコメントを
+// 要約（Summarize を指定した場合）または先頭だけに切り詰め、それでも超える分は古い順に省略する
+type Assembler struct {
+	MaxTokens  int                                                                       // プロンプトの予算（0 なら無制限）
+	KeepRecent int                                                                       // 常にそのまま残す最新のコメント数
+	Summarize  func(ctx context.Context, text string) (string, *domain.Execution, error) // 途中のコメントを要約する（nil なら切り詰める）
+}
+
+// Stats はプロンプトの組み立て結果
+type Stats struct {
+	Tokens     int  // 組み立てたプロンプトの推定トークン数
+	Full       int  // 全てのコメントをそのまま含めた場合の推定トークン数
+	Condensed  int  // 要約・切り詰めたコメント数
+	Omitted    int  // 省略したコメント数
+	Summarized bool // エージェントで要約したか
+
+	Summarization *domain.Execution // 要約の実行結果（使わなかった場合も含む。実行しなければ nil）
+}
+
+// Thread はIssue本文 issue（本文がなければ nil）とコメントからプロンプトを組み立てる
+// 要約に失敗した場合は切り詰めにフォールバックし、エラーを返す（プロンプトは有効）
+func (a *Assembler) Thread(ctx context.Context, issue *domain.Comment, comments []domain.Comment) (string, Stats, error) {
+	if a == nil {
+		a = &Assembler{}
+	}
+	var head []string
+	if issue != nil {
+		head = append(head, Comment("Issue", *issue))
+	}
+	keep := min(len(comments), max(a.KeepRecent, 0))
+	middle, recent := comments[:len(comments)-keep], comments[len(comments)-keep:]
+	var tail []string
+	for _, c := range recent {
+		tail = append(tail, Comment("Comment", c))
+	}
+
+	full := make([]string, 0, len(middle))
+	for _, c := range middle {
+		full = append(full, Comment("Comment", c))
+	}
+	text := join(head, full, tail)
+	stats := Stats{Full: EstimateTokens(text)}
+	stats.Tokens = stats.Full
+	if a.MaxTokens <= 0 || stats.Full <= a.MaxTokens || len(middle) == 0 {
+		return text, stats, nil
+	}
+
+	// エージェントで途中のコメントを1つの要約にまとめる
+	var summarizeErr error
+	if a.Summarize != nil {
+		summary, exec, err := a.Summarize(ctx, strings.Join(full, Separator))
+		stats.Summarization = exec
+		if err == nil && strings.TrimSpace(summary) != "" {
+			part := fmt.Sprintf("### Summary of %d earlier comments (condensed by vibe)\n\n%s", len(middle), strings.TrimSpace(summary))
+			text = join(head, []string{part}, tail)
+			if tokens := EstimateTokens(text); tokens <= a.MaxTokens {
+				stats.Tokens, stats.Condensed, stats.Summarized = tokens, len(middle), true
+				return text, stats, nil
+			}
+		}
+		summarizeErr = err
+		if err == nil {
+			summarizeErr = fmt.Errorf("the summary does not fit in the prompt budget")
+		}
+	}
+
+	// 途中のコメントを先頭だけに切り詰め、まだ超えていれば古い順に省略する
+	excerpts := make([]string, 0, len(middle))
+	for _, c := range middle {
+		excerpts = append(excerpts, Excerpt("Comment", c, excerptChars))
+	}
+	omitted := 0
+	for {
+		mid := excerpts[omitted:]
+		if omitted > 0 {
+			mid = append([]string{fmt.Sprintf("_%d earlier comments were omitted to fit the prompt budget._", omitted)}, mid...)
+		}
+		text = join(head, mid, tail)
+		if EstimateTokens(text) <= a.MaxTokens || omitted == len(excerpts) {
+			break
+		}
+		omitted++
+	}
+	stats.Tokens = EstimateTokens(text)
+	stats.Condensed = len(middle) - omitted
+	stats.Omitted = omitted
+	return text, stats, summarizeErr
+}
+
+func join(parts ...[]string) string {
+	var all []string
+	for _, p := range parts {
+		all = append(all, p...)
+	}
+	return strings.Join(all, Separator)
+}
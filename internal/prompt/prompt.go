@@ -0,0 +1,44 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// Separator はプロンプトの部品の区切り
+const Separator = "\n\n---\n\n"
+
+// Comment はコメントを投稿者と投稿日時の見出しを付けたMarkdownにする
+// 例: "### Comment by @alice (owner) on 2026-01-02 15:04 UTC"
+func Comment(kind string, c domain.Comment) string {
+	return heading(kind, c) + "\n\n" + strings.TrimSpace(c.Body)
+}
+
+// Excerpt はコメントの先頭 n 文字だけを見出し付きで返す（短いコメントはそのまま）
+func Excerpt(kind string, c domain.Comment, n int) string {
+	body := []rune(strings.TrimSpace(c.Body))
+	if len(body) <= n {
+		return Comment(kind, c)
+	}
+	return fmt.Sprintf("%s (condensed)\n\n%s …", heading(kind, c), strings.TrimSpace(string(body[:n])))
+}
+
+func heading(kind string, c domain.Comment) string {
+	return fmt.Sprintf("### %s by %s", kind, c.Attribution())
+}
+
+// EstimateTokens はテキストのトークン数を概算する
+// ASCII は4文字で1トークン、それ以外（日本語など）は1文字1トークンとして数える
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < 0x80 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
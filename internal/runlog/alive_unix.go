@@ -0,0 +1,19 @@
+//go:build !windows
+
+package runlog
+
+import (
+	"os"
+	"syscall"
+)
+
+// alive はプロセスが実行中かどうかを返す
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	// 他のユーザーのプロセスは EPERM になるが実行中
+	return err == nil || err == syscall.EPERM
+}
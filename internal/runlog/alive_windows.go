@@ -0,0 +1,16 @@
+//go:build windows
+
+package runlog
+
+import "os"
+
+// alive はプロセスが実行中かどうかを返す
+// Windows では FindProcess が終了したプロセスに対してエラーを返す
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
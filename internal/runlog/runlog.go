@@ -0,0 +1,266 @@
+package runlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// dirName は実行中の状態と出力を置くディレクトリ名（~/.vibe/runs）
+const dirName = "runs"
+
+// Run は実行中のタスク（vibe run / vibe watch のワーカー）を表す
+// ~/.vibe/runs/<task-id>.json に保存し、実行が終わると削除する
+type Run struct {
+	TaskID    string    `json:"task_id"`
+	Title     string    `json:"title"`
+	Project   string    `json:"project"` // owner/#number
+	IssueURL  string    `json:"issue_url,omitempty"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	Log       string    `json:"log"` // 出力を追記するファイル
+}
+
+// Log は実行中のタスクの出力を ~/.vibe/runs/<task-id>.log に書き込む
+// 実行が終わってもログは残し、次の実行で上書きする
+type Log struct {
+	mu     sync.Mutex
+	file   *os.File
+	state  string
+	closed bool
+}
+
+// Dir は実行中の状態と出力を置くディレクトリを返す
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, dirName), nil
+}
+
+// unsafeChars はファイル名に使えない文字
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// LogPath はタスクの出力ファイルのパスを返す
+func LogPath(taskID string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, unsafeChars.ReplaceAllString(taskID, "_")+".log"), nil
+}
+
+// cursorPath はタスクのプロンプトカーソルのファイルのパスを返す
+// Running が *.json を実行中の記録として読むため、拡張子は .cursor にする
+func cursorPath(taskID string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, unsafeChars.ReplaceAllString(taskID, "_")+".cursor"), nil
+}
+
+// SaveCursor はタスクのプロンプトに含めた最後のコメントを記録する
+func SaveCursor(taskID string, cursor *domain.PromptCursor) error {
+	path, err := cursorPath(taskID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create runs dir: %w", err)
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prompt cursor: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write prompt cursor: %w", err)
+	}
+	return nil
+}
+
+// LoadCursor は記録したプロンプトカーソルを返す（記録がなければ nil）
+func LoadCursor(taskID string) (*domain.PromptCursor, error) {
+	path, err := cursorPath(taskID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read prompt cursor: %w", err)
+	}
+	var cursor domain.PromptCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt cursor: %w", err)
+	}
+	return &cursor, nil
+}
+
+// Start はタスクの実行を記録し、出力の書き込み先を返す
+func Start(project string, task *domain.Task) (*Log, error) {
+	path, err := LogPath(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create runs dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log: %w", err)
+	}
+
+	run := Run{
+		TaskID:    task.ID,
+		Title:     task.Title,
+		Project:   project,
+		IssueURL:  task.IssueURL,
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+		Log:       path,
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to marshal run: %w", err)
+	}
+	state := strings.TrimSuffix(path, ".log") + ".json"
+	if err := os.WriteFile(state, data, 0600); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write run state: %w", err)
+	}
+
+	l := &Log{file: file, state: state}
+	l.Printf("▶ %s (%s, started %s)\n", task.Title, project, run.StartedAt.Format("2006-01-02 15:04:05"))
+	return l, nil
+}
+
+// Write は出力を追記する（複数のゴルーチンから呼び出せる）
+func (l *Log) Write(p []byte) (int, error) {
+	if l == nil {
+		return len(p), nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Write(p)
+}
+
+// Printf は書式付きで出力を追記する
+func (l *Log) Printf(format string, args ...any) {
+	fmt.Fprintf(l, format, args...)
+}
+
+// Finish は実行結果を追記し、実行中の記録を削除する
+func (l *Log) Finish(exec *domain.Execution) error {
+	if l == nil {
+		return nil
+	}
+	if exec != nil {
+		if exec.Success {
+			l.Printf("✅ Completed (%.1fs, $%.2f)\n", exec.Duration.Seconds(), exec.CostUSD)
+		} else {
+			l.Printf("❌ Failed (%.1fs): %s\n", exec.Duration.Seconds(), exec.Error)
+		}
+	}
+	return l.Close()
+}
+
+// Close は実行中の記録を削除する（ログは残す）。複数回呼び出してもよい
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if err := os.Remove(l.state); err != nil && !os.IsNotExist(err) {
+		l.file.Close()
+		return fmt.Errorf("failed to remove run state: %w", err)
+	}
+	return l.file.Close()
+}
+
+// Running は実行中のタスクを開始順に返す
+// 異常終了したプロセスの記録は削除する
+func Running() ([]Run, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	var runs []Run
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var r Run
+		if err := json.Unmarshal(data, &r); err != nil {
+			continue
+		}
+		if !alive(r.PID) {
+			_ = os.Remove(path)
+			continue
+		}
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	return runs, nil
+}
+
+// Tail はファイルの末尾 n 行を返す（ファイルがなければ空）
+func Tail(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open run log: %w", err)
+	}
+	defer f.Close()
+
+	// 大きなログは末尾だけを読む
+	const maxRead = 256 * 1024
+	if info, err := f.Stat(); err == nil && info.Size() > maxRead {
+		if _, err := f.Seek(-maxRead, io.SeekEnd); err != nil {
+			return nil, fmt.Errorf("failed to read run log: %w", err)
+		}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run log: %w", err)
+	}
+	return lines, nil
+}
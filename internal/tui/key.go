@@ -0,0 +1,94 @@
+package tui
+
+import "unicode/utf8"
+
+// Key は押されたキー（文字キーはその文字、特殊キーは名前）
+type Key string
+
+// 特殊キー
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+	KeyEnter     Key = "enter"
+	KeyTab       Key = "tab"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+// escapeKeys はエスケープシーケンスとキーの対応
+var escapeKeys = map[string]Key{
+	"\x1b[A": KeyUp, "\x1b[B": KeyDown, "\x1b[C": KeyRight, "\x1b[D": KeyLeft,
+	"\x1bOA": KeyUp, "\x1bOB": KeyDown, "\x1bOC": KeyRight, "\x1bOD": KeyLeft,
+	"\x1b[H": KeyHome, "\x1b[F": KeyEnd, "\x1bOH": KeyHome, "\x1bOF": KeyEnd,
+	"\x1b[1~": KeyHome, "\x1b[4~": KeyEnd,
+	"\x1b[5~": KeyPageUp, "\x1b[6~": KeyPageDown,
+}
+
+// parseKeys は一度に読み込んだ入力をキーの列に変換する
+// 単独の ESC は Esc キー、未知のエスケープシーケンスは読み捨てる
+func parseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 {
+				keys = append(keys, KeyEsc)
+				b = b[1:]
+				continue
+			}
+			n := escapeLen(b)
+			if k, ok := escapeKeys[string(b[:n])]; ok {
+				keys = append(keys, k)
+			}
+			b = b[n:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, KeyEnter)
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, KeyTab)
+			b = b[1:]
+		case c == 0x03:
+			keys = append(keys, KeyCtrlC)
+			b = b[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, KeyBackspace)
+			b = b[1:]
+		case c < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys = append(keys, Key(string(r)))
+			b = b[size:]
+		}
+	}
+	return keys
+}
+
+// escapeLen は b の先頭のエスケープシーケンスの長さを返す
+func escapeLen(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+	switch b[1] {
+	case '[':
+		// CSI: パラメータの後の 0x40-0x7e で終わる
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	case 'O':
+		return min(3, len(b))
+	default:
+		// Alt+キーなど: ESC とその次の1文字
+		return 2
+	}
+}
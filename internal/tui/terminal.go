@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// 画面制御のエスケープシーケンス
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	cursorHome   = "\x1b[H"
+	clearLine    = "\x1b[K"
+	clearBelow   = "\x1b[J"
+)
+
+// Terminal は全画面表示の端末（rawモード・代替スクリーン）
+type Terminal struct {
+	saved  string // stty -g で保存した端末設定
+	out    *bufio.Writer
+	keys   chan Key
+	width  int // 最後に Size で取得した幅
+	height int // 最後に Size で取得した高さ
+}
+
+// Open は端末をrawモードにして代替スクリーンに切り替える
+// 標準入出力が端末でない場合や、stty のないOSではエラーを返す
+func Open() (*Terminal, error) {
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("the terminal UI is not supported on Windows")
+	}
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return nil, fmt.Errorf("the terminal UI requires an interactive terminal")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("failed to set raw mode: %w", err)
+	}
+
+	t := &Terminal{
+		saved: strings.TrimSpace(saved),
+		out:   bufio.NewWriterSize(os.Stdout, 64*1024),
+		keys:  make(chan Key, 16),
+	}
+	t.out.WriteString(altScreenOn + cursorHide)
+	t.out.Flush()
+	go t.readKeys()
+	return t, nil
+}
+
+// Close は端末を元の状態に戻す
+func (t *Terminal) Close() error {
+	t.out.WriteString(cursorShow + altScreenOff)
+	t.out.Flush()
+	if _, err := stty(t.saved); err != nil {
+		return fmt.Errorf("failed to restore terminal settings: %w", err)
+	}
+	return nil
+}
+
+// Keys は押されたキーを返すチャネル（標準入力が閉じられると閉じる）
+func (t *Terminal) Keys() <-chan Key {
+	return t.keys
+}
+
+// Size は端末の幅と高さを返す（取得できなければ 80x24）
+func (t *Terminal) Size() (width, height int) {
+	t.width, t.height = 80, 24
+	out, err := stty("size")
+	if err == nil {
+		fields := strings.Fields(out)
+		if len(fields) == 2 {
+			rows, err1 := strconv.Atoi(fields[0])
+			cols, err2 := strconv.Atoi(fields[1])
+			if err1 == nil && err2 == nil && rows > 0 && cols > 0 {
+				t.width, t.height = cols, rows
+			}
+		}
+	}
+	return t.width, t.height
+}
+
+// Draw は画面全体を lines で描き直す
+// 各行は最後に Size で取得した幅に切り詰め、高さを超えた行は描かない
+func (t *Terminal) Draw(lines []string) {
+	if t.width == 0 {
+		t.Size()
+	}
+	width, height := t.width, t.height
+	t.out.WriteString(cursorHome)
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		if i > 0 {
+			t.out.WriteString("\r\n")
+		}
+		t.out.WriteString(Fit(line, width))
+		t.out.WriteString(clearLine)
+	}
+	t.out.WriteString(clearBelow)
+	t.out.Flush()
+}
+
+// readKeys は標準入力を読み、キーに変換して送る
+func (t *Terminal) readKeys() {
+	defer close(t.keys)
+	buf := make([]byte, 256)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			t.keys <- k
+		}
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// stty は端末設定を変更・取得する
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 文字の装飾
+const (
+	Reset   = "\x1b[0m"
+	Bold    = "\x1b[1m"
+	Dim     = "\x1b[2m"
+	Reverse = "\x1b[7m"
+	Red     = "\x1b[31m"
+	Green   = "\x1b[32m"
+	Yellow  = "\x1b[33m"
+	Cyan    = "\x1b[36m"
+)
+
+// Style は s を装飾して返す
+func Style(s string, styles ...string) string {
+	if len(styles) == 0 {
+		return s
+	}
+	return strings.Join(styles, "") + s + Reset
+}
+
+// Width は s の表示幅を返す（エスケープシーケンスは幅0、全角文字は幅2）
+func Width(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if n := escapeAt(s, i); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += RuneWidth(r)
+		i += size
+	}
+	return w
+}
+
+// Fit は s を表示幅 width ちょうどに切り詰める・空白で埋める
+// 装飾の途中で切り詰めた場合は装飾を元に戻す
+func Fit(s string, width int) string {
+	var b strings.Builder
+	w, styled := 0, false
+	for i := 0; i < len(s); {
+		if n := escapeAt(s, i); n > 0 {
+			b.WriteString(s[i : i+n])
+			styled = s[i:i+n] != Reset
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := RuneWidth(r)
+		if w+rw > width {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+		i += size
+	}
+	if styled {
+		b.WriteString(Reset)
+	}
+	if w < width {
+		b.WriteString(strings.Repeat(" ", width-w))
+	}
+	return b.String()
+}
+
+// Truncate は s を表示幅 width 以内に切り詰める（切り詰めた場合は末尾を … にする）
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	return strings.TrimRight(Fit(s, width-1), " ") + "…"
+}
+
+// Wrap は装飾のない s を表示幅 width で折り返す
+// 可能なら空白で折り返し、タブは空白にする
+func Wrap(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\t", "    "), "\n") {
+		para = strings.TrimRight(para, "\r ")
+		if para == "" {
+			lines = append(lines, "")
+			continue
+		}
+		for para != "" {
+			line, rest := cut(para, width)
+			lines = append(lines, line)
+			para = rest
+		}
+	}
+	return lines
+}
+
+// cut は s の先頭から表示幅 width までを切り出す
+func cut(s string, width int) (line, rest string) {
+	w, lastSpace := 0, -1
+	for i, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > width {
+			if lastSpace > 0 {
+				return s[:lastSpace], strings.TrimLeft(s[lastSpace:], " ")
+			}
+			if i == 0 {
+				// 幅1に全角文字が入らない場合も1文字は進める
+				_, size := utf8.DecodeRuneInString(s)
+				return s[:size], s[size:]
+			}
+			return s[:i], s[i:]
+		}
+		if r == ' ' {
+			lastSpace = i
+		}
+		w += rw
+	}
+	return s, ""
+}
+
+// RuneWidth は文字の表示幅を返す
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || r == 0x200B:
+		return 0
+	case r < 0x1100:
+		return 1
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// wideRanges は全角で表示される文字の範囲（East Asian Wide/Fullwidth と主な絵文字）
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0}, {0x23F3, 0x23F3},
+	{0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693},
+	{0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5}, {0x26FA, 0x26FA},
+	{0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728}, {0x274C, 0x274C},
+	{0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0},
+	{0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A}, {0x1F200, 0x1F251}, {0x1F300, 0x1F64F}, {0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x3FFFD},
+}
+
+func isWide(r rune) bool {
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			return false
+		}
+		if r <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeAt は s[i:] がCSIエスケープシーケンスで始まればその長さを返す
+func escapeAt(s string, i int) int {
+	if s[i] != 0x1b || i+1 >= len(s) || s[i+1] != '[' {
+		return 0
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j - i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// ExceededError は予算を使い切ったことを表すエラー
+type ExceededError struct {
+	Scope  string // "daily" / "project"
+	Reason string
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s budget exhausted: %s", e.Scope, e.Reason)
+}
+
+// Budget は設定された予算と実行記録を照合する
+type Budget struct {
+	cfg     config.BudgetConfig
+	ledger  *Ledger
+	project string
+}
+
+// NewBudget は新しいBudgetを作成する
+func NewBudget(cfg config.BudgetConfig, ledger *Ledger, project string) *Budget {
+	return &Budget{
+		cfg:     cfg,
+		ledger:  ledger,
+		project: project,
+	}
+}
+
+// Check は日次・プロジェクト予算が残っているか確認する
+// Reserve で予約した実行中の実行は、1回あたりの見積もり（estimate）を合計に加えて確認する
+// 使い切っている場合は *ExceededError を返す
+func (b *Budget) Check(now time.Time) error {
+	b.ledger.mu.Lock()
+	defer b.ledger.mu.Unlock()
+	return b.check(now)
+}
+
+// Reserve は予算が残っていれば実行中の1回として予約し、予約を解放する関数を返す
+// 並行して実行するタスクが全員同じ残りを見て予算を超えないよう、確認と予約を排他的に行う
+// 解放関数は実行を Record で記録した後に呼ぶ。見積もりを超える実行があれば、その分だけ予算を超えることがある
+func (b *Budget) Reserve(now time.Time) (func(), error) {
+	b.ledger.mu.Lock()
+	defer b.ledger.mu.Unlock()
+	if err := b.check(now); err != nil {
+		return nil, err
+	}
+
+	if b.ledger.inFlight == nil {
+		b.ledger.inFlight = make(map[string]int)
+	}
+	b.ledger.inFlight[b.project]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.ledger.mu.Lock()
+			defer b.ledger.mu.Unlock()
+			b.ledger.inFlight[b.project]--
+		})
+	}, nil
+}
+
+// check は Check の本体（b.ledger.mu をロックして呼ぶ）
+func (b *Budget) check(now time.Time) error {
+	if b.cfg.Daily.IsZero() && b.cfg.Project.IsZero() {
+		return nil
+	}
+
+	records, err := b.ledger.Records()
+	if err != nil {
+		return err
+	}
+
+	running := 0
+	for _, n := range b.ledger.inFlight {
+		running += n
+	}
+	today := now.Local().Format("2006-01-02")
+	daily := Sum(records, func(r Record) bool { return Day(r) == today })
+	if reason := exceeded(b.cfg.Daily, b.estimate(daily, running)); reason != "" {
+		return &ExceededError{Scope: "daily", Reason: withRunning(reason, running)}
+	}
+
+	running = b.ledger.inFlight[b.project]
+	project := Sum(records, func(r Record) bool { return r.Project == b.project })
+	if reason := exceeded(b.cfg.Project, b.estimate(project, running)); reason != "" {
+		return &ExceededError{Scope: "project", Reason: withRunning(reason, running)}
+	}
+
+	return nil
+}
+
+// estimate は合計に実行中の running 回分の見積もりを加える
+// 1回分はタスクごとの上限（設定されていれば）、なければこれまでの1回あたりの平均とする
+func (b *Budget) estimate(total Total, running int) Total {
+	if running == 0 {
+		return total
+	}
+	var per Total
+	if total.Runs > 0 {
+		per = Total{
+			CostUSD:  total.CostUSD / float64(total.Runs),
+			Tokens:   total.Tokens / total.Runs,
+			Duration: total.Duration / time.Duration(total.Runs),
+		}
+	}
+	if limit := b.cfg.PerTask; limit.USD > 0 {
+		per.CostUSD = limit.USD
+	}
+	if limit := b.cfg.PerTask; limit.Tokens > 0 {
+		per.Tokens = limit.Tokens
+	}
+	if limit := b.cfg.PerTask; limit.Time.Duration > 0 {
+		per.Duration = limit.Time.Duration
+	}
+	total.Runs += running
+	total.CostUSD += per.CostUSD * float64(running)
+	total.Tokens += per.Tokens * running
+	total.Duration += per.Duration * time.Duration(running)
+	return total
+}
+
+// withRunning は理由に実行中の見積もりを含むことを書き添える
+func withRunning(reason string, running int) string {
+	if running == 0 {
+		return reason
+	}
+	return fmt.Sprintf("%s (including an estimate for %d running task(s))", reason, running)
+}
+
+// TaskTimeout はタスクごとの実行時間上限を考慮したタイムアウトを返す
+func (b *Budget) TaskTimeout(timeout time.Duration) time.Duration {
+	if limit := b.cfg.PerTask.Time.Duration; limit > 0 && (timeout == 0 || limit < timeout) {
+		return limit
+	}
+	return timeout
+}
+
+// CheckTask は実行結果がタスクごとの予算を超えていれば理由を返す
+func (b *Budget) CheckTask(exec *domain.Execution) string {
+	return exceeded(b.cfg.PerTask, Total{
+		CostUSD:  exec.CostUSD,
+		Tokens:   exec.Tokens(),
+		Duration: exec.Duration,
+	})
+}
+
+// Record は実行結果を記録する
+func (b *Budget) Record(task *domain.Task, exec *domain.Execution) error {
+	return b.ledger.Append(NewRecord(b.project, task, exec))
+}
+
+// exceeded は合計が上限に達していれば理由を返す
+func exceeded(limit config.BudgetLimit, total Total) string {
+	var reasons []string
+	if limit.USD > 0 && total.CostUSD >= limit.USD {
+		reasons = append(reasons, fmt.Sprintf("$%.2f of $%.2f", total.CostUSD, limit.USD))
+	}
+	if limit.Tokens > 0 && total.Tokens >= limit.Tokens {
+		reasons = append(reasons, fmt.Sprintf("%d of %d tokens", total.Tokens, limit.Tokens))
+	}
+	if limit.Time.Duration > 0 && total.Duration >= limit.Time.Duration {
+		reasons = append(reasons, fmt.Sprintf("%s of %s", total.Duration.Round(time.Second), limit.Time.Duration))
+	}
+	return strings.Join(reasons, ", ")
+}
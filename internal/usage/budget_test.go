@@ -0,0 +1,207 @@
+package usage
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// testLedger は一時ディレクトリに records を書き込んだ Ledger を作成する
+func testLedger(t *testing.T, records ...Record) *Ledger {
+	t.Helper()
+	l := &Ledger{path: filepath.Join(t.TempDir(), ledgerFileName)}
+	for _, r := range records {
+		if err := l.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestExceeded(t *testing.T) {
+	limit := config.BudgetLimit{USD: 5, Tokens: 1000, Time: config.Duration{Duration: time.Minute}}
+	tests := []struct {
+		name  string
+		limit config.BudgetLimit
+		total Total
+		want  string
+	}{
+		{name: "no limit", total: Total{CostUSD: 100}},
+		{name: "below every limit", limit: limit, total: Total{CostUSD: 4.99, Tokens: 999, Duration: 59 * time.Second}},
+		{name: "reaching the limit counts as exceeded", limit: limit, total: Total{CostUSD: 5}, want: "$5.00 of $5.00"},
+		{name: "over the limit", limit: limit, total: Total{CostUSD: 7.5}, want: "$7.50 of $5.00"},
+		{name: "tokens", limit: limit, total: Total{Tokens: 1200}, want: "1200 of 1000 tokens"},
+		{name: "time", limit: limit, total: Total{Duration: 90 * time.Second}, want: "1m30s of 1m0s"},
+		{
+			name:  "every exceeded limit is listed",
+			limit: limit,
+			total: Total{CostUSD: 6, Tokens: 1000, Duration: time.Minute},
+			want:  "$6.00 of $5.00, 1000 of 1000 tokens, 1m0s of 1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceeded(tt.limit, tt.total); got != tt.want {
+				t.Errorf("exceeded() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	past := Total{Runs: 4, CostUSD: 8, Tokens: 400, Duration: 4 * time.Minute}
+	tests := []struct {
+		name    string
+		perTask config.BudgetLimit
+		total   Total
+		running int
+		want    Total
+	}{
+		{name: "nothing running", total: past, want: past},
+		{
+			name:    "average of past runs",
+			total:   past,
+			running: 2,
+			want:    Total{Runs: 6, CostUSD: 12, Tokens: 600, Duration: 6 * time.Minute},
+		},
+		{
+			name:    "per-task limit wins over the average",
+			perTask: config.BudgetLimit{USD: 5, Tokens: 1000},
+			total:   past,
+			running: 1,
+			want:    Total{Runs: 5, CostUSD: 13, Tokens: 1400, Duration: 5 * time.Minute},
+		},
+		{
+			name:    "no history and no limit adds nothing",
+			running: 3,
+			want:    Total{Runs: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBudget(config.BudgetConfig{PerTask: tt.perTask}, nil, "o/#1")
+			if got := b.estimate(tt.total, tt.running); got != tt.want {
+				t.Errorf("estimate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	records := []Record{
+		{Time: yesterday, Project: "o/#1", CostUSD: 6},
+		{Time: now, Project: "o/#1", CostUSD: 3},
+		{Time: now, Project: "o/#2", CostUSD: 1},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       config.BudgetConfig
+		project   string
+		wantScope string // 空なら予算内
+	}{
+		{name: "no budget", project: "o/#1"},
+		{name: "daily budget left", cfg: config.BudgetConfig{Daily: config.BudgetLimit{USD: 5}}, project: "o/#1"},
+		{name: "daily budget counts every project", cfg: config.BudgetConfig{Daily: config.BudgetLimit{USD: 4}}, project: "o/#2", wantScope: "daily"},
+		{name: "project budget counts every day", cfg: config.BudgetConfig{Project: config.BudgetLimit{USD: 9}}, project: "o/#1", wantScope: "project"},
+		{name: "other project is within its budget", cfg: config.BudgetConfig{Project: config.BudgetLimit{USD: 9}}, project: "o/#2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewBudget(tt.cfg, testLedger(t, records...), tt.project).Check(now)
+			var exceeded *ExceededError
+			switch {
+			case tt.wantScope == "" && err != nil:
+				t.Errorf("Check() = %v, want nil", err)
+			case tt.wantScope != "" && !errors.As(err, &exceeded):
+				t.Errorf("Check() = %v, want *ExceededError", err)
+			case tt.wantScope != "" && exceeded.Scope != tt.wantScope:
+				t.Errorf("Check() scope = %q, want %q", exceeded.Scope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestReserveConcurrent(t *testing.T) {
+	// $3 の見積もりで $10 の予算なら、実行中が 0, 1, 2, 3 件のときだけ予約できる
+	cfg := config.BudgetConfig{
+		PerTask: config.BudgetLimit{USD: 3},
+		Daily:   config.BudgetLimit{USD: 10},
+	}
+	b := NewBudget(cfg, testLedger(t), "o/#1")
+
+	const workers = 10
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		releases []func()
+		refused  int
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := b.Reserve(time.Now())
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				refused++
+				return
+			}
+			releases = append(releases, release)
+		}()
+	}
+	wg.Wait()
+
+	if len(releases) != 4 || refused != workers-4 {
+		t.Fatalf("reserved %d and refused %d, want 4 and %d", len(releases), refused, workers-4)
+	}
+
+	// 解放は何度呼んでも1回分だけ戻す
+	releases[0]()
+	releases[0]()
+	if got := b.ledger.inFlight["o/#1"]; got != 3 {
+		t.Errorf("in flight after releasing one = %d, want 3", got)
+	}
+	if _, err := b.Reserve(time.Now()); err != nil {
+		t.Errorf("Reserve() after a release = %v, want nil", err)
+	}
+	if _, err := b.Reserve(time.Now()); err == nil {
+		t.Error("Reserve() over the budget = nil, want an error")
+	}
+}
+
+func TestTaskBudget(t *testing.T) {
+	b := NewBudget(config.BudgetConfig{PerTask: config.BudgetLimit{USD: 1, Time: config.Duration{Duration: 10 * time.Minute}}}, nil, "o/#1")
+
+	tests := []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{timeout: 0, want: 10 * time.Minute},
+		{timeout: 5 * time.Minute, want: 5 * time.Minute},
+		{timeout: time.Hour, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := b.TaskTimeout(tt.timeout); got != tt.want {
+			t.Errorf("TaskTimeout(%s) = %s, want %s", tt.timeout, got, tt.want)
+		}
+	}
+
+	if got := b.CheckTask(&domain.Execution{CostUSD: 0.5}); got != "" {
+		t.Errorf("CheckTask() under the limit = %q, want empty", got)
+	}
+	if got := b.CheckTask(&domain.Execution{CostUSD: 1.2}); got != "$1.20 of $1.00" {
+		t.Errorf("CheckTask() over the limit = %q, want %q", got, "$1.20 of $1.00")
+	}
+}
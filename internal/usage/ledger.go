@@ -0,0 +1,184 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tkc/vibe-project/internal/config"
+	"github.com/tkc/vibe-project/internal/domain"
+)
+
+// ledgerFileName は実行記録ファイル名
+const ledgerFileName = "usage.jsonl"
+
+// Record は1回の実行にかかったコストの記録
+type Record struct {
+	Time     time.Time     `json:"time"`
+	Project  string        `json:"project"` // owner/#number
+	TaskID   string        `json:"task_id"`
+	Title    string        `json:"title"`
+	Repo     string        `json:"repo,omitempty"` // owner/repo
+	CostUSD  float64       `json:"cost_usd"`
+	Tokens   int           `json:"tokens"`
+	Duration time.Duration `json:"duration"`
+	Success  bool          `json:"success"`
+}
+
+// Ledger は ~/.vibe/usage.jsonl に実行記録を追記・集計する
+type Ledger struct {
+	path string
+
+	mu       sync.Mutex
+	inFlight map[string]int // Budget.Reserve で予約した実行中（未記録）の実行数（Projectごと）
+}
+
+// NewLedger はデフォルトの場所の Ledger を作成する
+func NewLedger() (*Ledger, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Ledger{path: filepath.Join(dir, ledgerFileName)}, nil
+}
+
+// ProjectKey はプロジェクトを識別するキーを返す
+func ProjectKey(owner string, number int) string {
+	return fmt.Sprintf("%s/#%d", owner, number)
+}
+
+// NewRecord は実行結果から記録を作成する
+func NewRecord(project string, task *domain.Task, exec *domain.Execution) Record {
+	return Record{
+		Time:     exec.EndedAt,
+		Project:  project,
+		TaskID:   task.ID,
+		Title:    task.Title,
+		Repo:     repoFromURL(task.IssueURL),
+		CostUSD:  exec.CostUSD,
+		Tokens:   exec.Tokens(),
+		Duration: exec.Duration,
+		Success:  exec.Success,
+	}
+}
+
+// Append は記録を追記する
+func (l *Ledger) Append(r Record) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create usage dir: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+	return nil
+}
+
+// Records は全記録を読み込む
+func (l *Ledger) Records() ([]Record, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			// 壊れた行は読み飛ばす
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+	return records, nil
+}
+
+// Total は集計結果
+type Total struct {
+	Key      string
+	Runs     int
+	CostUSD  float64
+	Tokens   int
+	Duration time.Duration
+}
+
+func (t *Total) add(r Record) {
+	t.Runs++
+	t.CostUSD += r.CostUSD
+	t.Tokens += r.Tokens
+	t.Duration += r.Duration
+}
+
+// Sum は条件に一致する記録を合計する
+func Sum(records []Record, match func(Record) bool) Total {
+	var t Total
+	for _, r := range records {
+		if match == nil || match(r) {
+			t.add(r)
+		}
+	}
+	return t
+}
+
+// GroupBy は記録をキーごとに集計し、キー順に並べて返す
+func GroupBy(records []Record, key func(Record) string) []Total {
+	totals := make(map[string]*Total)
+	for _, r := range records {
+		k := key(r)
+		t, ok := totals[k]
+		if !ok {
+			t = &Total{Key: k}
+			totals[k] = t
+		}
+		t.add(r)
+	}
+
+	result := make([]Total, 0, len(totals))
+	for _, t := range totals {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Day は記録の日付キー (YYYY-MM-DD, ローカル時刻) を返す
+func Day(r Record) string {
+	return r.Time.Local().Format("2006-01-02")
+}
+
+// repoFromURL はIssue URLから owner/repo を抽出する
+func repoFromURL(issueURL string) string {
+	parts := strings.Split(strings.TrimPrefix(issueURL, "https://github.com/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}